@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henri123lemoine/grove/internal/app"
+	"github.com/henri123lemoine/grove/internal/config"
+	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
+)
+
+// runAdopt implements "grove adopt": repair worktrees that were moved by
+// hand and relocate ad-hoc worktrees into the configured layout.
+func runAdopt(args []string) int {
+	fs := flag.NewFlagSet("adopt", flag.ContinueOnError)
+	yes := fs.Bool("y", false, "Apply every change without asking")
+	dryRun := fs.Bool("n", false, "Only report what would change")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, `Usage:
+  grove adopt [-y] [-n] [search-dir...]
+
+Finds worktrees whose metadata is broken (prunable) and, when their
+directory was moved, repairs them with git worktree repair. Then offers to
+move worktrees that live outside worktree_dir with git worktree move.
+Moved worktrees are searched for in worktree_dir, next to the repository
+and in any search-dir given.
+
+Flags:
+  -y  Apply every change without asking
+  -n  Only report what would change`)
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	repo, err := git.GetRepo()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	confirm := func(format string, a ...any) bool {
+		fmt.Printf(format+" [y/N] ", a...)
+		switch {
+		case *dryRun:
+			fmt.Println("n (dry run)")
+			return false
+		case *yes:
+			fmt.Println("y")
+			return true
+		}
+		answer, _ := in.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+
+	worktrees, err := git.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// 1. Broken metadata
+	worktreeDir := filepath.Join(repo.MainWorktreeRoot, cfg.General.WorktreeDir)
+	searchDirs := append([]string{worktreeDir, filepath.Dir(repo.MainWorktreeRoot)}, fs.Args()...)
+	if repaired, ok := adoptPrunable(worktrees, searchDirs, confirm); !ok {
+		return 1
+	} else if repaired {
+		if worktrees, err = git.List(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// 2. Worktrees outside worktree_dir
+	status := 0
+	found := false
+	for _, wt := range worktrees {
+		if wt.IsMain || wt.IsBare || wt.IsPrunable {
+			continue
+		}
+		name := wt.Branch
+		if wt.IsDetached {
+			name = "detached/" + strings.TrimSuffix(wt.Branch, " (detached)")
+		}
+		target := app.DefaultWorktreePath(cfg, name)
+		if inLayout(cfg, wt.Path, worktreeDir, target) {
+			continue
+		}
+
+		if !found {
+			fmt.Println("Worktrees outside worktree_dir:")
+			found = true
+		}
+		switch {
+		case wt.IsLocked:
+			fmt.Printf("  %s is locked, skipping\n", wt.Path)
+			continue
+		case exists(target):
+			fmt.Printf("  %s: %s already exists, skipping\n", wt.Path, target)
+			continue
+		}
+
+		if !confirm("  Move %s -> %s?", wt.Path, target) {
+			continue
+		}
+		if err := git.Move(wt.Path, target); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			status = 1
+			continue
+		}
+		if err := state.MoveWorktree(wt.Path, target); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not move grove state for %s: %v\n", target, err)
+		}
+	}
+	if !found {
+		fmt.Println("All worktrees are inside worktree_dir.")
+	}
+	return status
+}
+
+// adoptPrunable reports prunable worktrees and repairs the ones found at a
+// new location. It returns whether anything was repaired.
+func adoptPrunable(worktrees []git.Worktree, searchDirs []string, confirm func(string, ...any) bool) (repaired, ok bool) {
+	var prunable []git.Worktree
+	for _, wt := range worktrees {
+		if wt.IsPrunable {
+			prunable = append(prunable, wt)
+		}
+	}
+	if len(prunable) == 0 {
+		return false, true
+	}
+
+	fmt.Println("Broken worktree metadata:")
+	for _, wt := range prunable {
+		fmt.Printf("  %s (%s): %s\n", wt.Path, wt.Branch, wt.PrunableReason)
+	}
+
+	moved := git.FindMovedWorktrees(worktrees, searchDirs, 4)
+	for _, m := range moved {
+		fmt.Printf("  %s was moved to %s\n", m.OldPath, m.NewPath)
+	}
+	if len(moved) > 0 && confirm("Repair %d moved worktrees?", len(moved)) {
+		paths := make([]string, len(moved))
+		for i, m := range moved {
+			paths[i] = m.NewPath
+		}
+		if err := git.RepairWorktrees(paths); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false, false
+		}
+		repaired = true
+		// Per-worktree state follows only once git knows the new location
+		for _, m := range moved {
+			if err := state.MoveWorktree(m.OldPath, m.NewPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not move grove state for %s: %v\n", m.NewPath, err)
+			}
+		}
+	}
+
+	if len(prunable) > len(moved) || !repaired {
+		fmt.Println("Worktrees that no longer exist can be removed with: git worktree prune")
+	}
+	fmt.Println()
+	return repaired, true
+}
+
+// inLayout reports whether a worktree already sits where grove puts
+// worktrees: inside worktree_dir or, with a worktree_path template, at its
+// expected path.
+func inLayout(cfg *config.Config, path, worktreeDir, target string) bool {
+	path = git.ResolvePath(path)
+	if cfg.General.WorktreePath != "" {
+		return path == git.ResolvePath(target)
+	}
+	rel, err := filepath.Rel(git.ResolvePath(worktreeDir), path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/henri123lemoine/grove/internal/app"
+	"github.com/henri123lemoine/grove/internal/config"
+	"github.com/henri123lemoine/grove/internal/git"
+)
+
+// runClone implements "grove clone <url|path> [dir]": a bare clone in
+// dir/.bare with a worktree for the default branch.
+func runClone(args []string) int {
+	fs := flag.NewFlagSet("clone", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, `Usage:
+  grove clone <url|path> [dir]
+
+Clones into dir/.bare, points dir/.git at it and creates a worktree for
+the default branch. dir defaults to the repository name.`)
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+
+	source := fs.Arg(0)
+	dir := fs.Arg(1)
+	if dir == "" {
+		dir = git.CloneDir(source)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Cloning %s into %s\n", source, filepath.Join(dir, git.BareDirName))
+	branch, err := git.CloneBare(source, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Worktree paths are resolved against the current repository
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	git.ResetRepo()
+
+	path := app.DefaultWorktreePath(cfg, branch)
+	if err := git.Create(path, branch, false, "", nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	// The same setup the TUI runs after creating a worktree
+	if cfg.Worktree.InitSubmodules {
+		if err := git.UpdateSubmodules(path, cfg.Worktree.SubmoduleReference); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if cfg.Ports.Enabled {
+		if _, err := app.AllocatePorts(cfg, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not allocate ports: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Created worktree for %s at %s\n", branch, path)
+	return 0
+}
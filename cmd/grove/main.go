@@ -51,6 +51,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands
+	switch flag.Arg(0) {
+	case "clone":
+		os.Exit(runClone(flag.Args()[1:]))
+	case "adopt":
+		os.Exit(runAdopt(flag.Args()[1:]))
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -62,7 +70,7 @@ func main() {
 	configWarnings := cfg.Validate()
 
 	// Initialize theme based on config
-	ui.InitTheme(cfg.UI.Theme)
+	ui.InitTheme(cfg)
 
 	// Handle first-run experience
 	if config.IsFirstRun() {
@@ -115,6 +123,8 @@ func printUsage() {
 
 Usage:
   grove [flags]
+  grove clone <url|path> [dir]   Bare clone with a worktree-first layout
+  grove adopt [-y] [-n]          Repair moved worktrees, relocate ad-hoc ones
 
 Flags:
   -p, --print-selected  Print selected worktree path on exit
@@ -0,0 +1,465 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
+)
+
+// ActionID identifies a list action. IDs match the [keys] config names.
+type ActionID string
+
+// List actions.
+const (
+	ActionUp        ActionID = "up"
+	ActionDown      ActionID = "down"
+	ActionHome      ActionID = "home"
+	ActionEnd       ActionID = "end"
+	ActionOpen      ActionID = "open"
+	ActionPrevious  ActionID = "previous"
+	ActionNew       ActionID = "new"
+	ActionDelete    ActionID = "delete"
+	ActionRename    ActionID = "rename"
+	ActionMove      ActionID = "move"
+	ActionLock      ActionID = "lock"
+	ActionNote      ActionID = "note"
+	ActionPin       ActionID = "pin"
+	ActionWiden     ActionID = "widen"
+	ActionFetch     ActionID = "fetch"
+	ActionPush      ActionID = "push"
+	ActionMark      ActionID = "mark"
+	ActionPrune     ActionID = "prune"
+	ActionPruneGone ActionID = "prune_gone"
+	ActionStash     ActionID = "stash"
+	ActionBranches  ActionID = "branches"
+	ActionFilter    ActionID = "filter"
+	ActionDetail    ActionID = "detail"
+	ActionSort      ActionID = "sort"
+	ActionTree      ActionID = "tree"
+	ActionCollapse  ActionID = "collapse"
+	ActionExpand    ActionID = "expand"
+	ActionPalette   ActionID = "palette"
+	ActionHelp      ActionID = "help"
+	ActionQuit      ActionID = "quit"
+)
+
+// action describes a list action for key dispatch, the help screen and the
+// command palette.
+type action struct {
+	ID      ActionID
+	Section string // Help section
+	Desc    string
+
+	// treeOnly actions only exist in tree mode, so their keys are free
+	// for other uses otherwise.
+	treeOnly bool
+
+	// hidden keeps cursor movement and the palette itself out of the palette.
+	hidden bool
+
+	// available reports whether the action makes sense for the current
+	// selection; the palette hides it otherwise. nil means always. Keys
+	// still run unavailable actions so they can explain what's wrong.
+	available func(m *Model) bool
+}
+
+// actions lists every list action in help order.
+var actions = []action{
+	{ID: ActionUp, Section: "Navigation", hidden: true, Desc: "Move up"},
+	{ID: ActionDown, Section: "Navigation", hidden: true, Desc: "Move down"},
+	{ID: ActionHome, Section: "Navigation", hidden: true, Desc: "Go to first"},
+	{ID: ActionEnd, Section: "Navigation", hidden: true, Desc: "Go to last"},
+	{ID: ActionOpen, Section: "Navigation", Desc: "Open worktree", available: hasCursorRow},
+	{ID: ActionPrevious, Section: "Navigation", Desc: "Open previously used worktree", available: func(m *Model) bool {
+		return m.previousWorktree() != nil
+	}},
+
+	{ID: ActionNew, Section: "Actions", Desc: "New worktree"},
+	{ID: ActionDelete, Section: "Actions", Desc: "Delete worktree", available: cursorWorktreeIs(func(wt *git.Worktree) bool {
+		return !wt.IsMain && !wt.IsLocked
+	})},
+	{ID: ActionRename, Section: "Actions", Desc: "Rename branch (detached: convert to branch)", available: cursorWorktreeIs(notMain)},
+	{ID: ActionMove, Section: "Actions", Desc: "Move worktree directory", available: cursorWorktreeIs(func(wt *git.Worktree) bool {
+		return !wt.IsMain && !wt.IsLocked
+	})},
+	{ID: ActionLock, Section: "Actions", Desc: "Lock / unlock worktree", available: cursorWorktreeIs(notMain)},
+	{ID: ActionNote, Section: "Actions", Desc: "Edit note and #labels", available: cursorWorktreeIs(nil)},
+	{ID: ActionPin, Section: "Actions", Desc: "Pin / unpin worktree (kept on top)", available: cursorWorktreeIs(nil)},
+	{ID: ActionWiden, Section: "Actions", Desc: "Widen sparse checkout", available: cursorWorktreeIs(func(wt *git.Worktree) bool {
+		return wt.Sparse != nil
+	})},
+	{ID: ActionFetch, Section: "Actions", Desc: "Fetch all remotes"},
+	{ID: ActionPush, Section: "Actions", Desc: "Push branch (sets upstream if missing)", available: func(m *Model) bool {
+		for _, wt := range m.actionTargets() {
+			if !wt.IsDetached && wt.Branch != "" {
+				return true
+			}
+		}
+		return false
+	}},
+	{ID: ActionMark, Section: "Actions", Desc: "Mark worktree for bulk actions", available: cursorWorktreeIs(nil)},
+	{ID: ActionPrune, Section: "Actions", Desc: "Prune stale worktrees"},
+	{ID: ActionPruneGone, Section: "Actions", Desc: "Prune branches whose upstream is gone"},
+	{ID: ActionStash, Section: "Actions", Desc: "Manage stashes", available: cursorWorktreeIs(nil)},
+	{ID: ActionBranches, Section: "Actions", Desc: "Manage branches without worktrees"},
+	{ID: ActionFilter, Section: "Actions", Desc: "Filter worktrees"},
+	{ID: ActionDetail, Section: "Actions", Desc: "Toggle detail panel"},
+	{ID: ActionSort, Section: "Actions", Desc: "Cycle sort order (recent, last commit, ...)"},
+
+	{ID: ActionTree, Section: "Tree", Desc: "Group worktrees by branch prefix"},
+	{ID: ActionCollapse, Section: "Tree", Desc: "Collapse group / go to parent", treeOnly: true},
+	{ID: ActionExpand, Section: "Tree", Desc: "Expand group", treeOnly: true, available: func(m *Model) bool {
+		return m.cursorGroup() != nil
+	}},
+
+	{ID: ActionPalette, Section: "General", Desc: "Command palette", hidden: true},
+	{ID: ActionHelp, Section: "General", Desc: "Toggle this help"},
+	{ID: ActionQuit, Section: "General", Desc: "Quit"},
+}
+
+func hasCursorRow(m *Model) bool {
+	return m.rowCount() > 0
+}
+
+func notMain(wt *git.Worktree) bool {
+	return !wt.IsMain
+}
+
+// cursorWorktreeIs returns an availability check that passes when a worktree
+// is selected and satisfies ok (any worktree when ok is nil).
+func cursorWorktreeIs(ok func(wt *git.Worktree) bool) func(m *Model) bool {
+	return func(m *Model) bool {
+		wt := m.cursorWorktree()
+		return wt != nil && (ok == nil || ok(wt))
+	}
+}
+
+// Binding returns the key binding of a list action.
+func (km KeyMap) Binding(id ActionID) key.Binding {
+	switch id {
+	case ActionUp:
+		return km.Up
+	case ActionDown:
+		return km.Down
+	case ActionHome:
+		return km.Home
+	case ActionEnd:
+		return km.End
+	case ActionOpen:
+		return km.Open
+	case ActionPrevious:
+		return km.Previous
+	case ActionNew:
+		return km.New
+	case ActionDelete:
+		return km.Delete
+	case ActionRename:
+		return km.Rename
+	case ActionMove:
+		return km.Move
+	case ActionLock:
+		return km.Lock
+	case ActionNote:
+		return km.Note
+	case ActionPin:
+		return km.Pin
+	case ActionWiden:
+		return km.Widen
+	case ActionFetch:
+		return km.Fetch
+	case ActionPush:
+		return km.Push
+	case ActionMark:
+		return km.Mark
+	case ActionPrune:
+		return km.Prune
+	case ActionPruneGone:
+		return km.PruneGone
+	case ActionStash:
+		return km.Stash
+	case ActionBranches:
+		return km.Branches
+	case ActionFilter:
+		return km.Filter
+	case ActionDetail:
+		return km.Detail
+	case ActionSort:
+		return km.Sort
+	case ActionTree:
+		return km.Tree
+	case ActionCollapse:
+		return km.Collapse
+	case ActionExpand:
+		return km.Expand
+	case ActionPalette:
+		return km.Palette
+	case ActionHelp:
+		return km.Help
+	case ActionQuit:
+		return km.Quit
+	}
+	return key.Binding{}
+}
+
+// actionForKey returns the list action bound to msg.
+func (m *Model) actionForKey(msg tea.KeyMsg) (ActionID, bool) {
+	for _, a := range actions {
+		if a.treeOnly && !m.treeView {
+			continue
+		}
+		if key.Matches(msg, m.keys.Binding(a.ID)) {
+			return a.ID, true
+		}
+	}
+	return "", false
+}
+
+// runAction performs a list action on the current selection.
+func (m Model) runAction(id ActionID) (tea.Model, tea.Cmd) {
+	switch id {
+	case ActionUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.ensureCursorVisible()
+		}
+	case ActionDown:
+		if m.cursor < m.rowCount()-1 {
+			m.cursor++
+			m.ensureCursorVisible()
+		}
+	case ActionHome:
+		m.cursor = 0
+		m.viewOffset = 0
+	case ActionEnd:
+		m.cursor = m.rowCount() - 1
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		m.ensureCursorVisible()
+	case ActionOpen:
+		if group := m.cursorGroup(); group != nil {
+			return m, m.setGroupCollapsed(group.Prefix, !group.Collapsed)
+		}
+		if wt := m.cursorWorktree(); wt != nil {
+			return m.openSelected(wt)
+		}
+	case ActionNew:
+		m.state = StateCreate
+		m.createRemote = ""
+		m.createInput.Focus()
+		return m, textinput.Blink
+	case ActionDelete:
+		if wt := m.cursorWorktree(); wt != nil {
+			if wt.IsMain {
+				m.err = fmt.Errorf("cannot delete main worktree")
+				return m, nil
+			}
+			if wt.IsLocked {
+				m.err = fmt.Errorf("worktree is locked%s (unlock it first)", lockReasonSuffix(wt))
+				return m, nil
+			}
+			m.deleteWorktree = wt
+			m.state = StateDelete
+			return m, checkSafety(wt.Path, wt.Branch, m.repo.DefaultBranch)
+		}
+	case ActionRename:
+		if wt := m.cursorWorktree(); wt != nil {
+			if wt.IsMain {
+				m.err = fmt.Errorf("cannot rename main worktree branch")
+				return m, nil
+			}
+			m.renameWorktree = wt
+			// Renaming a detached worktree converts it to a branch
+			if wt.IsDetached {
+				m.renameInput.Reset()
+			} else {
+				m.renameInput.SetValue(wt.Branch)
+			}
+			m.renameInput.Focus()
+			m.state = StateRename
+			return m, textinput.Blink
+		}
+	case ActionLock:
+		if wt := m.cursorWorktree(); wt != nil {
+			if wt.IsMain {
+				m.err = fmt.Errorf("cannot lock main worktree")
+				return m, nil
+			}
+			if wt.IsLocked {
+				return m, unlockWorktree(wt.Path)
+			}
+			m.lockWorktree = wt
+			m.lockInput.Reset()
+			m.lockInput.Focus()
+			m.state = StateLock
+			return m, textinput.Blink
+		}
+	case ActionNote:
+		if wt := m.cursorWorktree(); wt != nil {
+			m.noteWorktree = wt
+			m.noteInput.SetValue(state.Note{Text: wt.Note, Labels: wt.Labels}.String())
+			m.noteInput.CursorEnd()
+			m.noteInput.Focus()
+			m.state = StateNote
+			return m, textinput.Blink
+		}
+	case ActionWiden:
+		if wt := m.cursorWorktree(); wt != nil {
+			if wt.Sparse == nil {
+				m.err = fmt.Errorf("not a sparse checkout")
+				return m, nil
+			}
+			m.widenWorktree = wt
+			m.widenInput.Reset()
+			m.widenInput.Focus()
+			m.state = StateWidenSparse
+			return m, textinput.Blink
+		}
+	case ActionMove:
+		if wt := m.cursorWorktree(); wt != nil {
+			if wt.IsMain {
+				m.err = fmt.Errorf("cannot move main worktree")
+				return m, nil
+			}
+			m.moveWorktree = wt
+			m.moveInput.SetValue(m.relativeToRepo(wt.Path))
+			m.moveInput.CursorEnd()
+			m.moveInput.Focus()
+			m.state = StateMove
+			return m, textinput.Blink
+		}
+	case ActionFilter:
+		m.state = StateFilter
+		m.filterInput.Focus()
+		return m, textinput.Blink
+	case ActionFetch:
+		m.state = StateFetching
+		return m, fetchAll
+	case ActionHelp:
+		m.state = StateHelp
+		return m, nil
+	case ActionPalette:
+		return m.openPalette()
+	case ActionQuit:
+		m.shouldQuit = true
+		return m, tea.Quit
+	case ActionDetail:
+		m.showDetail = !m.showDetail
+		// Lazy-load detail info when toggling on
+		if wt := m.cursorWorktree(); m.showDetail && wt != nil {
+			if wt.LastCommitHash == "" {
+				return m, loadWorktreeDetail(wt.Path)
+			}
+		}
+		return m, nil
+	case ActionPrune:
+		m.state = StatePruneConfirm
+		m.includePinned = false
+		return m, nil
+	case ActionPruneGone:
+		m.state = StatePruneGoneConfirm
+		m.includePinned = false
+		m.goneBranches = nil
+		m.goneLoading = true
+		return m, findGoneBranches(m.worktrees, m.repo.DefaultBranch)
+	case ActionStash:
+		if wt := m.cursorWorktree(); wt != nil {
+			m.stashWorktree = wt
+			m.stashCursor = 0
+			m.state = StateStash
+			return m, loadStashList(wt.Path)
+		}
+	case ActionBranches:
+		m.state = StateBranches
+		m.branchCursor = 0
+		m.branchViewOffset = 0
+		m.branchesLoading = true
+		return m, loadBranchDetails(m.repo.DefaultBranch)
+	case ActionTree:
+		m.treeView = !m.treeView
+		m.resortKeepingCursor()
+		return m, m.saveListView()
+	case ActionPin:
+		if wt := m.cursorWorktree(); wt != nil {
+			return m, m.togglePin(wt.Path)
+		}
+	case ActionPrevious:
+		wt := m.previousWorktree()
+		if wt == nil {
+			m.err = fmt.Errorf("no previously opened worktree")
+			return m, nil
+		}
+		m.moveCursorToPath(wt.Path)
+		return m.openSelected(wt)
+	case ActionCollapse:
+		if group := m.cursorGroup(); group != nil && !group.Collapsed {
+			return m, m.setGroupCollapsed(group.Prefix, true)
+		}
+		// Otherwise step out to the enclosing group
+		if m.cursor < len(m.rows) {
+			m.moveCursorToGroup(m.rows[m.cursor].Parent)
+		}
+		return m, nil
+	case ActionExpand:
+		if group := m.cursorGroup(); group != nil {
+			if group.Collapsed {
+				return m, m.setGroupCollapsed(group.Prefix, false)
+			}
+			// Already open: step into its first entry
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+				m.ensureCursorVisible()
+			}
+		}
+		return m, nil
+	case ActionSort:
+		m.sortMode = m.sortMode.Next()
+		m.applyFilter() // Re-sort the list
+		return m, nil
+	case ActionMark:
+		if wt := m.cursorWorktree(); wt != nil {
+			if m.marked[wt.Path] {
+				delete(m.marked, wt.Path)
+			} else {
+				m.marked[wt.Path] = true
+			}
+			if m.cursor < m.rowCount()-1 {
+				m.cursor++
+				m.ensureCursorVisible()
+			}
+		}
+		return m, nil
+	case ActionPush:
+		targets := m.actionTargets()
+		var pushable []git.Worktree
+		diverged := false
+		for _, wt := range targets {
+			if wt.IsDetached || wt.Branch == "" {
+				continue
+			}
+			pushable = append(pushable, wt)
+			if wt.Ahead > 0 && wt.Behind > 0 {
+				diverged = true
+			}
+		}
+		if len(pushable) == 0 {
+			if len(targets) > 0 {
+				m.err = fmt.Errorf("cannot push detached HEAD")
+			}
+			return m, nil
+		}
+		if diverged {
+			m.pushTargets = pushable
+			m.state = StatePushConfirmForce
+			return m, nil
+		}
+		m.state = StatePushing
+		return m, pushWorktrees(m.config, pushable, false)
+	}
+	return m, nil
+}
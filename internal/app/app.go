@@ -1,10 +1,16 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/user"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -16,6 +22,7 @@ import (
 	"github.com/henri123lemoine/grove/internal/config"
 	"github.com/henri123lemoine/grove/internal/exec"
 	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
 	"github.com/henri123lemoine/grove/internal/ui"
 )
 
@@ -36,17 +43,36 @@ const (
 	StateStash
 	StateSelectLayout
 	StatePruneConfirm
+	StatePushing
+	StatePushConfirmForce
+	StateBranches
+	StateBranchDeleteConfirm
+	StateBranchRename
+	StateBranchSetUpstream
+	StatePruneGoneConfirm
+	StateMove
+	StateLock
+	StateCreateRemoteClash
+	StateCreateTemplate
+	StateSelectSparse
+	StateWidenSparse
+	StateNote
+	StatePalette
+	StateStashApplyTo
+	StateStashBranch
 )
 
 // SortMode represents the worktree list sort order.
 type SortMode int
 
 const (
-	SortDefault  SortMode = iota // Current first, main second, then alphabetical
-	SortName                     // Alphabetical A-Z
-	SortNameDesc                 // Alphabetical Z-A
-	SortDirty                    // Dirty worktrees first
-	SortClean                    // Clean worktrees first
+	SortDefault    SortMode = iota // Current first, main second, then alphabetical
+	SortName                       // Alphabetical A-Z
+	SortNameDesc                   // Alphabetical Z-A
+	SortDirty                      // Dirty worktrees first
+	SortClean                      // Clean worktrees first
+	SortRecent                     // Most recently opened first
+	SortLastCommit                 // Newest commit first
 )
 
 // String returns the display name for the sort mode.
@@ -60,6 +86,10 @@ func (s SortMode) String() string {
 		return "dirty"
 	case SortClean:
 		return "clean"
+	case SortRecent:
+		return "recent"
+	case SortLastCommit:
+		return "last-commit"
 	default:
 		return "default"
 	}
@@ -67,7 +97,7 @@ func (s SortMode) String() string {
 
 // Next returns the next sort mode in the cycle.
 func (s SortMode) Next() SortMode {
-	return (s + 1) % 5
+	return (s + 1) % 7
 }
 
 // ParseSortMode parses a sort mode from string.
@@ -81,23 +111,15 @@ func ParseSortMode(s string) SortMode {
 		return SortDirty
 	case "clean":
 		return SortClean
+	case "recent":
+		return SortRecent
+	case "last-commit":
+		return SortLastCommit
 	default:
 		return SortDefault
 	}
 }
 
-// isConfirmKey returns true if the key is a confirmation key (y/Y).
-func isConfirmKey(msg tea.KeyMsg) bool {
-	s := msg.String()
-	return s == "y" || s == "Y"
-}
-
-// isDenyKey returns true if the key is a denial key (n/N).
-func isDenyKey(msg tea.KeyMsg) bool {
-	s := msg.String()
-	return s == "n" || s == "N"
-}
-
 // Model is the main application model.
 type Model struct {
 	// Configuration
@@ -118,12 +140,25 @@ type Model struct {
 	loading bool
 	err     error
 
+	// Post-create file copy (nil when no copy is running)
+	copyProgress  *git.CopyProgress
+	quitAfterCopy bool // exit_after_open is waiting for the copy to finish
+
 	// Create flow
 	createInput     textinput.Model
 	createBranch    string
 	createIsNew     bool
+	createRemote    string // Remote branch to track (e.g. "origin/feature-x")
 	baseBranchIndex int
 	baseViewOffset  int
+	branchPattern   *regexp.Regexp // Compiled branch.pattern (nil = unrestricted)
+
+	// Branch name template flow
+	templateIndex  int
+	templateFields []string
+	templateField  int
+	templateValues map[string]string
+	templateInput  textinput.Model
 
 	// Delete flow
 	deleteWorktree      *git.Worktree
@@ -140,19 +175,67 @@ type Model struct {
 	renameWorktree *git.Worktree
 	renameInput    textinput.Model
 
+	// Move flow
+	moveWorktree *git.Worktree
+	moveInput    textinput.Model
+
+	// Lock flow
+	lockWorktree *git.Worktree
+	lockInput    textinput.Model
+
+	// Note flow
+	noteWorktree *git.Worktree
+	noteInput    textinput.Model
+
+	// Command palette
+	paletteInput   textinput.Model
+	paletteMatches []ActionID
+	paletteCursor  int
+
+	// Sparse checkout: profile picker before creating, widen action after
+	pendingCreate *createRequest
+	sparseCursor  int
+	widenWorktree *git.Worktree
+	widenInput    textinput.Model
+
 	// Stash flow
-	stashWorktree *git.Worktree
-	stashEntries  []git.StashEntry
-	stashCursor   int
+	stashWorktree     *git.Worktree
+	stashEntries      []git.StashEntry
+	stashCursor       int
+	stashDiff         string // Preview of the selected stash
+	stashDiffErr      error  // Why the preview couldn't be loaded
+	stashDiffIndex    int    // Stash index the preview was requested for
+	stashTargetCursor int    // Worktree picked by apply-to
+	stashBranchInput  textinput.Model
 
 	// Layout selection flow
 	layoutWorktree *git.Worktree
 	layoutCursor   int
 
+	// Push flow
+	pushTargets []git.Worktree // Worktrees waiting on force-push confirmation
+
+	// Branches view (local branches without worktrees)
+	branchDetails       []git.BranchInfo
+	branchCursor        int
+	branchViewOffset    int
+	branchesLoading     bool
+	branchInput         textinput.Model  // Shared by rename and set-upstream
+	branchDeleteTargets []git.BranchInfo // Branches waiting on delete confirmation
+	branchForceConfirm  bool             // Second confirmation, for branches that need -D
+
+	// Prune-gone flow
+	goneBranches []git.GoneBranch
+	goneLoading  bool
+
+	// Marked worktrees (path -> marked) for bulk actions
+	marked map[string]bool
+
 	// UI
 	width              int
 	height             int
 	keys               KeyMap
+	pendingKeys        []string // Keys typed so far of a key sequence
 	showDetail         bool
 	spinner            spinner.Model
 	configWarnings     []string
@@ -160,6 +243,24 @@ type Model struct {
 	sortMode           SortMode         // Current sort order
 	cachedColumnWidths *ui.ColumnWidths // Cached for render performance
 
+	// When each worktree was last opened (path -> time), for SortRecent
+	recent map[string]time.Time
+
+	// Tree mode groups the list by branch prefix
+	treeView  bool
+	collapsed map[string]bool // Collapsed group prefixes
+	rows      []ui.ListRow    // Rows shown in tree mode; nil when flat
+
+	// Parsed filter input, updated by applyFilter
+	query filterQuery
+
+	// Merge status was requested by an is:merged qualifier
+	wantMerged bool
+
+	// Pinned worktree paths in pin order, kept at the top of the list
+	pinned        []string
+	includePinned bool // Prune flows also act on pinned worktrees
+
 	// Exit behavior
 	shouldQuit       bool
 	openAfterQuit    *git.Worktree
@@ -172,6 +273,17 @@ func New(cfg *config.Config, repo *git.Repo, configWarnings []string) Model {
 	createInput := textinput.New()
 	createInput.Placeholder = "branch-name"
 	createInput.CharLimit = 250 // Git supports up to 255 bytes
+	createInput.ShowSuggestions = true
+	createInput.SetSuggestions(cfg.Branch.Prefixes)
+
+	templateInput := textinput.New()
+	templateInput.CharLimit = 250
+
+	// Invalid patterns are reported by config validation
+	var branchPattern *regexp.Regexp
+	if cfg.Branch.Pattern != "" {
+		branchPattern, _ = regexp.Compile(cfg.Branch.Pattern)
+	}
 
 	deleteInput := textinput.New()
 	deleteInput.Placeholder = "Type 'delete' to confirm"
@@ -185,24 +297,65 @@ func New(cfg *config.Config, repo *git.Repo, configWarnings []string) Model {
 	renameInput.Placeholder = "new-branch-name"
 	renameInput.CharLimit = 250 // Git supports up to 255 bytes
 
+	branchInput := textinput.New()
+	branchInput.CharLimit = 250
+
+	moveInput := textinput.New()
+	moveInput.Placeholder = "new/worktree/path"
+	moveInput.CharLimit = 1024
+
+	lockInput := textinput.New()
+	lockInput.Placeholder = "reason (optional)"
+	lockInput.CharLimit = 200
+
+	noteInput := textinput.New()
+	noteInput.Placeholder = "#label note text"
+	noteInput.CharLimit = 500
+
+	stashBranchInput := textinput.New()
+	stashBranchInput.Placeholder = "new-branch-name"
+	stashBranchInput.CharLimit = 100
+
+	paletteInput := textinput.New()
+	paletteInput.Placeholder = "type a command"
+	paletteInput.CharLimit = 100
+
+	widenInput := textinput.New()
+	widenInput.Placeholder = "profile, directories, or * for everything"
+	widenInput.CharLimit = 1024
+	widenInput.ShowSuggestions = true
+	widenInput.SetSuggestions(sparseProfileNames(cfg))
+
 	// Initialize spinner with dots style
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	return Model{
-		config:         cfg,
-		repo:           repo,
-		keys:           KeyMapFromConfig(&cfg.Keys),
-		createInput:    createInput,
-		deleteInput:    deleteInput,
-		filterInput:    filterInput,
-		renameInput:    renameInput,
-		spinner:        s,
-		state:          StateList,
-		loading:        true,
-		configWarnings: configWarnings,
-		sortMode:       ParseSortMode(cfg.UI.DefaultSort),
+		config:           cfg,
+		repo:             repo,
+		keys:             KeyMapFromConfig(&cfg.Keys),
+		createInput:      createInput,
+		deleteInput:      deleteInput,
+		filterInput:      filterInput,
+		renameInput:      renameInput,
+		moveInput:        moveInput,
+		templateInput:    templateInput,
+		branchPattern:    branchPattern,
+		lockInput:        lockInput,
+		noteInput:        noteInput,
+		paletteInput:     paletteInput,
+		stashBranchInput: stashBranchInput,
+		widenInput:       widenInput,
+		branchInput:      branchInput,
+		spinner:          s,
+		state:            StateList,
+		loading:          true,
+		configWarnings:   configWarnings,
+		sortMode:         ParseSortMode(cfg.UI.DefaultSort),
+		marked:           make(map[string]bool),
+		collapsed:        make(map[string]bool),
+		recent:           make(map[string]time.Time),
 	}
 }
 
@@ -212,6 +365,9 @@ func (m Model) Init() tea.Cmd {
 		loadWorktrees,
 		loadBranchesWithTypes,
 		m.spinner.Tick,
+		watchConfig(configModTime()),
+		loadListView(m.repo),
+		loadRecentOpens,
 	)
 }
 
@@ -263,14 +419,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.worktrees = msg.Worktrees
 		m.rebuildWorktreeIndex()
+		m.attachPins()
 		m.applyFilter()
 		m.ensureCursorVisible()
 		// If from cache, trigger background refresh + upstream fetch
 		if msg.FromCache {
-			return m, tea.Batch(refreshWorktrees, loadUpstreamStatus(m.worktrees))
+			return m, tea.Batch(refreshWorktrees, loadUpstreamStatus(m.worktrees, m.config, m.repo, m.wantMerged))
 		}
 		// Fresh data - just fetch upstream
-		return m, loadUpstreamStatus(m.worktrees)
+		return m, loadUpstreamStatus(m.worktrees, m.config, m.repo, m.wantMerged)
 
 	case WorktreesLoadedMsg:
 		// Background refresh completed (or direct load in tests)
@@ -285,10 +442,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.worktrees = msg.Worktrees
 		m.rebuildWorktreeIndex()
+		m.attachPins()
 		m.applyFilter()
 		m.ensureCursorVisible()
 		// Trigger upstream fetch for fresh data
-		return m, loadUpstreamStatus(m.worktrees)
+		return m, loadUpstreamStatus(m.worktrees, m.config, m.repo, m.wantMerged)
 
 	case BranchesLoadedMsg:
 		if msg.Err != nil {
@@ -311,7 +469,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// 1. SafetyLevelSafe: Skip confirmation - worktree is clean and merged
 		//
 		// 2. SafetyLevelWarning: Skip if ALL of these are false:
-		//    - HasUncommittedChanges AND config.ConfirmDirty
+		//    - HasLocalChanges (files or submodules) AND config.ConfirmDirty
 		//    - !IsMerged AND config.ConfirmUnmerged
 		//    - HasUnpushedCommits (always requires warning)
 		//
@@ -325,7 +483,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case git.SafetyLevelSafe:
 			skipConfirmation = true
 		case git.SafetyLevelWarning:
-			needsDirtyConfirm := msg.Info.HasUncommittedChanges && m.config.Safety.ConfirmDirty
+			needsDirtyConfirm := msg.Info.HasLocalChanges() && m.config.Safety.ConfirmDirty
 			needsUnmergedConfirm := !msg.Info.IsMerged && m.config.Safety.ConfirmUnmerged
 			needsUnpushedConfirm := msg.Info.HasUnpushedCommits
 			skipConfirmation = !needsDirtyConfirm && !needsUnmergedConfirm && !needsUnpushedConfirm
@@ -337,7 +495,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if skipConfirmation {
 			// Proceed with deletion immediately
-			force := msg.Info.HasUncommittedChanges
+			force := msg.Info.HasLocalChanges()
 			path := m.deleteWorktree.Path
 			m.state = StateList
 			m.deleteWorktree = nil
@@ -357,13 +515,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			m.err = msg.Err
 		}
+		if msg.SubmoduleErr != nil {
+			m.err = msg.SubmoduleErr
+		}
 		m.createInput.Reset()
 		// Run post-create operations and optionally open the worktree
 		if msg.Err == nil && msg.Path != "" {
+			if len(m.config.Worktree.CopyPatterns) > 0 {
+				m.copyProgress = &git.CopyProgress{}
+			}
 			cmds := []tea.Cmd{
 				loadWorktrees,
 				runPostCreateOperations(m.config, msg.Path),
 			}
+			if m.config.Ports.Enabled {
+				cmds = append(cmds, allocatePorts(m.config, msg.Path))
+			}
 			// Auto-open the worktree if configured
 			if m.config.Open.OpenAfterCreate {
 				newWt := &git.Worktree{
@@ -434,7 +601,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.Err
 			return m, nil
 		}
+		// Mirror what openWorktree recorded in the state file
+		now := time.Now()
+		if current := m.currentWorktree(); current != nil && current.Path != msg.Path {
+			m.recent[current.Path] = now
+		}
+		m.recent[msg.Path] = now.Add(time.Nanosecond)
 		if m.config.Open.ExitAfterOpen {
+			// Quitting would abandon a copy into the new worktree halfway
+			if m.copyProgress != nil {
+				m.quitAfterCopy = true
+				return m, nil
+			}
 			m.shouldQuit = true
 			return m, tea.Quit
 		}
@@ -458,13 +636,94 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, loadWorktrees
 
+	case WorktreeMovedMsg:
+		m.state = StateList
+		m.moveInput.Reset()
+		m.moveWorktree = nil
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, loadWorktrees
+
+	case SparseWidenedMsg:
+		m.state = StateList
+		m.widenInput.Reset()
+		m.widenWorktree = nil
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, loadWorktrees
+
+	case WorktreeLockChangedMsg:
+		m.state = StateList
+		m.lockInput.Reset()
+		m.lockWorktree = nil
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		return m, loadWorktrees
+
+	case NoteSavedMsg:
+		m.state = StateList
+		m.noteInput.Reset()
+		m.noteWorktree = nil
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		if idx, ok := m.worktreeIndexByPath[msg.Path]; ok {
+			m.worktrees[idx].Note = msg.Note.Text
+			m.worktrees[idx].Labels = msg.Note.Labels
+		}
+		// The note may change what the filter matches and the column widths
+		m.resortKeepingCursor()
+		return m, nil
+
+	case FileCopyProgressMsg:
+		progress := msg.Progress
+		m.copyProgress = &progress
+		return m, waitForCopyUpdate(msg.updates)
+
 	case FileCopyCompletedMsg:
+		m.copyProgress = nil
 		if msg.Err != nil {
 			// Show error to user with clear context
 			m.err = fmt.Errorf("file copy failed: %w", msg.Err)
+			m.quitAfterCopy = false
+			return m, nil
+		}
+		if m.quitAfterCopy {
+			m.shouldQuit = true
+			return m, tea.Quit
+		}
+		return m, nil
+
+	case PortsAllocatedMsg:
+		if msg.Err != nil {
+			m.err = fmt.Errorf("port allocation failed: %w", msg.Err)
 		}
 		return m, nil
 
+	case configPollMsg:
+		return m, watchConfig(msg.modTime)
+
+	case ConfigReloadedMsg:
+		if msg.Err != nil {
+			m.err = fmt.Errorf("failed to reload config: %w", msg.Err)
+			return m, watchConfig(msg.ModTime)
+		}
+		// Only display settings apply live; the rest needs a restart
+		m.config.UI = msg.Config.UI
+		m.config.Themes = msg.Config.Themes
+		ui.InitTheme(m.config)
+		m.configWarnings = msg.Config.Validate()
+		m.updateColumnWidths()
+		// New columns may need data the list has not loaded yet
+		return m, tea.Batch(watchConfig(msg.ModTime), loadUpstreamStatus(m.worktrees, m.config, m.repo, m.wantMerged))
+
 	case PruneCompletedMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
@@ -485,6 +744,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.stashEntries = msg.Entries
+		return m, m.previewStash()
+
+	case StashDiffLoadedMsg:
+		// Ignore previews of stashes no longer selected
+		if msg.Index != m.stashDiffIndex {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.stashDiffErr = msg.Err
+			return m, nil
+		}
+		m.stashDiff = msg.Diff
+		if m.stashDiff == "" {
+			m.stashDiff = "(no tracked changes)"
+		}
 		return m, nil
 
 	case StashOperationCompletedMsg:
@@ -495,19 +769,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = StateList
 		m.stashWorktree = nil
 		m.stashEntries = nil
+		m.stashDiff = ""
+		m.stashDiffErr = nil
 		return m, loadWorktrees
 
+	case RecentOpensLoadedMsg:
+		m.recent = msg.Opened
+		if m.sortMode == SortRecent {
+			m.resortKeepingCursor()
+		}
+		return m, nil
+
+	case ListViewLoadedMsg:
+		m.treeView = msg.View.Tree
+		m.collapsed = make(map[string]bool, len(msg.View.Collapsed))
+		for _, prefix := range msg.View.Collapsed {
+			m.collapsed[prefix] = true
+		}
+		m.pinned = msg.View.Pinned
+		m.attachPins()
+		m.applyFilter()
+		m.ensureCursorVisible()
+		return m, nil
+
 	case DetailLoadedMsg:
 		// Update worktree with lazy-loaded detail info using O(1) map lookup
 		if i, ok := m.worktreeIndexByPath[msg.Path]; ok {
 			m.worktrees[i].LastCommitHash = msg.LastCommitHash
 			m.worktrees[i].LastCommitMessage = msg.LastCommitMessage
 			m.worktrees[i].LastCommitTime = msg.LastCommitTime
+			m.worktrees[i].Sparse = msg.Sparse
 		}
 		if i, ok := m.filteredIndexByPath[msg.Path]; ok {
 			m.filteredWorktrees[i].LastCommitHash = msg.LastCommitHash
 			m.filteredWorktrees[i].LastCommitMessage = msg.LastCommitMessage
 			m.filteredWorktrees[i].LastCommitTime = msg.LastCommitTime
+			m.filteredWorktrees[i].Sparse = msg.Sparse
 		}
 		return m, nil
 
@@ -520,19 +817,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update worktrees with background-loaded upstream status
 		for i := range m.worktrees {
 			if updated, ok := upstreamByPath[m.worktrees[i].Path]; ok {
-				m.worktrees[i].Ahead = updated.Ahead
-				m.worktrees[i].Behind = updated.Behind
-				m.worktrees[i].HasUpstream = updated.HasUpstream
+				copyBackgroundStatus(&m.worktrees[i], updated, msg.Merged)
 			}
 		}
 		// Also update filtered list
 		for i := range m.filteredWorktrees {
 			if updated, ok := upstreamByPath[m.filteredWorktrees[i].Path]; ok {
-				m.filteredWorktrees[i].Ahead = updated.Ahead
-				m.filteredWorktrees[i].Behind = updated.Behind
-				m.filteredWorktrees[i].HasUpstream = updated.HasUpstream
+				copyBackgroundStatus(&m.filteredWorktrees[i], updated, msg.Merged)
 			}
 		}
+		if m.sortMode == SortLastCommit || len(m.query.qualifiers) > 0 {
+			// Qualifiers may depend on what was just loaded
+			m.resortKeepingCursor()
+		} else {
+			m.rebuildRows() // Refresh group rollups
+			m.updateColumnWidths()
+		}
 		return m, nil
 
 	case BranchDeletedMsg:
@@ -541,6 +841,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Use refreshWorktrees to get fresh data after branch deletion
 		return m, refreshWorktrees
+
+	case BranchDetailsLoadedMsg:
+		m.branchesLoading = false
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.branchDetails = msg.Branches
+		if m.branchCursor >= len(m.branchDetails) {
+			m.branchCursor = len(m.branchDetails) - 1
+		}
+		if m.branchCursor < 0 {
+			m.branchCursor = 0
+		}
+		m.ensureBranchCursorVisible()
+		return m, nil
+
+	case BranchOperationCompletedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		m.state = StateBranches
+		m.branchInput.Reset()
+		m.branchInput.Blur()
+		m.branchDeleteTargets = nil
+		m.branchesLoading = true
+		// Refresh the create flow's branch list too
+		return m, tea.Batch(loadBranchDetails(m.repo.DefaultBranch), loadBranchesWithTypes)
+
+	case GoneBranchesCheckedMsg:
+		m.goneLoading = false
+		if msg.Err != nil {
+			m.err = msg.Err
+			m.state = StateList
+			return m, nil
+		}
+		if len(msg.Branches) == 0 {
+			m.err = fmt.Errorf("no branches with a gone upstream (fetch first to update)")
+			m.state = StateList
+			return m, nil
+		}
+		m.goneBranches = msg.Branches
+		return m, nil
+
+	case PruneGoneCompletedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		return m, tea.Batch(refreshWorktrees, loadBranchesWithTypes)
+
+	case PushCompletedMsg:
+		m.state = StateList
+		m.marked = make(map[string]bool)
+		if msg.Err != nil {
+			m.err = msg.Err
+		}
+		// Refresh ahead/behind and upstream tracking for everything we pushed
+		return m, loadUpstreamStatus(m.worktrees, m.config, m.repo, m.wantMerged)
 	}
 
 	return m, nil
@@ -548,6 +906,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress handles key presses based on current state.
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if bindings := m.screenBindings(); bindings != nil {
+		var ok bool
+		if msg, ok = m.resolveSequence(msg, bindings); !ok {
+			return m, nil
+		}
+	} else {
+		m.pendingKeys = nil
+	}
+
 	switch m.state {
 	case StateList:
 		return m.handleListKeys(msg)
@@ -573,6 +940,36 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleLayoutKeys(msg)
 	case StatePruneConfirm:
 		return m.handlePruneConfirmKeys(msg)
+	case StatePushConfirmForce:
+		return m.handlePushConfirmForceKeys(msg)
+	case StateBranches:
+		return m.handleBranchesKeys(msg)
+	case StateBranchDeleteConfirm:
+		return m.handleBranchDeleteConfirmKeys(msg)
+	case StateBranchRename, StateBranchSetUpstream:
+		return m.handleBranchInputKeys(msg)
+	case StatePruneGoneConfirm:
+		return m.handlePruneGoneConfirmKeys(msg)
+	case StateMove:
+		return m.handleMoveKeys(msg)
+	case StateLock:
+		return m.handleLockKeys(msg)
+	case StateNote:
+		return m.handleNoteKeys(msg)
+	case StatePalette:
+		return m.handlePaletteKeys(msg)
+	case StateStashApplyTo:
+		return m.handleStashApplyToKeys(msg)
+	case StateStashBranch:
+		return m.handleStashBranchKeys(msg)
+	case StateCreateRemoteClash:
+		return m.handleRemoteClashKeys(msg)
+	case StateCreateTemplate:
+		return m.handleCreateTemplateKeys(msg)
+	case StateSelectSparse:
+		return m.handleSelectSparseKeys(msg)
+	case StateWidenSparse:
+		return m.handleWidenSparseKeys(msg)
 	}
 	return m, nil
 }
@@ -603,8 +1000,8 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		line := msg.Y - listTop
 		for i := startIdx; i < endIdx; i++ {
 			entryLines := 1
-			if m.showDetail && i == m.cursor {
-				entryLines += detailPanelLineCount(m.filteredWorktrees[i])
+			if wt := m.cursorWorktree(); m.showDetail && i == m.cursor && wt != nil {
+				entryLines += detailPanelLineCount(*wt)
 			}
 			if line < entryLines {
 				m.cursor = i
@@ -630,111 +1027,116 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch {
-	case key.Matches(msg, m.keys.Up):
-		if m.cursor > 0 {
-			m.cursor--
-			m.ensureCursorVisible()
+	// Otherwise Esc clears any marks
+	if msg.Type == tea.KeyEsc && len(m.marked) > 0 {
+		m.marked = make(map[string]bool)
+		return m, nil
+	}
+
+	// 1-9 open the pinned worktree in that slot
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '9' {
+		slot := int(msg.Runes[0] - '0')
+		wt := m.pinnedWorktree(slot)
+		if wt == nil {
+			m.err = fmt.Errorf("no worktree pinned to %d", slot)
+			return m, nil
 		}
-	case key.Matches(msg, m.keys.Down):
-		if m.cursor < len(m.filteredWorktrees)-1 {
-			m.cursor++
-			m.ensureCursorVisible()
+		m.moveCursorToPath(wt.Path)
+		return m.openSelected(wt)
+	}
+
+	if id, ok := m.actionForKey(msg); ok {
+		return m.runAction(id)
+	}
+	return m, nil
+}
+
+// openSelected opens wt, first offering the layout picker when layouts are
+// defined and no window exists for it yet.
+func (m Model) openSelected(wt *git.Worktree) (tea.Model, tea.Cmd) {
+	m.selectedWorktree = wt
+
+	if len(m.config.Layouts) > 0 && !exec.WindowExistsFor(m.config, wt) {
+		m.layoutWorktree = wt
+		m.layoutCursor = 0
+		m.state = StateSelectLayout
+		return m, nil
+	}
+
+	// No layouts or window already exists, open directly
+	return m, openWorktree(m.config, wt, m.currentWorktree(), nil)
+}
+
+// previousWorktree returns the most recently opened worktree other than the
+// current one, like "cd -".
+func (m Model) previousWorktree() *git.Worktree {
+	var prev *git.Worktree
+	var prevTime time.Time
+	for i := range m.filteredWorktrees {
+		wt := &m.filteredWorktrees[i]
+		t, ok := m.recent[wt.Path]
+		if !ok || wt.IsCurrent || wt.IsPrunable {
+			continue
 		}
-	case key.Matches(msg, m.keys.Home):
-		m.cursor = 0
-		m.viewOffset = 0
-	case key.Matches(msg, m.keys.End):
-		m.cursor = len(m.filteredWorktrees) - 1
-		if m.cursor < 0 {
-			m.cursor = 0
+		if prev == nil || t.After(prevTime) {
+			prev, prevTime = wt, t
 		}
-		m.ensureCursorVisible()
-	case key.Matches(msg, m.keys.Open):
-		if len(m.filteredWorktrees) > 0 && m.cursor < len(m.filteredWorktrees) {
-			wt := &m.filteredWorktrees[m.cursor]
-			m.selectedWorktree = wt
-
-			// If layouts are defined and no window already exists, show layout selector
-			if len(m.config.Layouts) > 0 && !exec.WindowExistsFor(m.config, wt) {
-				m.layoutWorktree = wt
-				m.layoutCursor = 0
-				m.state = StateSelectLayout
-				return m, nil
-			}
+	}
+	return prev
+}
 
-			// No layouts or window already exists, open directly
-			return m, openWorktree(m.config, wt, m.currentWorktree(), nil)
+// pinnedWorktree returns the worktree pinned to slot (1-based), or nil.
+func (m Model) pinnedWorktree(slot int) *git.Worktree {
+	for i := range m.worktrees {
+		if m.worktrees[i].Pin == slot {
+			return &m.worktrees[i]
 		}
-	case key.Matches(msg, m.keys.New):
-		m.state = StateCreate
-		m.createInput.Focus()
-		return m, textinput.Blink
-	case key.Matches(msg, m.keys.Delete):
-		if len(m.filteredWorktrees) > 0 && m.cursor < len(m.filteredWorktrees) {
-			wt := &m.filteredWorktrees[m.cursor]
-			if wt.IsMain {
-				m.err = fmt.Errorf("cannot delete main worktree")
-				return m, nil
-			}
-			m.deleteWorktree = wt
-			m.state = StateDelete
-			return m, checkSafety(wt.Path, wt.Branch, m.repo.DefaultBranch)
-		}
-	case key.Matches(msg, m.keys.Rename):
-		if len(m.filteredWorktrees) > 0 && m.cursor < len(m.filteredWorktrees) {
-			wt := &m.filteredWorktrees[m.cursor]
-			if wt.IsMain {
-				m.err = fmt.Errorf("cannot rename main worktree branch")
-				return m, nil
-			}
-			if wt.IsDetached {
-				m.err = fmt.Errorf("cannot rename detached HEAD (checkout a branch first)")
-				return m, nil
-			}
-			m.renameWorktree = wt
-			m.renameInput.SetValue(wt.Branch)
-			m.renameInput.Focus()
-			m.state = StateRename
-			return m, textinput.Blink
+	}
+	return nil
+}
+
+// attachPins numbers the pinned worktrees that still exist, in pin order.
+func (m *Model) attachPins() {
+	for i := range m.worktrees {
+		m.worktrees[i].Pin = 0
+	}
+	slot := 0
+	for _, path := range m.pinned {
+		if i, ok := m.worktreeIndexByPath[path]; ok {
+			slot++
+			m.worktrees[i].Pin = slot
 		}
-	case key.Matches(msg, m.keys.Filter):
-		m.state = StateFilter
-		m.filterInput.Focus()
-		return m, textinput.Blink
-	case key.Matches(msg, m.keys.Fetch):
-		m.state = StateFetching
-		return m, fetchAll
-	case key.Matches(msg, m.keys.Help):
-		m.state = StateHelp
-		return m, nil
-	case key.Matches(msg, m.keys.Detail):
-		m.showDetail = !m.showDetail
-		// Lazy-load detail info when toggling on
-		if m.showDetail && len(m.filteredWorktrees) > 0 && m.cursor < len(m.filteredWorktrees) {
-			wt := m.filteredWorktrees[m.cursor]
-			if wt.LastCommitHash == "" {
-				return m, loadWorktreeDetail(wt.Path)
+	}
+}
+
+// togglePin pins or unpins the worktree at path and saves the pins.
+func (m *Model) togglePin(path string) tea.Cmd {
+	if i := slices.Index(m.pinned, path); i >= 0 {
+		m.pinned = slices.Delete(slices.Clone(m.pinned), i, i+1)
+	} else {
+		m.pinned = append(slices.Clone(m.pinned), path)
+	}
+	m.attachPins()
+	m.resortKeepingCursor()
+	return m.saveListView()
+}
+
+// actionTargets returns the worktrees a bulk-capable action applies to:
+// the marked worktrees if any are marked, otherwise the one under the cursor.
+func (m Model) actionTargets() []git.Worktree {
+	if len(m.marked) > 0 {
+		var targets []git.Worktree
+		for _, wt := range m.worktrees {
+			if m.marked[wt.Path] {
+				targets = append(targets, wt)
 			}
 		}
-		return m, nil
-	case key.Matches(msg, m.keys.Prune):
-		m.state = StatePruneConfirm
-		return m, nil
-	case key.Matches(msg, m.keys.Stash):
-		if len(m.filteredWorktrees) > 0 && m.cursor < len(m.filteredWorktrees) {
-			wt := &m.filteredWorktrees[m.cursor]
-			m.stashWorktree = wt
-			m.stashCursor = 0
-			m.state = StateStash
-			return m, loadStashList(wt.Path)
-		}
-	case key.Matches(msg, m.keys.Sort):
-		m.sortMode = m.sortMode.Next()
-		m.applyFilter() // Re-sort the list
-		return m, nil
+		return targets
 	}
-	return m, nil
+	if wt := m.cursorWorktree(); wt != nil {
+		return []git.Worktree{*wt}
+	}
+	return nil
 }
 
 // handleHelpKeys handles key presses in the help view.
@@ -750,6 +1152,20 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyEsc:
 		m.state = StateList
 		m.createInput.Reset()
+		m.createRemote = ""
+		return m, nil
+	case tea.KeyTab:
+		// Prefer turning an invalid name into its slug; otherwise let the
+		// input complete a configured prefix
+		if suggestion := m.createSuggestion(); suggestion != "" {
+			m.createInput.SetValue(suggestion)
+			m.createInput.CursorEnd()
+			return m, nil
+		}
+	case tea.KeyCtrlT:
+		if len(m.config.Branch.Templates) > 0 {
+			return m.startBranchTemplate(0)
+		}
 		return m, nil
 	case tea.KeyEnter:
 		branchName := m.createInput.Value()
@@ -758,6 +1174,20 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.createBranch = branchName
 
+		// Picking a new local name for a remote branch after a clash
+		if m.createRemote != "" {
+			if m.validateNewBranchName(branchName) != nil {
+				return m, nil
+			}
+			if git.BranchExists(branchName) {
+				m.err = fmt.Errorf("branch %q already exists", branchName)
+				return m, nil
+			}
+			remote := m.createRemote
+			m.createRemote = ""
+			return m.requestCreate(createRequest{branch: branchName, remote: remote})
+		}
+
 		// Check if this branch already has a worktree
 		for i := range m.worktrees {
 			if m.worktrees[i].Branch == branchName {
@@ -767,9 +1197,23 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Tags, SHAs and revision expressions get a detached worktree
+		if sha, ok := git.ResolveDetachTarget(branchName); ok {
+			return m.requestCreate(createRequest{rev: branchName, shortSHA: sha})
+		}
+
+		// Remote branches get a local branch tracking them
+		if remote, local, ok := git.ResolveRemoteBranch(branchName, git.GetPrimaryRemote(m.config.General.Remote)); ok {
+			return m.startRemoteCheckout(local, remote)
+		}
+
 		// Check if branch exists
 		m.createIsNew = !git.BranchExists(branchName)
 		if m.createIsNew {
+			// The view already shows why the name is rejected
+			if m.validateNewBranchName(branchName) != nil {
+				return m, nil
+			}
 			m.state = StateCreateSelectBase
 			// Pre-select the configured default base branch if it exists in the list
 			m.baseBranchIndex = 0
@@ -785,7 +1229,7 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Branch exists, create worktree
-		return m, createWorktree(m.config, branchName, false, "")
+		return m.requestCreate(createRequest{branch: branchName})
 	}
 
 	var cmd tea.Cmd
@@ -793,50 +1237,255 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// handleSelectBaseKeys handles key presses when selecting base branch.
-func (m Model) handleSelectBaseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch {
-	case key.Matches(msg, m.keys.Up):
-		if m.baseBranchIndex > 0 {
-			m.baseBranchIndex--
-			m.ensureBaseBranchVisible()
-		}
-	case key.Matches(msg, m.keys.Down):
-		if m.baseBranchIndex < len(m.branches)-1 {
-			m.baseBranchIndex++
-			m.ensureBaseBranchVisible()
-		}
-	case key.Matches(msg, m.keys.Cancel):
-		m.state = StateList
-		m.createInput.Reset()
-		m.baseViewOffset = 0
-		return m, nil
-	case key.Matches(msg, m.keys.Confirm):
-		baseBranch := ""
-		if m.baseBranchIndex < len(m.branches) {
-			baseBranch = m.branches[m.baseBranchIndex].Name
-		}
-		m.baseViewOffset = 0
-		return m, createWorktree(m.config, m.createBranch, true, baseBranch)
+// validateNewBranchName checks a name for a new branch against git's ref
+// rules and the configured branch.pattern.
+func (m Model) validateNewBranchName(name string) error {
+	if err := git.ValidateBranchName(name); err != nil {
+		return err
 	}
-	return m, nil
+	if m.branchPattern != nil && !m.branchPattern.MatchString(name) {
+		return fmt.Errorf("branch name must match %s", m.branchPattern)
+	}
+	return nil
 }
 
-// handleDeleteKeys handles key presses in the delete confirmation.
-func (m Model) handleDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.safetyInfo == nil {
-		// Still loading safety info
-		return m, nil
+// createValidation returns the live validation message for the create input.
+// Revision syntax is left alone since it may name a detach target, and so
+// are names createHint recognizes.
+func (m Model) createValidation() string {
+	value := m.createInput.Value()
+	if value == "" || strings.ContainsAny(value, "~^") || m.createHint() != "" {
+		return ""
+	}
+	if err := m.validateNewBranchName(value); err != nil {
+		return err.Error()
 	}
+	return ""
+}
 
-	// Determine if we should require typing "delete" based on config
-	requireTyping := m.safetyInfo.Level == git.SafetyLevelDanger &&
-		m.config.Safety.RequireTypingForUnique
+// createHint describes what enter will do when the create input names an
+// existing worktree, branch or tag rather than a new branch. It checks the
+// loaded branch list so rendering doesn't run git.
+func (m Model) createHint() string {
+	value := m.createInput.Value()
+	if value == "" || m.createRemote != "" {
+		return ""
+	}
+	if value == "HEAD" {
+		return "creates a detached worktree at HEAD"
+	}
+	for _, wt := range m.worktrees {
+		if wt.Branch == value {
+			return "opens the existing worktree"
+		}
+	}
 
-	switch msg.Type {
-	case tea.KeyEsc:
-		m.state = StateList
-		m.deleteInput.Reset()
+	var tag, remote, bareRemote bool
+	for _, b := range m.branches {
+		switch {
+		case b.IsTag:
+			tag = tag || b.Name == value
+		case b.IsRemote:
+			remote = remote || b.Name == value
+			if _, name, ok := strings.Cut(b.Name, "/"); ok && name == value {
+				bareRemote = true
+			}
+		case b.Name == value:
+			return "existing branch: creates a worktree for it"
+		}
+	}
+	switch {
+	case tag:
+		return "tag: creates a detached worktree"
+	case remote:
+		return "remote branch: creates a local branch tracking it"
+	case bareRemote:
+		return "on the remote: creates a local branch tracking it"
+	}
+	return ""
+}
+
+// createSuggestion returns a slugified version of an invalid create input,
+// e.g. a pasted ticket title, or "" when there is nothing better to offer.
+func (m Model) createSuggestion() string {
+	value := m.createInput.Value()
+	if m.createValidation() == "" {
+		return ""
+	}
+	suggestion := slugifyBranch(value)
+	if suggestion == value || m.validateNewBranchName(suggestion) != nil {
+		return ""
+	}
+	return suggestion
+}
+
+// startBranchTemplate begins filling in branch.templates[index].
+func (m Model) startBranchTemplate(index int) (tea.Model, tea.Cmd) {
+	tmpl := m.config.Branch.Templates[index]
+	m.templateIndex = index
+	m.templateFields = templateFields(tmpl)
+	m.templateField = 0
+	m.templateValues = make(map[string]string)
+	m.templateInput.Reset()
+	m.templateInput.Focus()
+	m.state = StateCreateTemplate
+	return m, textinput.Blink
+}
+
+// handleCreateTemplateKeys handles key presses while filling in a template.
+func (m Model) handleCreateTemplateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateCreate
+		m.templateInput.Blur()
+		return m, nil
+	case tea.KeyCtrlT:
+		return m.startBranchTemplate((m.templateIndex + 1) % len(m.config.Branch.Templates))
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.templateInput.Value())
+		if value == "" || m.templateField >= len(m.templateFields) {
+			return m, nil
+		}
+		field := m.templateFields[m.templateField]
+		if field == "slug" {
+			value = slugify(value)
+		} else {
+			value = strings.Join(strings.Fields(value), "-")
+		}
+		m.templateValues[field] = value
+		m.templateField++
+		m.templateInput.Reset()
+
+		if m.templateField == len(m.templateFields) {
+			// Hand the result back to the create input for review
+			tmpl := m.config.Branch.Templates[m.templateIndex]
+			m.createInput.SetValue(expandBranchTemplate(tmpl, m.templateValues))
+			m.createInput.CursorEnd()
+			m.createInput.Focus()
+			m.templateInput.Blur()
+			m.state = StateCreate
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.templateInput, cmd = m.templateInput.Update(msg)
+	return m, cmd
+}
+
+// currentTemplateField returns the template field being prompted for.
+func (m Model) currentTemplateField() string {
+	if m.templateField < len(m.templateFields) {
+		return m.templateFields[m.templateField]
+	}
+	return ""
+}
+
+// templatePreview shows the branch template with the fields filled so far.
+func (m Model) templatePreview() string {
+	if m.templateIndex >= len(m.config.Branch.Templates) {
+		return ""
+	}
+	return expandBranchTemplate(m.config.Branch.Templates[m.templateIndex], m.templateValues)
+}
+
+// startRemoteCheckout creates a local branch tracking remote, or asks what
+// to do when a local branch with that name already exists.
+func (m Model) startRemoteCheckout(local, remote string) (tea.Model, tea.Cmd) {
+	m.createBranch = local
+	if git.BranchExists(local) {
+		m.createRemote = remote
+		m.state = StateCreateRemoteClash
+		return m, nil
+	}
+	return m.requestCreate(createRequest{branch: local, remote: remote})
+}
+
+// handleRemoteClashKeys handles the prompt shown when checking out a remote
+// branch whose local name is already taken.
+func (m Model) handleRemoteClashKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Reuse):
+		// Reuse the existing local branch, opening its worktree if it has one.
+		// It tracks the remote branch from now on, as a new branch would.
+		if err := git.SetBranchUpstream(m.createBranch, m.createRemote); err != nil {
+			m.err = fmt.Errorf("failed to track %s: %w", m.createRemote, err)
+		}
+		m.createRemote = ""
+		m.createInput.Reset()
+		for i := range m.worktrees {
+			if m.worktrees[i].Branch == m.createBranch {
+				m.state = StateList
+				return m, openWorktree(m.config, &m.worktrees[i], m.currentWorktree(), nil)
+			}
+		}
+		return m.requestCreate(createRequest{branch: m.createBranch})
+	case key.Matches(msg, m.keys.NewName):
+		// Keep createRemote so the next name entered tracks it
+		m.state = StateCreate
+		m.createInput.SetValue(m.createBranch)
+		m.createInput.CursorEnd()
+		m.createInput.Focus()
+		return m, textinput.Blink
+	case msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.Cancel):
+		m.state = StateList
+		m.createRemote = ""
+		m.createInput.Reset()
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleSelectBaseKeys handles key presses when selecting base branch.
+func (m Model) handleSelectBaseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.baseBranchIndex > 0 {
+			m.baseBranchIndex--
+			m.ensureBaseBranchVisible()
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.baseBranchIndex < len(m.branches)-1 {
+			m.baseBranchIndex++
+			m.ensureBaseBranchVisible()
+		}
+	case msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.Cancel):
+		m.state = StateList
+		m.createInput.Reset()
+		m.baseViewOffset = 0
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		baseBranch := ""
+		if m.baseBranchIndex < len(m.branches) {
+			baseBranch = m.branches[m.baseBranchIndex].Name
+			// A remote base only becomes the upstream when the names match;
+			// otherwise the new branch just starts from it
+			if _, name, _ := strings.Cut(baseBranch, "/"); m.branches[m.baseBranchIndex].IsRemote && name == m.createBranch {
+				m.baseViewOffset = 0
+				return m.requestCreate(createRequest{branch: m.createBranch, remote: baseBranch})
+			}
+		}
+		m.baseViewOffset = 0
+		return m.requestCreate(createRequest{branch: m.createBranch, isNew: true, base: baseBranch})
+	}
+	return m, nil
+}
+
+// handleDeleteKeys handles key presses in the delete confirmation.
+func (m Model) handleDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.safetyInfo == nil {
+		// Still loading safety info
+		return m, nil
+	}
+
+	// Determine if we should require typing "delete" based on config
+	requireTyping := m.safetyInfo.Level == git.SafetyLevelDanger &&
+		m.config.Safety.RequireTypingForUnique
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateList
+		m.deleteInput.Reset()
 		m.deleteWorktree = nil
 		m.safetyInfo = nil
 		return m, nil
@@ -848,7 +1497,7 @@ func (m Model) handleDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		// Proceed with deletion
-		force := m.safetyInfo.HasUncommittedChanges
+		force := m.safetyInfo.HasLocalChanges()
 		m.forceDeleteBranch = m.safetyInfo.Level == git.SafetyLevelDanger
 		return m, deleteWorktree(m.deleteWorktree.Path, force)
 	}
@@ -861,12 +1510,12 @@ func (m Model) handleDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// For safe/warning (and danger without RequireTypingForUnique), y confirms, n cancels
-	if isConfirmKey(msg) {
-		force := m.safetyInfo.HasUncommittedChanges
+	if key.Matches(msg, m.keys.Yes) {
+		force := m.safetyInfo.HasLocalChanges()
 		m.forceDeleteBranch = m.safetyInfo.Level == git.SafetyLevelDanger
 		return m, deleteWorktree(m.deleteWorktree.Path, force)
 	}
-	if isDenyKey(msg) {
+	if key.Matches(msg, m.keys.No) {
 		m.state = StateList
 		m.deleteInput.Reset()
 		m.deleteWorktree = nil
@@ -889,7 +1538,7 @@ func (m Model) handleDeleteConfirmCloseWindowKeys(msg tea.KeyMsg) (tea.Model, te
 		return m, refreshWorktrees
 	}
 
-	if isConfirmKey(msg) {
+	if key.Matches(msg, m.keys.Yes) {
 		// Close the windows/tabs
 		for _, w := range m.pendingWindowsClose {
 			_ = exec.CloseWindow(w)
@@ -898,7 +1547,7 @@ func (m Model) handleDeleteConfirmCloseWindowKeys(msg tea.KeyMsg) (tea.Model, te
 		// Continue to branch deletion prompt
 		return m.handleBranchDeletionPrompt()
 	}
-	if isDenyKey(msg) {
+	if key.Matches(msg, m.keys.No) {
 		// Don't close windows, but still check branch deletion
 		m.pendingWindowsClose = nil
 		return m.handleBranchDeletionPrompt()
@@ -913,8 +1562,8 @@ func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyEsc:
 		// Remember the currently selected worktree before clearing filter
 		var selectedPath string
-		if m.cursor >= 0 && m.cursor < len(m.filteredWorktrees) {
-			selectedPath = m.filteredWorktrees[m.cursor].Path
+		if wt := m.cursorWorktree(); wt != nil {
+			selectedPath = wt.Path
 		}
 
 		m.state = StateList
@@ -923,12 +1572,7 @@ func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		// Try to restore cursor to the same worktree
 		if selectedPath != "" {
-			for i, wt := range m.filteredWorktrees {
-				if wt.Path == selectedPath {
-					m.cursor = i
-					break
-				}
-			}
+			m.moveCursorToPath(selectedPath)
 		}
 		return m, nil
 	case tea.KeyEnter:
@@ -940,6 +1584,11 @@ func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.filterInput, cmd = m.filterInput.Update(msg)
 	m.applyFilter()
+	if m.query.needsMerged() && !m.wantMerged {
+		// Merge status is only loaded for the merged column otherwise
+		m.wantMerged = true
+		return m, tea.Batch(cmd, loadUpstreamStatus(m.worktrees, m.config, m.repo, true))
+	}
 	return m, cmd
 }
 
@@ -959,7 +1608,14 @@ func (m Model) handleRenameKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.renameWorktree = nil
 			return m, nil
 		}
-		return m, renameBranch(m.renameWorktree.Path, m.renameWorktree.Branch, newName)
+		if err := m.validateNewBranchName(newName); err != nil {
+			m.state = StateList
+			m.err = err
+			m.renameInput.Reset()
+			m.renameWorktree = nil
+			return m, nil
+		}
+		return m, renameBranch(m.config, *m.renameWorktree, newName)
 	}
 
 	var cmd tea.Cmd
@@ -967,6 +1623,223 @@ func (m Model) handleRenameKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// handleMoveKeys handles key presses in move flow.
+func (m Model) handleMoveKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateList
+		m.moveInput.Reset()
+		m.moveWorktree = nil
+		return m, nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.moveInput.Value())
+		if value == "" {
+			m.state = StateList
+			m.moveInput.Reset()
+			m.moveWorktree = nil
+			return m, nil
+		}
+		newPath := value
+		if !filepath.IsAbs(newPath) && m.repo != nil {
+			newPath = filepath.Join(m.repo.MainWorktreeRoot, newPath)
+		}
+		return m, moveWorktree(m.config, *m.moveWorktree, filepath.Clean(newPath))
+	}
+
+	var cmd tea.Cmd
+	m.moveInput, cmd = m.moveInput.Update(msg)
+	return m, cmd
+}
+
+// handleLockKeys handles key presses while entering a lock reason.
+func (m Model) handleLockKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateList
+		m.lockInput.Reset()
+		m.lockWorktree = nil
+		return m, nil
+	case tea.KeyEnter:
+		reason := strings.TrimSpace(m.lockInput.Value())
+		return m, lockWorktree(m.lockWorktree.Path, reason)
+	}
+
+	var cmd tea.Cmd
+	m.lockInput, cmd = m.lockInput.Update(msg)
+	return m, cmd
+}
+
+// handleNoteKeys handles key presses while editing a worktree note.
+func (m Model) handleNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateList
+		m.noteInput.Reset()
+		m.noteWorktree = nil
+		return m, nil
+	case tea.KeyEnter:
+		return m, saveNote(m.noteWorktree.Path, state.ParseNote(m.noteInput.Value()))
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// createRequest records a worktree creation while the sparse profile
+// picker is shown.
+type createRequest struct {
+	branch   string // Branch to check out, or to create when isNew
+	isNew    bool
+	base     string // Base for a new branch
+	remote   string // Remote branch to track (e.g. "origin/feature-x")
+	rev      string // Revision for a detached worktree
+	shortSHA string
+}
+
+func (r createRequest) cmd(cfg *config.Config, sparse *git.SparseCheckout) tea.Cmd {
+	switch {
+	case r.rev != "":
+		return createDetachedWorktree(cfg, r.rev, r.shortSHA, sparse)
+	case r.remote != "":
+		return createTrackingWorktree(cfg, r.branch, r.remote, sparse)
+	default:
+		return createWorktree(cfg, r.branch, r.isNew, r.base, sparse)
+	}
+}
+
+// requestCreate creates the worktree, first asking for a sparse profile
+// when any are configured.
+func (m Model) requestCreate(req createRequest) (tea.Model, tea.Cmd) {
+	if len(m.config.Worktree.SparseProfiles) == 0 {
+		return m, req.cmd(m.config, nil)
+	}
+	m.pendingCreate = &req
+	m.sparseCursor = 0
+	m.state = StateSelectSparse
+	return m, nil
+}
+
+// pendingCreateLabel names the worktree waiting on the sparse profile picker.
+func (m Model) pendingCreateLabel() string {
+	if m.pendingCreate == nil {
+		return ""
+	}
+	if m.pendingCreate.rev != "" {
+		return m.pendingCreate.shortSHA + " (detached)"
+	}
+	return m.pendingCreate.branch
+}
+
+// handleSelectSparseKeys handles the sparse profile picker. The last
+// option is a full checkout.
+func (m Model) handleSelectSparseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	numOptions := len(m.config.Worktree.SparseProfiles) + 1
+
+	switch {
+	case msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.Cancel):
+		m.state = StateList
+		m.pendingCreate = nil
+		m.createInput.Reset()
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		if m.pendingCreate == nil {
+			m.state = StateList
+			return m, nil
+		}
+		var sparse *git.SparseCheckout
+		if m.sparseCursor < len(m.config.Worktree.SparseProfiles) {
+			profile := m.config.Worktree.SparseProfiles[m.sparseCursor]
+			sparse = &git.SparseCheckout{Profile: profile.Name, Paths: profile.Paths}
+		}
+		req := *m.pendingCreate
+		m.pendingCreate = nil
+		return m, req.cmd(m.config, sparse)
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.sparseCursor > 0 {
+			m.sparseCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.sparseCursor < numOptions-1 {
+			m.sparseCursor++
+		}
+	case key.Matches(msg, m.keys.Home):
+		m.sparseCursor = 0
+	case key.Matches(msg, m.keys.End):
+		m.sparseCursor = numOptions - 1
+	}
+	return m, nil
+}
+
+// handleWidenSparseKeys handles the widen input. A profile name adds that
+// profile's directories, "*" checks out everything, and anything else is
+// taken as a list of directories.
+func (m Model) handleWidenSparseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateList
+		m.widenInput.Reset()
+		m.widenWorktree = nil
+		return m, nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.widenInput.Value())
+		if value == "" || m.widenWorktree == nil {
+			return m, nil
+		}
+		if value == "*" {
+			return m, widenSparse(m.widenWorktree.Path, nil, "")
+		}
+		if profile := m.config.GetSparseProfile(value); profile != nil {
+			return m, widenSparse(m.widenWorktree.Path, profile.Paths, profile.Name)
+		}
+		paths := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+		return m, widenSparse(m.widenWorktree.Path, paths, "")
+	}
+
+	var cmd tea.Cmd
+	m.widenInput, cmd = m.widenInput.Update(msg)
+	return m, cmd
+}
+
+// sparseProfileNames lists the configured profile names for completion.
+func sparseProfileNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Worktree.SparseProfiles))
+	for _, p := range cfg.Worktree.SparseProfiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// lockReasonSuffix formats a lock reason for error messages.
+func lockReasonSuffix(wt *git.Worktree) string {
+	if wt.LockReason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", wt.LockReason)
+}
+
+// updateColumnWidths recalculates the cached list column widths.
+func (m *Model) updateColumnWidths() {
+	widths := ui.CalculateColumnWidths(m.filteredWorktrees, m.rows, m.config)
+	m.cachedColumnWidths = &widths
+}
+
+// relativeToRepo returns path relative to the main worktree root when it
+// lives inside it, so the move input starts with something short to edit.
+func (m Model) relativeToRepo(path string) string {
+	if m.repo == nil {
+		return path
+	}
+	rel, err := filepath.Rel(m.repo.MainWorktreeRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
 // handleStashKeys handles key presses in stash management flow.
 func (m Model) handleStashKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
@@ -982,35 +1855,58 @@ func (m Model) handleStashKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.stashCursor > 0 {
 			m.stashCursor--
 		}
-		return m, nil
+		return m, m.previewStash()
 	case key.Matches(msg, m.keys.Down):
 		if m.stashCursor < len(m.stashEntries)-1 {
 			m.stashCursor++
 		}
-		return m, nil
+		return m, m.previewStash()
 	case key.Matches(msg, m.keys.Home):
 		m.stashCursor = 0
-		return m, nil
+		return m, m.previewStash()
 	case key.Matches(msg, m.keys.End):
 		if len(m.stashEntries) > 0 {
 			m.stashCursor = len(m.stashEntries) - 1
 		}
-		return m, nil
+		return m, m.previewStash()
 	}
 
 	// Check for action keys
-	switch msg.String() {
-	case "p": // Pop stash
+	switch {
+	case key.Matches(msg, m.keys.StashPop):
 		if len(m.stashEntries) > 0 && m.stashCursor < len(m.stashEntries) {
 			entry := m.stashEntries[m.stashCursor]
 			return m, popStash(m.stashWorktree.Path, entry.Index)
 		}
-	case "a": // Apply stash (keep in list)
+	case key.Matches(msg, m.keys.StashApply): // Keeps the stash in the list
 		if len(m.stashEntries) > 0 && m.stashCursor < len(m.stashEntries) {
 			entry := m.stashEntries[m.stashCursor]
 			return m, applyStash(m.stashWorktree.Path, entry.Index)
 		}
-	case "d", "x": // Drop stash
+	case key.Matches(msg, m.keys.StashApplyTo):
+		if entry, ok := m.selectedStash(); ok {
+			targets := m.stashTargets()
+			if len(targets) == 0 {
+				m.err = fmt.Errorf("no other worktree to apply the stash in")
+				return m, nil
+			}
+			// Start on the worktree the stash was created in
+			m.stashTargetCursor = 0
+			for i, wt := range targets {
+				if entry.Branch != "" && wt.Branch == entry.Branch {
+					m.stashTargetCursor = i
+				}
+			}
+			m.state = StateStashApplyTo
+		}
+	case key.Matches(msg, m.keys.StashBranch):
+		if _, ok := m.selectedStash(); ok {
+			m.stashBranchInput.Reset()
+			m.stashBranchInput.Focus()
+			m.state = StateStashBranch
+			return m, textinput.Blink
+		}
+	case key.Matches(msg, m.keys.StashDrop):
 		if len(m.stashEntries) > 0 && m.stashCursor < len(m.stashEntries) {
 			entry := m.stashEntries[m.stashCursor]
 			return m, dropStash(m.stashWorktree.Path, entry.Index)
@@ -1020,17 +1916,127 @@ func (m Model) handleStashKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// selectedStash returns the stash under the cursor.
+func (m Model) selectedStash() (git.StashEntry, bool) {
+	if m.stashCursor < 0 || m.stashCursor >= len(m.stashEntries) {
+		return git.StashEntry{}, false
+	}
+	return m.stashEntries[m.stashCursor], true
+}
+
+// previewStash loads the diff of the selected stash, dropping the previous
+// preview so it never shows under the wrong entry.
+func (m *Model) previewStash() tea.Cmd {
+	m.stashDiff = ""
+	m.stashDiffErr = nil
+	entry, ok := m.selectedStash()
+	if !ok {
+		m.stashDiffIndex = -1
+		return nil
+	}
+	m.stashDiffIndex = entry.Index
+	return loadStashDiff(m.stashWorktree.Path, entry.Index)
+}
+
+// stashTargets returns the worktrees a stash can be applied in besides the
+// one the stash view was opened from.
+func (m Model) stashTargets() []git.Worktree {
+	var targets []git.Worktree
+	for _, wt := range m.worktrees {
+		if wt.IsPrunable || (m.stashWorktree != nil && wt.Path == m.stashWorktree.Path) {
+			continue
+		}
+		targets = append(targets, wt)
+	}
+	return targets
+}
+
+// stashOrigins returns, for each stash, the path of the worktree that has
+// its branch checked out, or "" when none does.
+func (m Model) stashOrigins() []string {
+	origins := make([]string, len(m.stashEntries))
+	for i, entry := range m.stashEntries {
+		if entry.Branch == "" {
+			continue
+		}
+		for j := range m.worktrees {
+			if m.worktrees[j].Branch == entry.Branch {
+				origins[i] = m.worktrees[j].ShortPath()
+				break
+			}
+		}
+	}
+	return origins
+}
+
+// handleStashApplyToKeys handles picking the worktree to apply a stash in.
+func (m Model) handleStashApplyToKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	targets := m.stashTargets()
+
+	switch {
+	case msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.Cancel):
+		m.state = StateStash
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		entry, ok := m.selectedStash()
+		if !ok || m.stashTargetCursor >= len(targets) {
+			m.state = StateStash
+			return m, nil
+		}
+		return m, applyStash(targets[m.stashTargetCursor].Path, entry.Index)
+	case key.Matches(msg, m.keys.Up):
+		if m.stashTargetCursor > 0 {
+			m.stashTargetCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.stashTargetCursor < len(targets)-1 {
+			m.stashTargetCursor++
+		}
+	case key.Matches(msg, m.keys.Home):
+		m.stashTargetCursor = 0
+	case key.Matches(msg, m.keys.End):
+		m.stashTargetCursor = max(len(targets)-1, 0)
+	}
+	return m, nil
+}
+
+// handleStashBranchKeys handles naming the branch created from a stash.
+func (m Model) handleStashBranchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateStash
+		m.stashBranchInput.Reset()
+		return m, nil
+	case tea.KeyEnter:
+		entry, ok := m.selectedStash()
+		branch := strings.TrimSpace(m.stashBranchInput.Value())
+		if !ok || branch == "" {
+			return m, nil
+		}
+		if err := git.ValidateBranchName(branch); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		return m, createWorktreeFromStash(m.config, branch, entry.Index)
+	}
+
+	var cmd tea.Cmd
+	m.stashBranchInput, cmd = m.stashBranchInput.Update(msg)
+	return m, cmd
+}
+
 // handleLayoutKeys handles key presses in layout selection.
 func (m Model) handleLayoutKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Number of options: layouts + "None" option
 	numOptions := len(m.config.Layouts) + 1
 
-	switch msg.Type {
-	case tea.KeyEsc:
+	switch {
+	case msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.Cancel):
 		m.state = StateList
 		m.layoutWorktree = nil
 		return m, nil
-	case tea.KeyEnter:
+	case key.Matches(msg, m.keys.Confirm):
 		// Determine selected layout (nil = "None" option)
 		var selectedLayout *config.LayoutConfig
 		if m.layoutCursor < len(m.config.Layouts) {
@@ -1072,18 +2078,283 @@ func (m Model) handlePruneConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if isConfirmKey(msg) {
+	if key.Matches(msg, m.keys.Yes) {
+		m.state = StateList
+		// Pinned worktrees survive unless explicitly included
+		var keep []string
+		if !m.includePinned {
+			for _, wt := range m.worktrees {
+				if wt.Pin > 0 && wt.IsPrunable {
+					keep = append(keep, wt.Path)
+				}
+			}
+		}
+		return m, pruneWorktrees(keep)
+	}
+	if key.Matches(msg, m.keys.No) {
+		m.state = StateList
+		return m, nil
+	}
+	if key.Matches(msg, m.keys.IncludePinned) {
+		m.includePinned = !m.includePinned
+	}
+
+	return m, nil
+}
+
+// handlePruneGoneConfirmKeys handles key presses in the prune-gone confirmation.
+func (m Model) handlePruneGoneConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.No) {
+		m.state = StateList
+		m.goneBranches = nil
+		m.goneLoading = false
+		return m, nil
+	}
+	if m.goneLoading {
+		return m, nil
+	}
+	if key.Matches(msg, m.keys.IncludePinned) {
+		m.includePinned = !m.includePinned
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keys.Yes) {
+		var prunable []git.GoneBranch
+		for _, g := range m.goneCandidates() {
+			if g.SkipReason == "" {
+				prunable = append(prunable, g)
+			}
+		}
+		m.state = StateList
+		m.goneBranches = nil
+		if len(prunable) == 0 {
+			return m, nil
+		}
+		return m, pruneGoneBranches(prunable)
+	}
+
+	return m, nil
+}
+
+// goneCandidates returns the prune-gone candidates, skipping pinned
+// worktrees unless they were explicitly included.
+func (m Model) goneCandidates() []git.GoneBranch {
+	candidates := slices.Clone(m.goneBranches)
+	for i, g := range candidates {
+		if g.SkipReason == "" && g.Worktree != nil && g.Worktree.Pin > 0 && !m.includePinned {
+			candidates[i].SkipReason = fmt.Sprintf("pinned (%s to include)", m.keys.IncludePinned.Help().Key)
+		}
+	}
+	return candidates
+}
+
+// handlePushConfirmForceKeys handles key presses in the force-push confirmation.
+func (m Model) handlePushConfirmForceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
 		m.state = StateList
-		return m, pruneWorktrees
+		m.pushTargets = nil
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keys.Yes) {
+		targets := m.pushTargets
+		m.pushTargets = nil
+		m.state = StatePushing
+		return m, pushWorktrees(m.config, targets, true)
+	}
+	if key.Matches(msg, m.keys.No) {
+		// Skip only the diverged branches; the rest still get pushed
+		var others []git.Worktree
+		for _, wt := range m.pushTargets {
+			if wt.Ahead == 0 || wt.Behind == 0 {
+				others = append(others, wt)
+			}
+		}
+		m.pushTargets = nil
+		if len(others) == 0 {
+			m.state = StateList
+			return m, nil
+		}
+		m.state = StatePushing
+		return m, pushWorktrees(m.config, others, false)
 	}
-	if isDenyKey(msg) {
+
+	return m, nil
+}
+
+// selectedBranchDetail returns the branch under the cursor in the branches view.
+func (m Model) selectedBranchDetail() (git.BranchInfo, bool) {
+	if m.branchCursor < 0 || m.branchCursor >= len(m.branchDetails) {
+		return git.BranchInfo{}, false
+	}
+	return m.branchDetails[m.branchCursor], true
+}
+
+// handleBranchesKeys handles key presses in the branches view.
+func (m Model) handleBranchesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.err = nil
+
+	if msg.Type == tea.KeyEsc {
 		m.state = StateList
+		m.branchDetails = nil
+		return m, nil
+	}
+	if m.branchesLoading {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.branchCursor > 0 {
+			m.branchCursor--
+			m.ensureBranchCursorVisible()
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		if m.branchCursor < len(m.branchDetails)-1 {
+			m.branchCursor++
+			m.ensureBranchCursorVisible()
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Home):
+		m.branchCursor = 0
+		m.branchViewOffset = 0
+		return m, nil
+	case key.Matches(msg, m.keys.End):
+		if len(m.branchDetails) > 0 {
+			m.branchCursor = len(m.branchDetails) - 1
+			m.ensureBranchCursorVisible()
+		}
+		return m, nil
+	}
+
+	branch, ok := m.selectedBranchDetail()
+
+	switch {
+	case key.Matches(msg, m.keys.BranchCreate):
+		if ok {
+			m.state = StateList
+			m.branchDetails = nil
+			return m.requestCreate(createRequest{branch: branch.Name})
+		}
+	case key.Matches(msg, m.keys.BranchDelete):
+		if ok {
+			if branch.Name == m.repo.DefaultBranch {
+				m.err = fmt.Errorf("cannot delete the default branch")
+				return m, nil
+			}
+			m.branchDeleteTargets = []git.BranchInfo{branch}
+			m.state = StateBranchDeleteConfirm
+		}
+	case key.Matches(msg, m.keys.BranchPrune): // All merged and squash-merged branches
+		var stale []git.BranchInfo
+		for _, b := range m.branchDetails {
+			if b.IsStale() && b.Name != m.repo.DefaultBranch {
+				stale = append(stale, b)
+			}
+		}
+		if len(stale) == 0 {
+			m.err = fmt.Errorf("no merged branches to prune")
+			return m, nil
+		}
+		m.branchDeleteTargets = stale
+		m.state = StateBranchDeleteConfirm
+	case key.Matches(msg, m.keys.BranchRename):
+		if ok {
+			m.branchInput.Placeholder = "new-branch-name"
+			m.branchInput.SetValue(branch.Name)
+			m.branchInput.Focus()
+			m.state = StateBranchRename
+			return m, textinput.Blink
+		}
+	case key.Matches(msg, m.keys.BranchUpstream):
+		if ok {
+			upstream := branch.Upstream
+			if upstream == "" {
+				upstream = git.GetPrimaryRemote(m.config.General.Remote) + "/" + branch.Name
+			}
+			m.branchInput.Placeholder = "remote/branch"
+			m.branchInput.SetValue(upstream)
+			m.branchInput.Focus()
+			m.state = StateBranchSetUpstream
+			return m, textinput.Blink
+		}
+	}
+
+	return m, nil
+}
+
+// handleBranchDeleteConfirmKeys handles key presses in the branch delete confirmation.
+func (m Model) handleBranchDeleteConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || key.Matches(msg, m.keys.No) {
+		m.state = StateBranches
+		m.branchDeleteTargets = nil
+		m.branchForceConfirm = false
 		return m, nil
 	}
 
+	if key.Matches(msg, m.keys.Yes) {
+		// Branches git doesn't see as merged need -D, which gets its own
+		// confirmation naming them
+		needsForce := slices.ContainsFunc(m.branchDeleteTargets, func(b git.BranchInfo) bool { return !b.IsMerged })
+		if needsForce && !m.branchForceConfirm {
+			m.branchForceConfirm = true
+			return m, nil
+		}
+		targets := m.branchDeleteTargets
+		force := m.branchForceConfirm
+		m.branchDeleteTargets = nil
+		m.branchForceConfirm = false
+		m.branchesLoading = true
+		return m, deleteBranches(targets, force)
+	}
+
 	return m, nil
 }
 
+// handleBranchInputKeys handles key presses when renaming a branch or setting
+// its upstream from the branches view.
+func (m Model) handleBranchInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = StateBranches
+		m.branchInput.Reset()
+		m.branchInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		branch, ok := m.selectedBranchDetail()
+		value := strings.TrimSpace(m.branchInput.Value())
+		if !ok || value == "" {
+			m.state = StateBranches
+			m.branchInput.Reset()
+			m.branchInput.Blur()
+			return m, nil
+		}
+		if m.state == StateBranchRename {
+			if value == branch.Name {
+				m.state = StateBranches
+				m.branchInput.Reset()
+				m.branchInput.Blur()
+				return m, nil
+			}
+			if err := m.validateNewBranchName(value); err != nil {
+				m.err = err
+				m.state = StateBranches
+				m.branchInput.Reset()
+				m.branchInput.Blur()
+				return m, nil
+			}
+			return m, renameBranchOnly(m.repo.MainWorktreeRoot, branch.Name, value)
+		}
+		return m, setBranchUpstream(branch.Name, value)
+	}
+
+	var cmd tea.Cmd
+	m.branchInput, cmd = m.branchInput.Update(msg)
+	return m, cmd
+}
+
 // handleBranchDeletionPrompt checks config and either deletes branch, prompts, or skips.
 func (m Model) handleBranchDeletionPrompt() (tea.Model, tea.Cmd) {
 	m.state = StateList
@@ -1131,7 +2402,7 @@ func (m Model) handleDeleteConfirmBranchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		return m, refreshWorktrees
 	}
 
-	if isConfirmKey(msg) {
+	if key.Matches(msg, m.keys.Yes) {
 		// Delete the branch
 		branch := m.deletedBranch
 		force := m.forceDeleteBranch
@@ -1140,7 +2411,7 @@ func (m Model) handleDeleteConfirmBranchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		m.forceDeleteBranch = false
 		return m, deleteBranch(branch, force)
 	}
-	if isDenyKey(msg) {
+	if key.Matches(msg, m.keys.No) {
 		// Don't delete branch, but still refresh because the worktree was already deleted
 		m.state = StateList
 		m.deletedBranch = ""
@@ -1156,45 +2427,56 @@ type worktreeSource []git.Worktree
 
 func (w worktreeSource) String(i int) string {
 	// Match against both branch name and short path for better results
-	// Using ShortPath avoids matching user home directory in absolute paths
-	return w[i].Branch + " " + w[i].ShortPath()
+	// Using ShortPath avoids matching user home directory in absolute paths.
+	// Notes and labels are searchable too.
+	s := w[i].Branch + " " + w[i].ShortPath()
+	if note := (state.Note{Text: w[i].Note, Labels: w[i].Labels}); !note.IsEmpty() {
+		s += " " + note.String()
+	}
+	return s
 }
 
 func (w worktreeSource) Len() int {
 	return len(w)
 }
 
-// applyFilter filters worktrees based on current filter input using fuzzy matching.
+// applyFilter filters worktrees by the qualifiers in the filter input, then
+// fuzzy-matches the remaining text.
 func (m *Model) applyFilter() {
-	filter := m.filterInput.Value()
-	if filter == "" {
-		// Make a copy to avoid modifying original
-		m.filteredWorktrees = make([]git.Worktree, len(m.worktrees))
-		copy(m.filteredWorktrees, m.worktrees)
+	m.query = parseFilterQuery(m.filterInput.Value(), time.Now())
+
+	// Copy so sorting never reorders m.worktrees
+	candidates := make([]git.Worktree, 0, len(m.worktrees))
+	for i := range m.worktrees {
+		if m.query.matches(&m.worktrees[i]) {
+			candidates = append(candidates, m.worktrees[i])
+		}
+	}
+	if m.query.text == "" {
+		m.filteredWorktrees = candidates
 	} else {
-		source := worktreeSource(m.worktrees)
-		matches := fuzzy.FindFrom(filter, source)
+		matches := fuzzy.FindFrom(m.query.text, worktreeSource(candidates))
 
 		m.filteredWorktrees = nil
 		for _, match := range matches {
-			m.filteredWorktrees = append(m.filteredWorktrees, m.worktrees[match.Index])
+			m.filteredWorktrees = append(m.filteredWorktrees, candidates[match.Index])
 		}
 	}
 
 	// Apply sorting
 	m.sortWorktrees()
 
+	m.rebuildRows()
+
 	// Ensure cursor is in bounds
-	if m.cursor >= len(m.filteredWorktrees) {
-		m.cursor = len(m.filteredWorktrees) - 1
+	if m.cursor >= m.rowCount() {
+		m.cursor = m.rowCount() - 1
 	}
 	if m.cursor < 0 {
 		m.cursor = 0
 	}
 
-	// Update cached column widths for render performance
-	widths := ui.CalculateColumnWidths(m.filteredWorktrees)
-	m.cachedColumnWidths = &widths
+	m.updateColumnWidths()
 
 	// Build path-to-index map for O(1) lookup in filteredWorktrees
 	m.filteredIndexByPath = make(map[string]int, len(m.filteredWorktrees))
@@ -1228,14 +2510,22 @@ func (m *Model) sortWorktrees() {
 		return
 	}
 
-	// Preserve fuzzy match score ordering when filter is active
-	if m.filterInput.Value() != "" {
+	// Preserve fuzzy match score ordering when filtering by text
+	if m.query.text != "" {
 		return
 	}
 
 	sort.SliceStable(m.filteredWorktrees, func(i, j int) bool {
 		a, b := m.filteredWorktrees[i], m.filteredWorktrees[j]
 
+		// Pinned worktrees stay on top in pin order, whatever the sort mode
+		if a.Pin > 0 || b.Pin > 0 {
+			if a.Pin == 0 || b.Pin == 0 {
+				return a.Pin > 0
+			}
+			return a.Pin < b.Pin
+		}
+
 		switch m.sortMode {
 		case SortName:
 			return a.Branch < b.Branch
@@ -1257,6 +2547,21 @@ func (m *Model) sortWorktrees() {
 			}
 			return a.Branch < b.Branch
 
+		case SortRecent:
+			// Most recently opened first; never-opened ones by name
+			ta, tb := m.recent[a.Path], m.recent[b.Path]
+			if !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+			return a.Branch < b.Branch
+
+		case SortLastCommit:
+			// Newest commit first, then by name
+			if !a.CommitTime.Equal(b.CommitTime) {
+				return a.CommitTime.After(b.CommitTime)
+			}
+			return a.Branch < b.Branch
+
 		default: // SortDefault
 			// Current first, main second, then alphabetical
 			if a.IsCurrent != b.IsCurrent {
@@ -1275,6 +2580,7 @@ func (m Model) View() string {
 	return ui.Render(ui.RenderParams{
 		State:               int(m.state),
 		Worktrees:           m.filteredWorktrees,
+		Rows:                m.rows,
 		Cursor:              m.cursor,
 		ViewOffset:          m.viewOffset,
 		VisibleCount:        m.visibleItemCount(),
@@ -1286,6 +2592,8 @@ func (m Model) View() string {
 		Config:              m.config,
 		FilterInput:         m.filterInput.View(),
 		FilterValue:         m.filterInput.Value(),
+		FilterQualifiers:    m.query.terms(),
+		FilterInvalid:       m.query.invalid,
 		CreateInput:         m.createInput.View(),
 		DeleteWorktree:      m.deleteWorktree,
 		SafetyInfo:          m.safetyInfo,
@@ -1296,22 +2604,64 @@ func (m Model) View() string {
 		BaseViewOffset:      m.baseViewOffset,
 		VisibleBranchCount:  m.visibleBranchCount(),
 		CreateBranch:        m.createBranch,
+		CreateRemote:        m.createRemote,
+		CreateValidation:    m.createValidation(),
+		CreateSuggestion:    m.createSuggestion(),
+		CreateHint:          m.createHint(),
+		HasBranchTemplates:  len(m.config.Branch.Templates) > 0,
+		TemplatePreview:     m.templatePreview(),
+		TemplateField:       m.currentTemplateField(),
+		TemplateInput:       m.templateInput.View(),
 		RenameWorktree:      m.renameWorktree,
 		RenameInput:         m.renameInput.View(),
+		MoveWorktree:        m.moveWorktree,
+		MoveInput:           m.moveInput.View(),
+		LockWorktree:        m.lockWorktree,
+		LockInput:           m.lockInput.View(),
+		NoteWorktree:        m.noteWorktree,
+		NoteInput:           m.noteInput.View(),
+		PaletteInput:        m.paletteInput.View(),
+		PaletteEntries:      m.paletteEntries(),
+		PaletteCursor:       m.paletteCursor,
 		StashWorktree:       m.stashWorktree,
 		StashEntries:        m.stashEntries,
 		StashCursor:         m.stashCursor,
+		StashOrigins:        m.stashOrigins(),
+		StashDiff:           m.stashDiff,
+		StashDiffErr:        m.stashDiffErr,
+		StashTargets:        m.stashTargets(),
+		StashTargetCursor:   m.stashTargetCursor,
+		StashBranchInput:    m.stashBranchInput.View(),
 		LayoutWorktree:      m.layoutWorktree,
 		LayoutCursor:        m.layoutCursor,
 		SpinnerFrame:        m.spinner.View(),
 		HelpSections:        m.keys.HelpSections(),
+		Keys:                m.keys.Hints(),
+		PendingKeys:         strings.Join(m.pendingKeys, " "),
 		PendingWindowsCount: len(m.pendingWindowsClose),
 		PendingWindowsName:  exec.GetMultiplexer().WindowName(),
 		ConfigWarnings:      m.configWarnings,
 		LastPruneCount:      m.lastPruneCount,
+		SparseCursor:        m.sparseCursor,
+		PendingCreate:       m.pendingCreateLabel(),
+		WidenWorktree:       m.widenWorktree,
+		WidenInput:          m.widenInput.View(),
+		CopyProgress:        m.copyProgress,
 		DeletedBranch:       m.deletedBranch,
 		SortMode:            m.sortMode.String(),
 		CachedColumnWidths:  m.cachedColumnWidths,
+		Marked:              m.marked,
+		PushTargets:         m.pushTargets,
+		BranchDetails:       m.branchDetails,
+		BranchCursor:        m.branchCursor,
+		BranchViewOffset:    m.branchViewOffset,
+		BranchesLoading:     m.branchesLoading,
+		BranchInput:         m.branchInput.View(),
+		BranchDeleteTargets: m.branchDeleteTargets,
+		BranchForceConfirm:  m.branchForceConfirm,
+		GoneBranches:        m.goneCandidates(),
+		IncludePinned:       m.includePinned,
+		GoneLoading:         m.goneLoading,
 	})
 }
 
@@ -1334,6 +2684,9 @@ func (m Model) SelectedWorktree() *git.Worktree {
 func (m Model) isLoading() bool {
 	return m.loading ||
 		m.state == StateFetching ||
+		m.state == StatePushing ||
+		(m.state == StateBranches && m.branchesLoading) ||
+		(m.state == StatePruneGoneConfirm && m.goneLoading) ||
 		(m.state == StateDelete && m.safetyInfo == nil)
 }
 
@@ -1341,14 +2694,29 @@ func (m Model) isLoading() bool {
 
 func loadWorktrees() tea.Msg {
 	worktrees, fromCache, err := git.ListCached()
+	attachNotes(worktrees)
 	return WorktreesCachedMsg{Worktrees: worktrees, FromCache: fromCache, Err: err}
 }
 
 func refreshWorktrees() tea.Msg {
 	worktrees, err := git.ListAndCache()
+	attachNotes(worktrees)
 	return WorktreesLoadedMsg{Worktrees: worktrees, Err: err}
 }
 
+// attachNotes fills in the notes and labels saved for each worktree.
+func attachNotes(worktrees []git.Worktree) {
+	if len(worktrees) == 0 {
+		return
+	}
+	notes := state.LoadNotes()
+	for i := range worktrees {
+		note := notes[filepath.Clean(worktrees[i].Path)]
+		worktrees[i].Note = note.Text
+		worktrees[i].Labels = note.Labels
+	}
+}
+
 func loadBranchesWithTypes() tea.Msg {
 	branches, err := git.ListAllBranchesWithWorktreeStatus()
 	return BranchesLoadedMsg{Branches: branches, Err: err}
@@ -1361,22 +2729,120 @@ func checkSafety(path, branch, defaultBranch string) tea.Cmd {
 	}
 }
 
-func createWorktree(cfg *config.Config, branch string, isNew bool, baseBranch string) tea.Cmd {
-	return func() tea.Msg {
-		repo, _ := git.GetRepo()
-		path := filepath.Join(cfg.General.WorktreeDir, sanitizePath(branch))
-		if repo != nil {
-			// Always use MainWorktreeRoot so worktrees are created at the project root
-			path = filepath.Join(repo.MainWorktreeRoot, cfg.General.WorktreeDir, sanitizePath(branch))
+// DefaultWorktreePath returns where grove places the worktree for branch in
+// the current repository:
+// general.worktree_path expanded when set, else <worktree_dir>/<branch>.
+func DefaultWorktreePath(cfg *config.Config, branch string) string {
+	return defaultWorktreePathOn(cfg, branch, time.Now().Format(pathDateLayout))
+}
+
+// pathDateLayout is the format of the {date} path variable.
+const pathDateLayout = "2006-01-02"
+
+var pathDateRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// defaultWorktreePathOn is DefaultWorktreePath with {date} set to date.
+func defaultWorktreePathOn(cfg *config.Config, branch, date string) string {
+	repo, _ := git.GetRepo()
+	root, repoName := "", ""
+	if repo != nil {
+		// Always use MainWorktreeRoot so worktrees are created at the project root
+		root, repoName = repo.MainWorktreeRoot, filepath.Base(repo.MainWorktreeRoot)
+	}
+
+	if cfg.General.WorktreePath == "" {
+		return filepath.Join(root, cfg.General.WorktreeDir, sanitizePath(branch))
+	}
+
+	path := expandWorktreePath(cfg.General.WorktreePath, repoName, branch, date)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return filepath.Clean(path)
+}
+
+// expandWorktreePath fills in a general.worktree_path template.
+func expandWorktreePath(tmpl, repoName, branch, date string) string {
+	if tmpl == "~" || strings.HasPrefix(tmpl, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			tmpl = filepath.Join(home, tmpl[1:])
+		}
+	}
+
+	branchShort := branch
+	if i := strings.LastIndex(branch, "/"); i >= 0 {
+		branchShort = branch[i+1:]
+	}
+
+	return strings.NewReplacer(
+		"{repo}", repoName,
+		"{branch}", sanitizePath(branch),
+		"{branch_short}", sanitizePath(branchShort),
+		"{user}", sanitizePath(currentUserName()),
+		"{date}", date,
+	).Replace(tmpl)
+}
+
+// currentUserName returns the login name for the {user} path variable.
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		// Windows reports DOMAIN\user
+		name := u.Username
+		if i := strings.LastIndex(name, "\\"); i >= 0 {
+			name = name[i+1:]
+		}
+		return name
+	}
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
 		}
-		err := git.Create(path, branch, isNew, baseBranch)
-		return WorktreeCreatedMsg{Path: path, Branch: branch, Err: err}
+	}
+	return "user"
+}
+
+func createWorktree(cfg *config.Config, branch string, isNew bool, baseBranch string, sparse *git.SparseCheckout) tea.Cmd {
+	return func() tea.Msg {
+		path := DefaultWorktreePath(cfg, branch)
+		err := git.Create(path, branch, isNew, baseBranch, sparse)
+		return worktreeCreated(cfg, path, branch, err)
 	}
 }
 
+func createTrackingWorktree(cfg *config.Config, branch, remoteRef string, sparse *git.SparseCheckout) tea.Cmd {
+	return func() tea.Msg {
+		path := DefaultWorktreePath(cfg, branch)
+		err := git.CreateTracking(path, branch, remoteRef, sparse)
+		return worktreeCreated(cfg, path, branch, err)
+	}
+}
+
+// createDetachedWorktree creates a worktree at rev with a detached HEAD,
+// placed under detached/<short-sha>.
+func createDetachedWorktree(cfg *config.Config, rev, shortSHA string, sparse *git.SparseCheckout) tea.Cmd {
+	return func() tea.Msg {
+		path := DefaultWorktreePath(cfg, "detached/"+shortSHA)
+		err := git.CreateDetached(path, rev, sparse)
+		return worktreeCreated(cfg, path, shortSHA+" (detached)", err)
+	}
+}
+
+// worktreeCreated finishes a successful create by initializing submodules
+// when configured, before anything opens the new worktree.
+func worktreeCreated(cfg *config.Config, path, branch string, err error) WorktreeCreatedMsg {
+	msg := WorktreeCreatedMsg{Path: path, Branch: branch, Err: err}
+	if err == nil && cfg.Worktree.InitSubmodules {
+		msg.SubmoduleErr = git.UpdateSubmodules(path, cfg.Worktree.SubmoduleReference)
+	}
+	return msg
+}
+
 func deleteWorktree(path string, force bool) tea.Cmd {
 	return func() tea.Msg {
 		err := git.Remove(path, force)
+		if err == nil {
+			_ = state.ForgetWorktree(path)
+		}
 		return WorktreeDeletedMsg{Path: path, Err: err}
 	}
 }
@@ -1392,7 +2858,15 @@ func openWorktree(cfg *config.Config, wt *git.Worktree, currentWt *git.Worktree,
 		}
 
 		isNew, err := exec.OpenWithConfig(cfg, wt, layout)
-		return WorktreeOpenedMsg{Err: err, IsNewWindow: isNew}
+		if err == nil {
+			// Record the worktree we leave too, so it becomes the previous one
+			opened := []string{wt.Path}
+			if currentWt != nil && currentWt.Path != wt.Path {
+				opened = []string{currentWt.Path, wt.Path}
+			}
+			_ = state.RecordOpen(opened...)
+		}
+		return WorktreeOpenedMsg{Path: wt.Path, Err: err, IsNewWindow: isNew}
 	}
 }
 
@@ -1401,9 +2875,71 @@ func fetchAll() tea.Msg {
 	return FetchCompletedMsg{Err: err}
 }
 
-func pruneWorktrees() tea.Msg {
-	count, err := git.Prune()
-	return PruneCompletedMsg{PrunedCount: count, Err: err}
+// pushWorktrees pushes each worktree's branch in turn. Branches without an
+// upstream are pushed with -u to the primary remote, the rest to their
+// upstream. When force is set, diverged branches are pushed with
+// --force-with-lease.
+func pushWorktrees(cfg *config.Config, worktrees []git.Worktree, force bool) tea.Cmd {
+	return func() tea.Msg {
+		remote := git.GetPrimaryRemote(cfg.General.Remote)
+		var errs []error
+		pushed := 0
+		for _, wt := range worktrees {
+			opts := git.PushOptions{
+				Remote:         remote,
+				SetUpstream:    !wt.HasUpstream,
+				ForceWithLease: force && wt.Ahead > 0 && wt.Behind > 0,
+			}
+			if err := git.Push(wt.Path, wt.Branch, opts); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			pushed++
+		}
+		return PushCompletedMsg{Pushed: pushed, Err: errors.Join(errs...)}
+	}
+}
+
+func findGoneBranches(worktrees []git.Worktree, defaultBranch string) tea.Cmd {
+	return func() tea.Msg {
+		// Make a copy to avoid race conditions
+		wtCopy := make([]git.Worktree, len(worktrees))
+		copy(wtCopy, worktrees)
+		branches, err := git.FindGoneBranches(wtCopy, defaultBranch)
+		return GoneBranchesCheckedMsg{Branches: branches, Err: err}
+	}
+}
+
+// pruneGoneBranches removes each worktree (if any) and then its branch.
+// Candidates were already safety-checked, so the branch is force-deleted:
+// squash-merged branches would otherwise be refused by git branch -d.
+func pruneGoneBranches(branches []git.GoneBranch) tea.Cmd {
+	return func() tea.Msg {
+		var errs []error
+		pruned := 0
+		for _, g := range branches {
+			if g.Worktree != nil {
+				if err := git.Remove(g.Worktree.Path, false); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				_ = state.ForgetWorktree(g.Worktree.Path)
+			}
+			if err := git.DeleteBranch(g.Branch, true); err != nil {
+				errs = append(errs, fmt.Errorf("delete %s: %w", g.Branch, err))
+				continue
+			}
+			pruned++
+		}
+		return PruneGoneCompletedMsg{Pruned: pruned, Err: errors.Join(errs...)}
+	}
+}
+
+func pruneWorktrees(keep []string) tea.Cmd {
+	return func() tea.Msg {
+		count, err := git.Prune(keep...)
+		return PruneCompletedMsg{PrunedCount: count, Err: err}
+	}
 }
 
 func deleteBranch(branch string, force bool) tea.Cmd {
@@ -1413,10 +2949,165 @@ func deleteBranch(branch string, force bool) tea.Cmd {
 	}
 }
 
-func renameBranch(worktreePath, oldName, newName string) tea.Cmd {
+// renameBranch renames the worktree's branch, or for a detached worktree
+// creates the branch at its HEAD. With worktree.move_on_rename the
+// directory follows the branch, but only when it still sits at the default
+// location for the old name; custom placements are left alone.
+func renameBranch(cfg *config.Config, wt git.Worktree, newName string) tea.Cmd {
+	return func() tea.Msg {
+		oldName := wt.Branch
+		oldPathName := oldName
+		if wt.IsDetached {
+			oldPathName = "detached/" + strings.TrimSuffix(oldName, " (detached)")
+		}
+		date, atDefault := matchDefaultPath(cfg, oldPathName, wt.Path)
+		windows := exec.FindWindowsForPath(wt.Path)
+
+		var err error
+		if wt.IsDetached {
+			err = git.ConvertToBranch(wt.Path, newName)
+		} else {
+			err = git.RenameBranch(wt.Path, oldName, newName)
+		}
+		if err != nil {
+			return BranchRenamedMsg{OldName: oldName, NewName: newName, Err: err}
+		}
+		wt.Branch = newName
+
+		if cfg.Worktree.MoveOnRename && atDefault {
+			// Keep the creation date so the worktree stays in its {date} folder
+			newPath := defaultWorktreePathOn(cfg, newName, date)
+			if err := git.Move(wt.Path, newPath); err != nil {
+				renameWindows(cfg, windows, &wt)
+				return BranchRenamedMsg{OldName: oldName, NewName: newName, Err: fmt.Errorf("branch renamed but move failed: %w", err)}
+			}
+			_ = state.MoveWorktree(wt.Path, newPath)
+			wt.Path = newPath
+		}
+
+		renameWindows(cfg, windows, &wt)
+		return BranchRenamedMsg{OldName: oldName, NewName: newName}
+	}
+}
+
+// matchDefaultPath reports whether path is where grove would place branch,
+// along with the {date} it matched. {date} acts as a wildcard: today and
+// every date found in path are tried, so worktrees created on an earlier
+// day still count as default placements.
+func matchDefaultPath(cfg *config.Config, branch, path string) (string, bool) {
+	resolved := git.ResolvePath(path)
+	dates := append([]string{time.Now().Format(pathDateLayout)}, pathDateRe.FindAllString(path, -1)...)
+	for _, date := range dates {
+		if git.ResolvePath(defaultWorktreePathOn(cfg, branch, date)) == resolved {
+			return date, true
+		}
+	}
+	return "", false
+}
+
+// moveWorktree relocates a worktree. Windows are looked up before the move
+// because multiplexers match them by the old path.
+func moveWorktree(cfg *config.Config, wt git.Worktree, newPath string) tea.Cmd {
+	return func() tea.Msg {
+		windows := exec.FindWindowsForPath(wt.Path)
+		if err := git.Move(wt.Path, newPath); err != nil {
+			return WorktreeMovedMsg{OldPath: wt.Path, NewPath: newPath, Err: err}
+		}
+		_ = state.MoveWorktree(wt.Path, newPath)
+		oldPath := wt.Path
+		wt.Path = newPath
+		renameWindows(cfg, windows, &wt)
+		return WorktreeMovedMsg{OldPath: oldPath, NewPath: newPath}
+	}
+}
+
+// widenSparse adds paths to a sparse worktree, or with no paths turns
+// sparse checkout off. Adding a profile extends the recorded name
+// ("api+web") so the detail panel shows what the worktree is made of.
+func widenSparse(path string, paths []string, addedProfile string) tea.Cmd {
+	return func() tea.Msg {
+		current, err := git.GetSparseStatus(path)
+		if err != nil {
+			return SparseWidenedMsg{Path: path, Err: err}
+		}
+		if current == nil {
+			return SparseWidenedMsg{Path: path, Err: fmt.Errorf("%s is not a sparse checkout", path)}
+		}
+
+		profile := ""
+		if addedProfile != "" {
+			profile = addedProfile
+			if current.Profile != "" {
+				profile = current.Profile + "+" + addedProfile
+			}
+		}
+		err = git.WidenSparse(path, paths, profile)
+		return SparseWidenedMsg{Path: path, Err: err}
+	}
+}
+
+func lockWorktree(path, reason string) tea.Cmd {
+	return func() tea.Msg {
+		err := git.Lock(path, reason)
+		return WorktreeLockChangedMsg{Path: path, Locked: true, Err: err}
+	}
+}
+
+func saveNote(path string, note state.Note) tea.Cmd {
+	return func() tea.Msg {
+		err := state.SetNote(path, note)
+		return NoteSavedMsg{Path: path, Note: note, Err: err}
+	}
+}
+
+func unlockWorktree(path string) tea.Cmd {
+	return func() tea.Msg {
+		err := git.Unlock(path)
+		return WorktreeLockChangedMsg{Path: path, Locked: false, Err: err}
+	}
+}
+
+// renameWindows gives open windows/tabs the name grove would use for wt now.
+func renameWindows(cfg *config.Config, windows []string, wt *git.Worktree) {
+	name := exec.WindowNameFor(cfg, wt)
+	for _, w := range windows {
+		_ = exec.RenameWindow(w, name)
+	}
+}
+
+func loadBranchDetails(defaultBranch string) tea.Cmd {
+	return func() tea.Msg {
+		branches, err := git.ListBranchesWithoutWorktrees(defaultBranch)
+		return BranchDetailsLoadedMsg{Branches: branches, Err: err}
+	}
+}
+
+// deleteBranches deletes branches from the branches view. Squash-merged and
+// unmerged branches need -D since git can't see them in the default branch;
+// they are only force-deleted when force is set, otherwise git refuses.
+func deleteBranches(branches []git.BranchInfo, force bool) tea.Cmd {
+	return func() tea.Msg {
+		var errs []error
+		for _, b := range branches {
+			if err := git.DeleteBranch(b.Name, force && !b.IsMerged); err != nil {
+				errs = append(errs, fmt.Errorf("delete %s: %w", b.Name, err))
+			}
+		}
+		return BranchOperationCompletedMsg{Operation: "delete", Err: errors.Join(errs...)}
+	}
+}
+
+func renameBranchOnly(repoRoot, oldName, newName string) tea.Cmd {
 	return func() tea.Msg {
-		err := git.RenameBranch(worktreePath, oldName, newName)
-		return BranchRenamedMsg{OldName: oldName, NewName: newName, Err: err}
+		err := git.RenameBranch(repoRoot, oldName, newName)
+		return BranchOperationCompletedMsg{Operation: "rename", Err: err}
+	}
+}
+
+func setBranchUpstream(branch, upstream string) tea.Cmd {
+	return func() tea.Msg {
+		err := git.SetBranchUpstream(branch, upstream)
+		return BranchOperationCompletedMsg{Operation: "upstream", Err: err}
 	}
 }
 
@@ -1430,22 +3121,55 @@ func loadStashList(worktreePath string) tea.Cmd {
 func loadWorktreeDetail(worktreePath string) tea.Cmd {
 	return func() tea.Msg {
 		hash, msg, time, _ := git.GetLastCommit(worktreePath)
+		sparse, _ := git.GetSparseStatus(worktreePath)
 		return DetailLoadedMsg{
 			Path:              worktreePath,
 			LastCommitHash:    hash,
 			LastCommitMessage: msg,
 			LastCommitTime:    time,
+			Sparse:            sparse,
 		}
 	}
 }
 
-func loadUpstreamStatus(worktrees []git.Worktree) tea.Cmd {
+// copyBackgroundStatus copies the fields loaded by loadUpstreamStatus.
+func copyBackgroundStatus(dst *git.Worktree, src git.Worktree, merged bool) {
+	dst.Ahead = src.Ahead
+	dst.Behind = src.Behind
+	dst.HasUpstream = src.HasUpstream
+	dst.UpstreamGone = src.UpstreamGone
+	if !src.CommitTime.IsZero() {
+		dst.CommitTime = src.CommitTime
+	}
+	if src.LastCommitHash != "" {
+		dst.LastCommitHash = src.LastCommitHash
+		dst.LastCommitMessage = src.LastCommitMessage
+		dst.LastCommitTime = src.LastCommitTime
+	}
+	if merged {
+		dst.IsMerged = src.IsMerged
+		dst.UniqueCommits = src.UniqueCommits
+	}
+}
+
+// loadUpstreamStatus fetches tracking info in the background, plus the last
+// commit and merge status when the list has columns that show them. Merge
+// status is also loaded when wantMerged is set, for the filter.
+func loadUpstreamStatus(worktrees []git.Worktree, cfg *config.Config, repo *git.Repo, wantMerged bool) tea.Cmd {
+	lastCommit := cfg != nil && (cfg.UI.HasColumn("age") || cfg.UI.HasColumn("last_commit"))
+	merged := (wantMerged || cfg != nil && cfg.UI.HasColumn("merged")) && repo != nil
+	defaultBranch := ""
+	if repo != nil {
+		defaultBranch = repo.DefaultBranch
+	}
 	return func() tea.Msg {
 		// Make a copy to avoid race conditions
 		wtCopy := make([]git.Worktree, len(worktrees))
 		copy(wtCopy, worktrees)
 		git.EnrichWorktreesUpstream(wtCopy)
-		return UpstreamLoadedMsg{Worktrees: wtCopy}
+		_ = git.EnrichWorktreesCommitTime(wtCopy)
+		git.EnrichWorktreesColumns(wtCopy, defaultBranch, lastCommit, merged)
+		return UpstreamLoadedMsg{Worktrees: wtCopy, Merged: merged}
 	}
 }
 
@@ -1463,6 +3187,23 @@ func applyStash(worktreePath string, index int) tea.Cmd {
 	}
 }
 
+func loadStashDiff(worktreePath string, index int) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := git.StashDiff(worktreePath, index)
+		return StashDiffLoadedMsg{Index: index, Diff: diff, Err: err}
+	}
+}
+
+// createWorktreeFromStash creates a worktree on a new branch holding the
+// stash, which is dropped once applied.
+func createWorktreeFromStash(cfg *config.Config, branch string, index int) tea.Cmd {
+	return func() tea.Msg {
+		path := DefaultWorktreePath(cfg, branch)
+		err := git.CreateFromStash(path, branch, index)
+		return worktreeCreated(cfg, path, branch, err)
+	}
+}
+
 func dropStash(worktreePath string, index int) tea.Cmd {
 	return func() tea.Msg {
 		err := git.DropStash(worktreePath, index)
@@ -1470,21 +3211,130 @@ func dropStash(worktreePath string, index int) tea.Cmd {
 	}
 }
 
+// runPostCreateOperations copies the configured files into a new worktree.
+// The copy runs in the background and reports through a channel: each
+// FileCopyProgressMsg carries the channel so Update can wait for the next
+// message, ending with FileCopyCompletedMsg.
 func runPostCreateOperations(cfg *config.Config, path string) tea.Cmd {
 	return func() tea.Msg {
 		// Copy files if patterns are configured
-		if len(cfg.Worktree.CopyPatterns) > 0 {
-			repo, _ := git.GetRepo()
-			if repo != nil {
-				err := git.CopyFiles(repo.MainWorktreeRoot, path, cfg.Worktree.CopyPatterns, cfg.Worktree.CopyIgnores)
-				if err != nil {
-					return FileCopyCompletedMsg{Err: err}
-				}
+		if len(cfg.Worktree.CopyPatterns) == 0 {
+			return FileCopyCompletedMsg{Err: nil}
+		}
+		repo, _ := git.GetRepo()
+		if repo == nil {
+			return FileCopyCompletedMsg{Err: nil}
+		}
+
+		updates := make(chan tea.Msg, 1)
+		go func() {
+			var lastSent time.Time
+			opts := git.CopyOptions{
+				Patterns: cfg.Worktree.CopyPatterns,
+				Ignores:  cfg.Worktree.CopyIgnores,
+				Mode:     cfg.Worktree.CopyMode,
+				Modes:    cfg.Worktree.CopyModes,
+				Progress: func(p git.CopyProgress) {
+					// Throttle redraws; drop updates the UI hasn't caught up with
+					if time.Since(lastSent) < copyProgressInterval {
+						return
+					}
+					lastSent = time.Now()
+					select {
+					case updates <- FileCopyProgressMsg{Progress: p, updates: updates}:
+					default:
+					}
+				},
 			}
+			err := git.CopyFiles(repo.MainWorktreeRoot, path, opts)
+			updates <- FileCopyCompletedMsg{Err: err}
+		}()
+		return <-updates
+	}
+}
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// configModTime returns the config file's modification time, or the zero
+// time when there is no config file.
+func configModTime() time.Time {
+	info, err := os.Stat(config.ConfigPath())
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// watchConfig checks the config file once per interval and reloads it when
+// its modification time moves on from modTime.
+func watchConfig(modTime time.Time) tea.Cmd {
+	return tea.Tick(configPollInterval, func(time.Time) tea.Msg {
+		path := config.ConfigPath()
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(modTime) {
+			return configPollMsg{modTime: modTime}
+		}
+		cfg, err := config.LoadFromPath(path)
+		return ConfigReloadedMsg{Config: cfg, ModTime: info.ModTime(), Err: err}
+	})
+}
+
+// copyProgressInterval limits how often copy progress is redrawn.
+const copyProgressInterval = 100 * time.Millisecond
+
+// waitForCopyUpdate receives the next message from a running copy.
+func waitForCopyUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// allocatePorts reserves the new worktree's port block in the background.
+func allocatePorts(cfg *config.Config, path string) tea.Cmd {
+	return func() tea.Msg {
+		block, err := AllocatePorts(cfg, path)
+		return PortsAllocatedMsg{Path: path, Block: block, Err: err}
+	}
+}
+
+// AllocatePorts reserves the new worktree's port block and, with
+// ports.write_env, records it in .env.grove for dev servers to pick up.
+func AllocatePorts(cfg *config.Config, path string) (state.PortBlock, error) {
+	repo, err := git.GetRepo()
+	if err != nil {
+		return state.PortBlock{}, err
+	}
+	live, _ := git.LivePaths()
+	block, err := state.AllocatePorts(repo.MainWorktreeRoot, path, live, cfg.Ports.Base, cfg.Ports.BlockSize, cfg.Ports.MaxBlocks)
+	if err != nil {
+		return state.PortBlock{}, err
+	}
+	if cfg.Ports.WriteEnv {
+		if err := writePortEnv(path, block); err != nil {
+			return block, err
 		}
+	}
+	return block, nil
+}
+
+// portEnvFile is written into worktrees when ports.write_env is set.
+const portEnvFile = ".env.grove"
 
-		return FileCopyCompletedMsg{Err: nil}
+// writePortEnv writes the block as PORT plus GROVE_PORT, GROVE_PORT_1, ...
+// and keeps the file out of git status so it doesn't make the worktree dirty.
+func writePortEnv(path string, block state.PortBlock) error {
+	var b strings.Builder
+	b.WriteString("# Ports reserved for this worktree by grove\n")
+	fmt.Fprintf(&b, "PORT=%d\n", block.Port(0))
+	fmt.Fprintf(&b, "GROVE_PORT=%d\n", block.Port(0))
+	for i := 1; i < block.Size; i++ {
+		fmt.Fprintf(&b, "GROVE_PORT_%d=%d\n", i, block.Port(i))
 	}
+	if err := os.WriteFile(filepath.Join(path, portEnvFile), []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return git.EnsureExcluded(portEnvFile)
 }
 
 // Helper functions
@@ -1499,6 +3349,61 @@ func sanitizePath(branch string) string {
 	return result
 }
 
+// slugify turns free text such as a ticket title into a lowercase,
+// hyphen-separated name: "Fix login crash (iOS 17)!" -> "fix-login-crash-ios-17".
+func slugify(s string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingDash = false
+		} else {
+			pendingDash = true
+		}
+	}
+	return b.String()
+}
+
+// slugifyBranch slugifies each path component, keeping prefixes like feat/.
+func slugifyBranch(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		if slug := slugify(part); slug != "" {
+			parts = append(parts, slug)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+var templateFieldRe = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// templateFields returns the distinct {field} names in a branch template, in order.
+func templateFields(tmpl string) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, match := range templateFieldRe.FindAllStringSubmatch(tmpl, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			fields = append(fields, match[1])
+		}
+	}
+	return fields
+}
+
+// expandBranchTemplate fills in template fields; missing ones stay as {field}.
+func expandBranchTemplate(tmpl string, values map[string]string) string {
+	return templateFieldRe.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if value, ok := values[match[1:len(match)-1]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
 func replaceAll(s, old, new string) string {
 	for {
 		next := replace(s, old, new)
@@ -1525,7 +3430,11 @@ func (m Model) visibleItemCount() int {
 	// - Footer: 2 lines (divider + help)
 	// - Box borders: 2 lines
 	// Total overhead: 6 lines
-	const overhead = 6
+	overhead := 6
+	// The filter view adds a qualifier hint line under its input
+	if m.state == StateFilter {
+		overhead++
+	}
 
 	// Each worktree entry is a single line
 	availableLines := m.height - overhead
@@ -1565,25 +3474,167 @@ func (m *Model) ensureCursorVisible() {
 	m.viewOffset = ensureOffsetVisible(m.cursor, m.viewOffset, m.visibleItemCount())
 }
 
+// rowCount returns the number of rows in the list: group headers and
+// worktrees in tree mode, worktrees otherwise.
+func (m Model) rowCount() int {
+	if m.treeView {
+		return len(m.rows)
+	}
+	return len(m.filteredWorktrees)
+}
+
+// cursorWorktree returns the worktree under the cursor, or nil when the list
+// is empty or the cursor is on a tree group header.
+func (m Model) cursorWorktree() *git.Worktree {
+	i := m.cursor
+	if m.treeView {
+		if i < 0 || i >= len(m.rows) || m.rows[i].IsGroup() {
+			return nil
+		}
+		i = m.rows[i].Index
+	}
+	if i < 0 || i >= len(m.filteredWorktrees) {
+		return nil
+	}
+	return &m.filteredWorktrees[i]
+}
+
+// cursorGroup returns the tree group under the cursor, or nil.
+func (m Model) cursorGroup() *ui.TreeGroup {
+	if !m.treeView || m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].Group
+}
+
+// moveCursorToPath puts the cursor on the worktree at path, if it is shown.
+func (m *Model) moveCursorToPath(path string) {
+	i, ok := m.filteredIndexByPath[path]
+	if !ok {
+		return
+	}
+	if m.treeView {
+		for r, row := range m.rows {
+			if !row.IsGroup() && row.Index == i {
+				m.cursor = r
+				m.ensureCursorVisible()
+				return
+			}
+		}
+		return
+	}
+	m.cursor = i
+	m.ensureCursorVisible()
+}
+
+// resortKeepingCursor reapplies filter and sort, keeping the cursor on the
+// same worktree.
+func (m *Model) resortKeepingCursor() {
+	var selectedPath string
+	if wt := m.cursorWorktree(); wt != nil {
+		selectedPath = wt.Path
+	}
+	m.applyFilter()
+	if selectedPath != "" {
+		m.moveCursorToPath(selectedPath)
+	}
+}
+
+// moveCursorToGroup puts the cursor on the header of the group with prefix.
+func (m *Model) moveCursorToGroup(prefix string) {
+	for r, row := range m.rows {
+		if row.IsGroup() && row.Group.Prefix == prefix {
+			m.cursor = r
+			m.ensureCursorVisible()
+			return
+		}
+	}
+}
+
+// rebuildRows regroups filteredWorktrees for tree mode. Groups stay
+// expanded while filtering so matches are never hidden.
+func (m *Model) rebuildRows() {
+	if !m.treeView {
+		m.rows = nil
+		return
+	}
+	collapsed := m.collapsed
+	if m.filterInput.Value() != "" {
+		collapsed = nil
+	}
+	m.rows = ui.TreeRows(m.filteredWorktrees, collapsed)
+}
+
+// setGroupCollapsed collapses or expands a tree group, keeping the cursor on
+// its header, and saves the new layout.
+func (m *Model) setGroupCollapsed(prefix string, collapsed bool) tea.Cmd {
+	if collapsed {
+		m.collapsed[prefix] = true
+	} else {
+		delete(m.collapsed, prefix)
+	}
+	m.rebuildRows()
+	m.updateColumnWidths()
+	m.moveCursorToGroup(prefix)
+	return m.saveListView()
+}
+
+// saveListView persists the tree layout for the repository.
+func (m Model) saveListView() tea.Cmd {
+	if m.repo == nil {
+		return nil
+	}
+	view := state.ListView{Tree: m.treeView, Pinned: slices.Clone(m.pinned)}
+	for prefix := range m.collapsed {
+		view.Collapsed = append(view.Collapsed, prefix)
+	}
+	slices.Sort(view.Collapsed)
+	root := m.repo.MainWorktreeRoot
+	return func() tea.Msg {
+		_ = state.SaveListView(root, view)
+		return nil
+	}
+}
+
+func loadRecentOpens() tea.Msg {
+	return RecentOpensLoadedMsg{Opened: state.RecentOpens()}
+}
+
+// loadListView restores the tree layout saved for the repository.
+func loadListView(repo *git.Repo) tea.Cmd {
+	if repo == nil {
+		return nil
+	}
+	root := repo.MainWorktreeRoot
+	return func() tea.Msg {
+		return ListViewLoadedMsg{View: state.LoadListView(root)}
+	}
+}
+
+// ensureBranchCursorVisible adjusts branchViewOffset to keep branchCursor in visible area.
+func (m *Model) ensureBranchCursorVisible() {
+	m.branchViewOffset = ensureOffsetVisible(m.branchCursor, m.branchViewOffset, m.visibleItemCount())
+}
+
 // ensureBaseBranchVisible adjusts baseViewOffset to keep baseBranchIndex in visible area.
 func (m *Model) ensureBaseBranchVisible() {
 	m.baseViewOffset = ensureOffsetVisible(m.baseBranchIndex, m.baseViewOffset, m.visibleBranchCount())
 }
 
-// visibleWorktreeRange returns the range rendered in the list view.
+// visibleWorktreeRange returns the range of rows rendered in the list view.
 func (m Model) visibleWorktreeRange() (int, int) {
 	startIdx := m.viewOffset
 	endIdx := m.viewOffset + m.visibleItemCount()
-	if endIdx > len(m.filteredWorktrees) {
-		endIdx = len(m.filteredWorktrees)
+	if endIdx > m.rowCount() {
+		endIdx = m.rowCount()
 	}
-	if startIdx >= len(m.filteredWorktrees) {
+	if startIdx >= m.rowCount() {
 		startIdx = 0
 	}
 	return startIdx, endIdx
 }
 
-// listTopLine returns the first line index of the first visible worktree entry.
+// listTopLine returns the first line index of the first visible list row.
 func (m Model) listTopLine(startIdx int) int {
 	width := m.width
 	if width < ui.MinWidth {
@@ -1639,6 +3690,12 @@ func (m Model) listTopLine(startIdx int) int {
 		lines++
 	}
 
+	// Copy progress line + trailing blank line.
+	if m.copyProgress != nil {
+		lines += wrappedLineCount(ui.CopyProgressLine(*m.copyProgress), wrapWidth)
+		lines++
+	}
+
 	// Prune feedback line + trailing blank line.
 	if m.lastPruneCount > 0 {
 		msg := fmt.Sprintf("Pruned %d stale worktree entries", m.lastPruneCount)
@@ -1684,6 +3741,21 @@ func detailPanelLineCount(wt git.Worktree) int {
 	if wt.UniqueCommits > 0 {
 		lines++
 	}
+	if wt.IsLocked {
+		lines++
+	}
+	if wt.Sparse != nil {
+		lines++
+	}
+	if wt.IsPrunable {
+		lines++
+	}
+	if wt.Note != "" {
+		lines++
+	}
+	if len(wt.Labels) > 0 {
+		lines++
+	}
 	return lines
 }
 
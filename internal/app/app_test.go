@@ -1,13 +1,21 @@
 package app
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/henri123lemoine/grove/internal/config"
 	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
+	"github.com/henri123lemoine/grove/internal/ui"
 )
 
 func TestNewModel(t *testing.T) {
@@ -221,6 +229,65 @@ func TestFuzzyFilter(t *testing.T) {
 	}
 }
 
+func TestFilterQualifiers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.DefaultSort = "name"
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.state = StateFilter
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main"},
+		{Path: "/wt/feature-b", Branch: "feature-b", IsDirty: true},
+		{Path: "/wt/feature-a", Branch: "feature-a"},
+		{Path: "/wt/fix", Branch: "fix"},
+	}
+	model.applyFilter()
+
+	// Qualifiers alone keep the configured sort order
+	model.filterInput.SetValue("-is:dirty")
+	model.applyFilter()
+	var branches []string
+	for _, wt := range model.filteredWorktrees {
+		branches = append(branches, wt.Branch)
+	}
+	if strings.Join(branches, ",") != "feature-a,fix,main" {
+		t.Errorf("-is:dirty matched %v, want feature-a,fix,main", branches)
+	}
+
+	// And combine with fuzzy text
+	model.filterInput.SetValue("-is:dirty feat")
+	model.applyFilter()
+	if len(model.filteredWorktrees) != 1 || model.filteredWorktrees[0].Branch != "feature-a" {
+		t.Errorf("expected only feature-a, got %d matches", len(model.filteredWorktrees))
+	}
+
+	// is:merged loads merge status, which the merged column would otherwise gate
+	model.filterInput.Focus()
+	model.filterInput.SetValue("is:merge")
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m := newModel.(Model)
+	if !m.wantMerged {
+		t.Error("typing is:merged should request merge status")
+	}
+	if len(m.filteredWorktrees) != 0 {
+		t.Errorf("nothing is merged before status loads, got %d matches", len(m.filteredWorktrees))
+	}
+	newModel, _ = m.Update(UpstreamLoadedMsg{Merged: true, Worktrees: []git.Worktree{
+		{Path: "/wt/fix", IsMerged: true},
+	}})
+	m = newModel.(Model)
+	if len(m.filteredWorktrees) != 1 || m.filteredWorktrees[0].Branch != "fix" {
+		t.Errorf("expected fix once merge status loaded, got %d matches", len(m.filteredWorktrees))
+	}
+}
+
 func TestWindowSizeMessage(t *testing.T) {
 	cfg := config.DefaultConfig()
 	repo := &git.Repo{
@@ -303,6 +370,114 @@ func TestDeleteFlowCannotDeleteMain(t *testing.T) {
 	}
 }
 
+func TestMoveFlow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/test/repo/.worktrees/feature", Branch: "feature"},
+	}
+	model.filteredWorktrees = model.worktrees
+
+	// Main worktree can't be moved
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m := newModel.(Model)
+	if m.state != StateList || m.err == nil {
+		t.Errorf("moving main worktree should error, state=%d err=%v", m.state, m.err)
+	}
+
+	m.err = nil
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = newModel.(Model)
+	if m.state != StateMove {
+		t.Fatalf("Expected StateMove, got %d", m.state)
+	}
+	if got := m.moveInput.Value(); got != filepath.Join(".worktrees", "feature") {
+		t.Errorf("move input = %q, want path relative to repo root", got)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.state != StateList || m.moveWorktree != nil {
+		t.Errorf("esc should cancel move, state=%d", m.state)
+	}
+}
+
+func TestLockedWorktreeCannotBeDeleted(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo/.worktrees/usb", Branch: "usb", IsLocked: true, LockReason: "on removable drive"},
+		{Path: "/test/repo/.worktrees/feature", Branch: "feature"},
+	}
+	model.filteredWorktrees = model.worktrees
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m := newModel.(Model)
+	if m.state != StateList || m.err == nil {
+		t.Errorf("deleting a locked worktree should error, state=%d err=%v", m.state, m.err)
+	}
+
+	// L on an unlocked worktree asks for a reason
+	m.err = nil
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	m = newModel.(Model)
+	if m.state != StateLock || m.lockWorktree == nil || m.lockWorktree.Branch != "feature" {
+		t.Errorf("Expected StateLock for feature, got state=%d", m.state)
+	}
+}
+
+func TestRemoteClashPrompt(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.state = StateCreateRemoteClash
+	model.createBranch = "feature-x"
+	model.createRemote = "origin/feature-x"
+
+	// n returns to the name input, still tracking the remote
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m := newModel.(Model)
+	if m.state != StateCreate {
+		t.Fatalf("Expected StateCreate, got %d", m.state)
+	}
+	if m.createRemote != "origin/feature-x" || m.createInput.Value() != "feature-x" {
+		t.Errorf("createRemote=%q input=%q", m.createRemote, m.createInput.Value())
+	}
+
+	// esc abandons the checkout entirely
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.state != StateList || m.createRemote != "" {
+		t.Errorf("esc should reset, state=%d createRemote=%q", m.state, m.createRemote)
+	}
+}
+
 func TestSanitizePath(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -325,6 +500,144 @@ func TestSanitizePath(t *testing.T) {
 	}
 }
 
+func TestExpandWorktreePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	user := sanitizePath(currentUserName())
+	date := "2024-05-01"
+
+	tests := []struct {
+		tmpl     string
+		branch   string
+		expected string
+	}{
+		{"../{repo}-wt/{branch}", "feature/auth", "../grove-wt/feature/auth"},
+		{"~/worktrees/{repo}/{branch_short}", "feature/auth", filepath.Join(home, "worktrees/grove/auth")},
+		{"/tmp/{user}/{date}/{branch}", "fix me", "/tmp/" + user + "/" + date + "/fix-me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got := expandWorktreePath(tt.tmpl, "grove", tt.branch, date)
+			if got != tt.expected {
+				t.Errorf("expandWorktreePath(%q, %q) = %q, want %q", tt.tmpl, tt.branch, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchDefaultPath(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.General.WorktreePath = "/tmp/wt/{date}/{branch}"
+
+	date, ok := matchDefaultPath(cfg, "feature/auth", "/tmp/wt/2024-05-01/feature/auth")
+	if !ok || date != "2024-05-01" {
+		t.Errorf("matchDefaultPath(earlier date) = %q, %v; want 2024-05-01, true", date, ok)
+	}
+	if _, ok := matchDefaultPath(cfg, "feature/auth", "/tmp/wt/2024-05-01/elsewhere"); ok {
+		t.Error("matchDefaultPath matched a custom placement")
+	}
+	if _, ok := matchDefaultPath(cfg, "feature/auth", DefaultWorktreePath(cfg, "feature/auth")); !ok {
+		t.Error("matchDefaultPath did not match today's default path")
+	}
+}
+
+func TestSlugifyBranch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Fix login crash (iOS 17)!", "fix-login-crash-ios-17"},
+		{"feat/Add OAuth  support", "feat/add-oauth-support"},
+		{"  --weird__name--  ", "weird-name"},
+		{"already-fine", "already-fine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := slugifyBranch(tt.input); got != tt.expected {
+				t.Errorf("slugifyBranch(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Branch.Pattern = "^(feat|fix)/"
+	model := New(cfg, &git.Repo{MainWorktreeRoot: "/test/repo"}, nil)
+	model.loading = false
+
+	model.createInput.SetValue("Fix login crash")
+	if model.createValidation() == "" {
+		t.Error("name with spaces should be invalid")
+	}
+
+	model.createInput.SetValue("fix/Login Crash")
+	if got := model.createSuggestion(); got != "fix/login-crash" {
+		t.Errorf("createSuggestion() = %q, want fix/login-crash", got)
+	}
+
+	model.createInput.SetValue("chore/cleanup")
+	if model.createValidation() == "" {
+		t.Error("name not matching branch.pattern should be invalid")
+	}
+
+	model.createInput.SetValue("feat/cleanup")
+	if msg := model.createValidation(); msg != "" {
+		t.Errorf("valid name reported as invalid: %s", msg)
+	}
+
+	// Names enter accepts as-is get a hint instead of an error
+	model.branches = []git.Branch{
+		{Name: "main"},
+		{Name: "origin/release", IsRemote: true},
+		{Name: "v1.0", IsTag: true},
+	}
+	for _, value := range []string{"main", "origin/release", "release", "v1.0"} {
+		model.createInput.SetValue(value)
+		if msg := model.createValidation(); msg != "" {
+			t.Errorf("createValidation(%q) = %q, want no error", value, msg)
+		}
+		if model.createHint() == "" {
+			t.Errorf("createHint(%q) is empty", value)
+		}
+	}
+}
+
+func TestBranchTemplateFlow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Branch.Templates = []string{"feat/{ticket}-{slug}"}
+	model := New(cfg, &git.Repo{MainWorktreeRoot: "/test/repo"}, nil)
+	model.loading = false
+	model.state = StateCreate
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m := newModel.(Model)
+	if m.state != StateCreateTemplate || m.currentTemplateField() != "ticket" {
+		t.Fatalf("Expected ticket prompt, state=%d field=%q", m.state, m.currentTemplateField())
+	}
+
+	m.templateInput.SetValue("ABC-123")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.currentTemplateField() != "slug" || m.templatePreview() != "feat/ABC-123-{slug}" {
+		t.Fatalf("field=%q preview=%q", m.currentTemplateField(), m.templatePreview())
+	}
+
+	m.templateInput.SetValue("Add OAuth login!")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.state != StateCreate {
+		t.Fatalf("Expected back in StateCreate, got %d", m.state)
+	}
+	if got := m.createInput.Value(); got != "feat/ABC-123-add-oauth-login" {
+		t.Errorf("create input = %q, want feat/ABC-123-add-oauth-login", got)
+	}
+}
+
 func TestKeyMapFromConfig(t *testing.T) {
 	keysConfig := &config.KeysConfig{
 		Up:   "up,k,w",
@@ -347,6 +660,21 @@ func TestKeyMapFromConfig(t *testing.T) {
 	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}}, km.Open) {
 		t.Error("Expected 'o' to match Open binding")
 	}
+
+	// Sequences are normalized, and mode keys replace their defaults
+	km = KeyMapFromConfig(&config.KeysConfig{
+		Delete: "d  d, space x",
+		Modes:  config.ModeKeysConfig{Stash: config.StashKeysConfig{Drop: "D"}},
+	})
+	if got := km.Delete.Keys(); !slices.Equal(got, []string{"d d", "  x"}) {
+		t.Errorf("Delete keys = %q, want [\"d d\" \"  x\"]", got)
+	}
+	if got := km.StashDrop.Keys(); !slices.Equal(got, []string{"D"}) {
+		t.Errorf("StashDrop keys = %q, want [D]", got)
+	}
+	if got := km.StashPop.Keys(); !slices.Equal(got, []string{"p"}) {
+		t.Errorf("StashPop keys = %q, want default [p]", got)
+	}
 }
 
 func TestDetailToggle(t *testing.T) {
@@ -412,3 +740,864 @@ func TestShouldQuit(t *testing.T) {
 		t.Error("ShouldQuit should be true after 'q'")
 	}
 }
+
+func TestMarkAndPushDiverged(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/test/repo/.worktrees/a", Branch: "a", HasUpstream: true, Ahead: 1, Behind: 2},
+		{Path: "/test/repo/.worktrees/b", Branch: "b"},
+	}
+	model.filteredWorktrees = model.worktrees
+	model.cursor = 1
+
+	// Space marks the worktree and advances the cursor
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m := newModel.(Model)
+	if !m.marked["/test/repo/.worktrees/a"] {
+		t.Fatal("Expected worktree a to be marked")
+	}
+	if m.cursor != 2 {
+		t.Errorf("Expected cursor to advance to 2, got %d", m.cursor)
+	}
+
+	// Push applies to marked worktrees; a diverged branch needs confirmation
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = newModel.(Model)
+	if m.state != StatePushConfirmForce {
+		t.Fatalf("Expected StatePushConfirmForce, got %d", m.state)
+	}
+	if len(m.pushTargets) != 1 || m.pushTargets[0].Branch != "a" {
+		t.Errorf("Expected push target a, got %+v", m.pushTargets)
+	}
+
+	// n skips the diverged branch, which leaves nothing to push
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(Model)
+	if m.state != StateList || m.pushTargets != nil {
+		t.Errorf("Expected cancel to return to list, got state %d", m.state)
+	}
+
+	// Esc clears marks
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if len(m.marked) != 0 {
+		t.Error("Expected esc to clear marks")
+	}
+}
+
+func TestPushSkipDiverged(t *testing.T) {
+	model := New(config.DefaultConfig(), &git.Repo{MainWorktreeRoot: "/test/repo"}, nil)
+	model.loading = false
+	model.state = StatePushConfirmForce
+	model.pushTargets = []git.Worktree{
+		{Path: "/test/repo/.worktrees/a", Branch: "a", HasUpstream: true, Ahead: 1, Behind: 2},
+		{Path: "/test/repo/.worktrees/b", Branch: "b", HasUpstream: true, Ahead: 1},
+	}
+
+	// n still pushes the branch that hasn't diverged
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m := newModel.(Model)
+	if m.state != StatePushing || cmd == nil {
+		t.Errorf("Expected n to push the other branches, got state %d", m.state)
+	}
+}
+
+func TestBranchesView(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m := newModel.(Model)
+	if m.state != StateBranches {
+		t.Fatalf("Expected StateBranches after 'b', got %d", m.state)
+	}
+	if !m.branchesLoading {
+		t.Error("Expected branches to be loading")
+	}
+
+	newModel, _ = m.Update(BranchDetailsLoadedMsg{Branches: []git.BranchInfo{
+		{Name: "old-feature", IsMerged: true},
+		{Name: "wip"},
+		{Name: "squashed", IsSquashMerged: true},
+	}})
+	m = newModel.(Model)
+	if m.branchesLoading || len(m.branchDetails) != 3 {
+		t.Fatalf("Expected 3 loaded branches, got %d", len(m.branchDetails))
+	}
+
+	// P selects only merged and squash-merged branches
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = newModel.(Model)
+	if m.state != StateBranchDeleteConfirm {
+		t.Fatalf("Expected StateBranchDeleteConfirm, got %d", m.state)
+	}
+	if len(m.branchDeleteTargets) != 2 {
+		t.Errorf("Expected 2 stale branches, got %d", len(m.branchDeleteTargets))
+	}
+
+	// The squash-merged branch needs -D, which takes a second confirmation
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if cmd != nil || !m.branchForceConfirm || m.state != StateBranchDeleteConfirm {
+		t.Fatalf("Expected a force-delete confirmation, got state %d force=%v", m.state, m.branchForceConfirm)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(Model)
+	if m.state != StateBranches || m.branchDeleteTargets != nil || m.branchForceConfirm {
+		t.Errorf("Expected cancel to return to branches view, got state %d", m.state)
+	}
+
+	// Merged branches alone are deleted after a single confirmation
+	m.branchDeleteTargets = []git.BranchInfo{{Name: "old-feature", IsMerged: true}}
+	m.state = StateBranchDeleteConfirm
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if cmd == nil || m.branchForceConfirm {
+		t.Error("Expected merged branches to be deleted without a second confirmation")
+	}
+	m.state = StateBranches
+	m.branchesLoading = false
+
+	// r opens rename prefilled with the branch name
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = newModel.(Model)
+	if m.state != StateBranchRename || m.branchInput.Value() != "wip" {
+		t.Errorf("Expected rename of 'wip', got state %d value %q", m.state, m.branchInput.Value())
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.state != StateList {
+		t.Errorf("Expected esc to return to list, got %d", m.state)
+	}
+}
+
+func TestPruneGoneFlow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	m := newModel.(Model)
+	if m.state != StatePruneGoneConfirm || !m.goneLoading {
+		t.Fatalf("Expected loading StatePruneGoneConfirm after 'X', got %d", m.state)
+	}
+
+	// Confirming while still checking does nothing
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if m.state != StatePruneGoneConfirm || cmd != nil {
+		t.Error("Expected 'y' to be ignored while checking")
+	}
+
+	newModel, _ = m.Update(GoneBranchesCheckedMsg{Branches: []git.GoneBranch{
+		{Branch: "merged-pr"},
+		{Branch: "wip", SkipReason: "2 unique commits"},
+	}})
+	m = newModel.(Model)
+	if m.goneLoading || len(m.goneBranches) != 2 {
+		t.Fatalf("Expected 2 gone branches, got %d", len(m.goneBranches))
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if m.state != StateList {
+		t.Errorf("Expected StateList after confirm, got %d", m.state)
+	}
+	if cmd == nil {
+		t.Error("Expected prune command for the prunable branch")
+	}
+
+	// Nothing gone: back to list with a hint
+	m.state = StatePruneGoneConfirm
+	m.goneLoading = true
+	newModel, _ = m.Update(GoneBranchesCheckedMsg{})
+	m = newModel.(Model)
+	if m.state != StateList || m.err == nil {
+		t.Error("Expected empty result to return to list with a message")
+	}
+}
+
+func TestExitAfterOpenWaitsForCopy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Open.ExitAfterOpen = true
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+
+	updates := make(chan tea.Msg, 1)
+	newModel, cmd := model.Update(FileCopyProgressMsg{Progress: git.CopyProgress{Path: "node_modules/a.js", Files: 12}, updates: updates})
+	m := newModel.(Model)
+	if m.copyProgress == nil || m.copyProgress.Files != 12 || cmd == nil {
+		t.Fatalf("progress not recorded or not waiting for more: %+v", m.copyProgress)
+	}
+
+	// The next message comes from the channel
+	updates <- FileCopyProgressMsg{Progress: git.CopyProgress{Files: 20}, updates: updates}
+	if msg, ok := cmd().(FileCopyProgressMsg); !ok || msg.Progress.Files != 20 {
+		t.Errorf("expected next progress message from channel, got %#v", msg)
+	}
+
+	// Opening the worktree must not quit while the copy runs
+	newModel, _ = m.Update(WorktreeOpenedMsg{IsNewWindow: true})
+	m = newModel.(Model)
+	if m.shouldQuit || !m.quitAfterCopy {
+		t.Fatalf("quit before copy finished: shouldQuit=%v quitAfterCopy=%v", m.shouldQuit, m.quitAfterCopy)
+	}
+
+	newModel, _ = m.Update(FileCopyCompletedMsg{})
+	m = newModel.(Model)
+	if !m.shouldQuit || m.copyProgress != nil {
+		t.Errorf("expected quit after copy completed, shouldQuit=%v", m.shouldQuit)
+	}
+}
+
+func TestSparseProfilePicker(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Worktree.SparseProfiles = []config.SparseProfile{
+		{Name: "api", Paths: []string{"services/api"}},
+		{Name: "web", Paths: []string{"services/web"}},
+	}
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+
+	model := New(cfg, repo, nil)
+	model.loading = false
+
+	newModel, cmd := model.requestCreate(createRequest{branch: "feature", isNew: true, base: "main"})
+	m := newModel.(Model)
+	if m.state != StateSelectSparse || cmd != nil {
+		t.Fatalf("expected sparse picker before creating, got state=%d", m.state)
+	}
+	if m.pendingCreateLabel() != "feature" {
+		t.Errorf("pendingCreateLabel() = %q, want feature", m.pendingCreateLabel())
+	}
+
+	// Cursor stops at the trailing "Full" option
+	for i := 0; i < 5; i++ {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = newModel.(Model)
+	}
+	if m.sparseCursor != 2 {
+		t.Errorf("sparseCursor = %d, want 2 (Full)", m.sparseCursor)
+	}
+
+	// y is a confirm-prompt key, not a picker one
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newModel.(Model)
+	if m.state != StateSelectSparse || cmd != nil {
+		t.Fatalf("y should not pick a profile, got state=%d", m.state)
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.pendingCreate != nil {
+		t.Error("enter should start creating the worktree")
+	}
+
+	// Esc abandons the creation
+	newModel, _ = m.requestCreate(createRequest{branch: "other"})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.state != StateList || m.pendingCreate != nil {
+		t.Errorf("esc should cancel, state=%d pending=%v", m.state, m.pendingCreate)
+	}
+
+	// Without profiles nothing is asked
+	model = New(config.DefaultConfig(), repo, nil)
+	newModel, cmd = model.requestCreate(createRequest{branch: "feature"})
+	if newModel.(Model).state == StateSelectSparse || cmd == nil {
+		t.Error("expected immediate create without sparse profiles")
+	}
+}
+
+func TestConfigReloadAppliesUISettings(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Open.Command = "original"
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	defer ui.InitTheme(nil)
+
+	reloaded := config.DefaultConfig()
+	reloaded.Open.Command = "changed"
+	reloaded.UI.Theme = "mine"
+	reloaded.Themes = map[string]config.ThemeConfig{"mine": {Base: "nord", Primary: "#7aa2f7"}}
+	reloaded.UI.Colors.Dirty = "oops"
+
+	newModel, cmd := model.Update(ConfigReloadedMsg{Config: reloaded})
+	m := newModel.(Model)
+	if m.config.UI.Theme != "mine" || m.config.Themes["mine"].Primary != "#7aa2f7" {
+		t.Errorf("theme not applied: %+v", m.config.UI)
+	}
+	if m.config.Open.Command != "original" {
+		t.Errorf("non-UI settings changed on reload: open.command = %q", m.config.Open.Command)
+	}
+	if len(m.configWarnings) != 1 {
+		t.Errorf("expected one warning for the invalid color, got %v", m.configWarnings)
+	}
+	if cmd == nil {
+		t.Error("config watcher not re-armed after reload")
+	}
+}
+
+func TestTreeView(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.height = 40
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/login", Branch: "feat/auth/login", IsDirty: true, Ahead: 2},
+		{Path: "/wt/signup", Branch: "feat/auth/signup", Behind: 1},
+		{Path: "/wt/api", Branch: "fix/api"},
+	}
+	model.applyFilter()
+
+	press := func(m Model, r rune) (Model, tea.Cmd) {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return newModel.(Model), cmd
+	}
+
+	m, cmd := press(model, 't')
+	if !m.treeView || cmd == nil {
+		t.Fatal("'t' should enable tree mode and save it")
+	}
+	cmd()
+
+	// main, feat/auth (merged single-child chain), login, signup, fix, api
+	if len(m.rows) != 6 {
+		t.Fatalf("expected 6 rows, got %d: %+v", len(m.rows), m.rows)
+	}
+	group := m.rows[1].Group
+	if group == nil || group.Prefix != "feat/auth" || group.Count != 2 || group.Dirty != 1 || group.Ahead != 2 || group.Behind != 1 {
+		t.Errorf("unexpected feat/auth group: %+v", group)
+	}
+	if m.rows[2].Label != "  login" || m.rows[2].Parent != "feat/auth" {
+		t.Errorf("unexpected leaf row: %+v", m.rows[2])
+	}
+
+	// Collapse from a leaf: first step out to the group, then collapse it
+	m.cursor = 2
+	m, _ = press(m, 'h')
+	if m.cursor != 1 {
+		t.Fatalf("expected cursor on group header, got %d", m.cursor)
+	}
+	if m.cursorWorktree() != nil {
+		t.Error("cursor on a group header should have no worktree")
+	}
+	m, cmd = press(m, 'h')
+	if !m.rows[1].Group.Collapsed || len(m.rows) != 4 {
+		t.Fatalf("group not collapsed: %+v", m.rows)
+	}
+	cmd()
+
+	// Cursor math skips the hidden rows
+	m, _ = press(m, 'j')
+	if wt := m.cursorWorktree(); m.cursor != 2 || wt != nil {
+		t.Errorf("expected cursor on fix/ header after down, got %d", m.cursor)
+	}
+	m, _ = press(m, 'G')
+	if wt := m.cursorWorktree(); wt == nil || wt.Branch != "fix/api" {
+		t.Errorf("expected fix/api at end, got %v", wt)
+	}
+	if start, end := m.visibleWorktreeRange(); start != 0 || end != 4 {
+		t.Errorf("visibleWorktreeRange = %d, %d; want 0, 4", start, end)
+	}
+
+	// Filtering shows matches inside collapsed groups
+	m.filterInput.SetValue("signup")
+	m.applyFilter()
+	if len(m.rows) != 2 || m.rows[1].IsGroup() {
+		t.Errorf("expected group and match while filtering, got %+v", m.rows)
+	}
+	m.filterInput.SetValue("")
+	m.applyFilter()
+
+	// The layout is restored for the next session
+	view := state.LoadListView("/test/repo")
+	if !view.Tree || len(view.Collapsed) != 1 || view.Collapsed[0] != "feat/auth" {
+		t.Errorf("saved view = %+v", view)
+	}
+	restored, _ := New(cfg, repo, nil).Update(ListViewLoadedMsg{View: view})
+	if r := restored.(Model); !r.treeView || !r.collapsed["feat/auth"] {
+		t.Error("saved view not applied on load")
+	}
+
+	// Enter on a group header expands it again
+	m.cursor = 1
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.rows[1].Group.Collapsed || len(m.rows) != 6 {
+		t.Errorf("enter should expand the group: %+v", m.rows)
+	}
+}
+
+func TestRecentSortAndPrevious(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.DefaultSort = "recent"
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true, IsCurrent: true},
+		{Path: "/wt/a", Branch: "a"},
+		{Path: "/wt/b", Branch: "b"},
+		{Path: "/wt/c", Branch: "c"},
+	}
+	model.applyFilter()
+
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	if m := newModel.(Model); m.err == nil || cmd != nil {
+		t.Error("previous without history should report an error")
+	}
+
+	now := time.Now()
+	newModel, _ = model.Update(RecentOpensLoadedMsg{Opened: map[string]time.Time{
+		"/test/repo": now,
+		"/wt/b":      now.Add(-time.Minute),
+		"/wt/c":      now.Add(-time.Hour),
+	}})
+	m := newModel.(Model)
+	var order []string
+	for _, wt := range m.filteredWorktrees {
+		order = append(order, wt.Branch)
+	}
+	if strings.Join(order, ",") != "main,b,c,a" {
+		t.Errorf("recent order = %v, want main,b,c,a", order)
+	}
+
+	// "-" skips the current worktree and opens the last one used before it
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	m = newModel.(Model)
+	if cmd == nil || m.selectedWorktree == nil || m.selectedWorktree.Branch != "b" {
+		t.Fatalf("expected to open b, got %v", m.selectedWorktree)
+	}
+	if wt := m.cursorWorktree(); wt == nil || wt.Branch != "b" {
+		t.Errorf("cursor not moved to b")
+	}
+
+	// Last-commit order follows background-loaded commit times
+	m.sortMode = SortLastCommit
+	newModel, _ = m.Update(UpstreamLoadedMsg{Worktrees: []git.Worktree{
+		{Path: "/test/repo", CommitTime: now.Add(-time.Hour)},
+		{Path: "/wt/a", CommitTime: now},
+		{Path: "/wt/b", CommitTime: now.Add(-2 * time.Hour)},
+	}})
+	m = newModel.(Model)
+	order = order[:0]
+	for _, wt := range m.filteredWorktrees {
+		order = append(order, wt.Branch)
+	}
+	if strings.Join(order, ",") != "a,main,b,c" {
+		t.Errorf("last-commit order = %v, want a,main,b,c", order)
+	}
+	if wt := m.cursorWorktree(); wt == nil || wt.Branch != "b" {
+		t.Error("cursor should stay on b after re-sorting")
+	}
+}
+
+func TestNoteFlow(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/auth", Branch: "auth", Note: "old", Labels: []string{"wip"}},
+	}
+	model.rebuildWorktreeIndex()
+	model.applyFilter()
+	model.cursor = 1
+
+	// N opens the editor prefilled with the current note
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	m := newModel.(Model)
+	if m.state != StateNote || m.noteWorktree == nil || m.noteWorktree.Branch != "auth" {
+		t.Fatalf("expected StateNote for auth, got state=%d", m.state)
+	}
+	if got := m.noteInput.Value(); got != "#wip old" {
+		t.Errorf("note input = %q, want %q", got, "#wip old")
+	}
+
+	m.noteInput.SetValue("#review waiting on api")
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected save command")
+	}
+	newModel, _ = newModel.(Model).Update(cmd())
+	m = newModel.(Model)
+	if m.state != StateList || m.err != nil {
+		t.Fatalf("expected list after save, state=%d err=%v", m.state, m.err)
+	}
+	if wt := m.cursorWorktree(); wt == nil || wt.Note != "waiting on api" || !slices.Equal(wt.Labels, []string{"review"}) {
+		t.Errorf("note not applied, got %+v", wt)
+	}
+	if note := state.LoadNotes()["/wt/auth"]; note.Text != "waiting on api" {
+		t.Errorf("note not persisted, got %+v", note)
+	}
+
+	// The filter matches note text and labels
+	for _, query := range []string{"api", "#review"} {
+		m.filterInput.SetValue(query)
+		m.applyFilter()
+		if len(m.filteredWorktrees) != 1 || m.filteredWorktrees[0].Branch != "auth" {
+			t.Errorf("filter %q matched %d worktrees, want auth only", query, len(m.filteredWorktrees))
+		}
+	}
+}
+
+func TestPinnedWorktrees(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.DefaultSort = "name"
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/a", Branch: "a"},
+		{Path: "/wt/b", Branch: "b"},
+		{Path: "/wt/c", Branch: "c"},
+	}
+	model.rebuildWorktreeIndex()
+	model.applyFilter()
+
+	order := func(m Model) string {
+		var branches []string
+		for _, wt := range m.filteredWorktrees {
+			branches = append(branches, wt.Branch)
+		}
+		return strings.Join(branches, ",")
+	}
+
+	// Pin c, then b: both move to the top in pin order
+	model.moveCursorToPath("/wt/c")
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	if cmd == nil {
+		t.Error("expected pins to be saved")
+	}
+	m := newModel.(Model)
+	m.moveCursorToPath("/wt/b")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	m = newModel.(Model)
+	if got := order(m); got != "c,b,a,main" {
+		t.Errorf("order with pins = %s, want c,b,a,main", got)
+	}
+	if wt := m.cursorWorktree(); wt == nil || wt.Branch != "b" {
+		t.Errorf("cursor should stay on b after pinning")
+	}
+
+	// Digits open pinned worktrees by slot
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	m = newModel.(Model)
+	if cmd == nil || m.selectedWorktree == nil || m.selectedWorktree.Branch != "c" {
+		t.Fatalf("1 should open c, got %v", m.selectedWorktree)
+	}
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if m := newModel.(Model); m.err == nil || cmd != nil {
+		t.Error("an empty slot should report an error")
+	}
+
+	// Prune-gone skips pinned worktrees unless included
+	m.err = nil
+	m.state = StatePruneGoneConfirm
+	m.goneBranches = []git.GoneBranch{
+		{Branch: "a", Worktree: &m.worktrees[m.worktreeIndexByPath["/wt/a"]]},
+		{Branch: "b", Worktree: &m.worktrees[m.worktreeIndexByPath["/wt/b"]]},
+	}
+	if got := m.goneCandidates(); got[0].SkipReason != "" || got[1].SkipReason == "" {
+		t.Errorf("expected only the pinned b to be skipped, got %+v", got)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = newModel.(Model)
+	if got := m.goneCandidates(); got[1].SkipReason != "" {
+		t.Errorf("pinned b should be included after a, got %q", got[1].SkipReason)
+	}
+
+	// Unpinning returns the worktree to its sorted place
+	m.state = StateList
+	m.moveCursorToPath("/wt/c")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	m = newModel.(Model)
+	if got := order(m); got != "b,a,c,main" {
+		t.Errorf("order after unpinning c = %s, want b,a,c,main", got)
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/auth", Branch: "auth"},
+	}
+	model.rebuildWorktreeIndex()
+	model.applyFilter()
+	model.cursor = 0
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	m := newModel.(Model)
+	if m.state != StatePalette {
+		t.Fatalf("expected StatePalette, got %d", m.state)
+	}
+	// The main worktree can't be deleted or renamed, so those are hidden
+	if slices.Contains(m.paletteMatches, ActionDelete) || slices.Contains(m.paletteMatches, ActionRename) {
+		t.Errorf("palette offers delete/rename on main: %v", m.paletteMatches)
+	}
+	if slices.Contains(m.paletteMatches, ActionUp) || slices.Contains(m.paletteMatches, ActionPalette) {
+		t.Errorf("palette offers hidden actions: %v", m.paletteMatches)
+	}
+
+	// A linked worktree can be deleted
+	m.state = StateList
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = newModel.(Model)
+	if !slices.Contains(m.paletteMatches, ActionDelete) {
+		t.Errorf("palette should offer delete on a linked worktree: %v", m.paletteMatches)
+	}
+	// Only sparse checkouts can be widened
+	if slices.Contains(m.paletteMatches, ActionWiden) {
+		t.Errorf("palette offers widen on a full checkout: %v", m.paletteMatches)
+	}
+
+	// Typing filters the actions; enter runs the selected one
+	for _, r := range "filter" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	if len(m.paletteMatches) == 0 || m.paletteMatches[0] != ActionFilter {
+		t.Fatalf("expected filter as the best match, got %v", m.paletteMatches)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.state != StateFilter {
+		t.Errorf("expected StateFilter after running filter, got %d", m.state)
+	}
+
+	// Esc closes without running anything
+	m.state = StateList
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(Model); m.state != StateList {
+		t.Errorf("expected StateList after esc, got %d", m.state)
+	}
+}
+
+func TestKeySequences(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.Keys.Delete = "d d"
+	cfg.Keys.Modes.Confirm.Yes = "Y"
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/auth", Branch: "auth"},
+		{Path: "/wt/ui", Branch: "ui"},
+	}
+	model.rebuildWorktreeIndex()
+	model.applyFilter()
+	model.cursor = 1
+
+	press := func(m Model, r rune) Model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return newModel.(Model)
+	}
+
+	// A single d only starts the sequence
+	m := press(model, 'd')
+	if m.state != StateList || !slices.Equal(m.pendingKeys, []string{"d"}) {
+		t.Fatalf("expected pending d in the list, got state=%d pending=%v", m.state, m.pendingKeys)
+	}
+
+	// A key that doesn't continue it drops the sequence and runs on its own
+	m = press(m, 'j')
+	if m.pendingKeys != nil || m.cursor != 2 {
+		t.Fatalf("expected j to move down and clear the sequence, got cursor=%d pending=%v", m.cursor, m.pendingKeys)
+	}
+
+	// Esc cancels a pending sequence
+	m = press(m, 'd')
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(Model); m.pendingKeys != nil || m.state != StateList {
+		t.Fatalf("expected esc to cancel the sequence, got state=%d pending=%v", m.state, m.pendingKeys)
+	}
+
+	m = press(press(m, 'd'), 'd')
+	if m.state != StateDelete || m.deleteWorktree == nil || m.deleteWorktree.Branch != "ui" {
+		t.Fatalf("expected d d to delete ui, got state=%d", m.state)
+	}
+
+	// Confirm prompts use [keys.modes.confirm]
+	m.state = StatePruneConfirm
+	if m = press(m, 'y'); m.state != StatePruneConfirm {
+		t.Errorf("y should no longer confirm, got state=%d", m.state)
+	}
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	if m = newModel.(Model); m.state != StateList || cmd == nil {
+		t.Errorf("Y should confirm the prune, got state=%d cmd=%v", m.state, cmd != nil)
+	}
+}
+
+func TestStashTransferFlow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	repo := &git.Repo{
+		Root:             "/test/repo",
+		GitDir:           "/test/repo/.git",
+		MainWorktreeRoot: "/test/repo",
+		DefaultBranch:    "main",
+	}
+	model := New(cfg, repo, nil)
+	model.loading = false
+	model.worktrees = []git.Worktree{
+		{Path: "/test/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/auth", Branch: "auth"},
+		{Path: "/wt/ui", Branch: "ui"},
+	}
+	model.state = StateStash
+	model.stashWorktree = &model.worktrees[0]
+	model.stashEntries = []git.StashEntry{
+		{Index: 0, Branch: "main", Subject: "wip"},
+		{Index: 1, Branch: "ui", Subject: "styles"},
+	}
+
+	press := func(m Model, r rune) Model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return newModel.(Model)
+	}
+
+	// Moving the cursor requests the new preview and drops stale ones
+	m := press(model, 'j')
+	if m.stashDiffIndex != 1 || m.stashDiff != "" {
+		t.Fatalf("expected preview of stash 1 to be requested, got index=%d", m.stashDiffIndex)
+	}
+	newModel, _ := m.Update(StashDiffLoadedMsg{Index: 0, Diff: "stale"})
+	if m = newModel.(Model); m.stashDiff != "" {
+		t.Errorf("stale preview shown: %q", m.stashDiff)
+	}
+	newModel, _ = m.Update(StashDiffLoadedMsg{Index: 1, Err: errors.New("bad object")})
+	if m = newModel.(Model); m.stashDiffErr == nil {
+		t.Error("failed preview should be recorded for the stash view")
+	}
+	m.stashDiffErr = nil
+	newModel, _ = m.Update(StashDiffLoadedMsg{Index: 1, Diff: "+styles"})
+	if m = newModel.(Model); m.stashDiff != "+styles" {
+		t.Errorf("preview = %q, want +styles", m.stashDiff)
+	}
+	if origins := m.stashOrigins(); !slices.Equal(origins, []string{"/test/repo", "/wt/ui"}) {
+		t.Errorf("stash origins = %v", origins)
+	}
+
+	// Apply-to lists the other worktrees, starting on the stash's own
+	m = press(m, 't')
+	if m.state != StateStashApplyTo {
+		t.Fatalf("expected StateStashApplyTo, got %d", m.state)
+	}
+	targets := m.stashTargets()
+	if len(targets) != 2 || targets[m.stashTargetCursor].Branch != "ui" {
+		t.Fatalf("expected cursor on ui among %v", targets)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("expected apply command")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(Model); m.state != StateStash {
+		t.Fatalf("expected esc back to the stash view, got %d", m.state)
+	}
+
+	// Branching needs a valid name
+	m = press(m, 'b')
+	if m.state != StateStashBranch {
+		t.Fatalf("expected StateStashBranch, got %d", m.state)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("expected no command without a branch name")
+	}
+	m.stashBranchInput.SetValue("from-stash")
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("expected create command")
+	}
+}
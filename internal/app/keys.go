@@ -1,10 +1,14 @@
 package app
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/henri123lemoine/grove/internal/config"
+	"github.com/henri123lemoine/grove/internal/git"
 	"github.com/henri123lemoine/grove/internal/ui"
 )
 
@@ -17,22 +21,63 @@ type KeyMap struct {
 	End  key.Binding
 
 	// Actions
-	Open   key.Binding
-	New    key.Binding
-	Delete key.Binding
-	Rename key.Binding
-	Fetch  key.Binding
-	Filter key.Binding
-	Detail key.Binding
-	Prune  key.Binding
-	Stash  key.Binding
-	Sort   key.Binding
+	Open      key.Binding
+	Previous  key.Binding
+	New       key.Binding
+	Delete    key.Binding
+	Rename    key.Binding
+	Move      key.Binding
+	Lock      key.Binding
+	Note      key.Binding
+	Pin       key.Binding
+	Widen     key.Binding
+	Fetch     key.Binding
+	Filter    key.Binding
+	Detail    key.Binding
+	Prune     key.Binding
+	PruneGone key.Binding
+	Stash     key.Binding
+	Sort      key.Binding
+	Push      key.Binding
+	Mark      key.Binding
+	Branches  key.Binding
+
+	// Tree mode
+	Tree     key.Binding
+	Collapse key.Binding
+	Expand   key.Binding
 
 	// General
-	Confirm key.Binding
-	Cancel  key.Binding
 	Quit    key.Binding
 	Help    key.Binding
+	Palette key.Binding
+
+	// Confirmation prompts
+	Yes           key.Binding
+	No            key.Binding
+	IncludePinned key.Binding
+
+	// Pickers
+	Confirm key.Binding
+	Cancel  key.Binding
+
+	// Remote branch clash prompt
+	Reuse   key.Binding
+	NewName key.Binding
+
+	// Stash view
+	StashPop     key.Binding
+	StashApply   key.Binding
+	StashApplyTo key.Binding
+	StashBranch  key.Binding
+	StashDrop    key.Binding
+
+	// Branches view
+	BranchCreate   key.Binding
+	BranchDelete   key.Binding
+	BranchPrune    key.Binding
+	BranchRename   key.Binding
+	BranchUpstream key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -58,6 +103,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "open"),
 		),
+		Previous: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "previous"),
+		),
 		New: key.NewBinding(
 			key.WithKeys("n"),
 			key.WithHelp("n", "new"),
@@ -70,6 +119,26 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "rename"),
 		),
+		Move: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "move"),
+		),
+		Lock: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "lock"),
+		),
+		Note: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "note"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "pin"),
+		),
+		Widen: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "widen"),
+		),
 		Fetch: key.NewBinding(
 			key.WithKeys("f"),
 			key.WithHelp("f", "fetch"),
@@ -86,6 +155,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("P"),
 			key.WithHelp("P", "prune"),
 		),
+		PruneGone: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "prune gone"),
+		),
 		Stash: key.NewBinding(
 			key.WithKeys("s"),
 			key.WithHelp("s", "stash"),
@@ -94,13 +167,29 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("o"),
 			key.WithHelp("o", "sort"),
 		),
-		Confirm: key.NewBinding(
-			key.WithKeys("enter", "y"),
-			key.WithHelp("enter/y", "confirm"),
+		Push: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "push"),
 		),
-		Cancel: key.NewBinding(
-			key.WithKeys("esc", "q"),
-			key.WithHelp("esc", "cancel"),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark"),
+		),
+		Branches: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "branches"),
+		),
+		Tree: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tree"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "collapse"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "expand"),
 		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
@@ -110,6 +199,78 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
 		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":/ctrl+p", "commands"),
+		),
+		Yes: key.NewBinding(
+			key.WithKeys("y", "Y"),
+			key.WithHelp("y", "yes"),
+		),
+		No: key.NewBinding(
+			key.WithKeys("n", "N"),
+			key.WithHelp("n", "no"),
+		),
+		IncludePinned: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "include pinned"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "cancel"),
+		),
+		Reuse: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reuse"),
+		),
+		NewName: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new name"),
+		),
+		StashPop: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pop"),
+		),
+		StashApply: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "apply"),
+		),
+		StashApplyTo: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "apply to"),
+		),
+		StashBranch: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "branch"),
+		),
+		StashDrop: key.NewBinding(
+			key.WithKeys("d", "x"),
+			key.WithHelp("d", "drop"),
+		),
+		BranchCreate: key.NewBinding(
+			key.WithKeys("enter", "w"),
+			key.WithHelp("enter/w", "worktree"),
+		),
+		BranchDelete: key.NewBinding(
+			key.WithKeys("d", "x"),
+			key.WithHelp("d", "delete"),
+		),
+		BranchPrune: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "prune merged"),
+		),
+		BranchRename: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rename"),
+		),
+		BranchUpstream: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "upstream"),
+		),
 	}
 }
 
@@ -147,6 +308,12 @@ func KeyMapFromConfig(cfg *config.KeysConfig) KeyMap {
 			key.WithHelp(cfg.Open, "open"),
 		)
 	}
+	if cfg.Previous != "" {
+		km.Previous = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Previous)...),
+			key.WithHelp(cfg.Previous, "previous"),
+		)
+	}
 	if cfg.New != "" {
 		km.New = key.NewBinding(
 			key.WithKeys(parseKeys(cfg.New)...),
@@ -165,6 +332,36 @@ func KeyMapFromConfig(cfg *config.KeysConfig) KeyMap {
 			key.WithHelp(cfg.Rename, "rename"),
 		)
 	}
+	if cfg.Move != "" {
+		km.Move = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Move)...),
+			key.WithHelp(cfg.Move, "move"),
+		)
+	}
+	if cfg.Lock != "" {
+		km.Lock = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Lock)...),
+			key.WithHelp(cfg.Lock, "lock"),
+		)
+	}
+	if cfg.Note != "" {
+		km.Note = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Note)...),
+			key.WithHelp(cfg.Note, "note"),
+		)
+	}
+	if cfg.Pin != "" {
+		km.Pin = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Pin)...),
+			key.WithHelp(cfg.Pin, "pin"),
+		)
+	}
+	if cfg.Widen != "" {
+		km.Widen = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Widen)...),
+			key.WithHelp(cfg.Widen, "widen"),
+		)
+	}
 	if cfg.Filter != "" {
 		km.Filter = key.NewBinding(
 			key.WithKeys(parseKeys(cfg.Filter)...),
@@ -189,6 +386,12 @@ func KeyMapFromConfig(cfg *config.KeysConfig) KeyMap {
 			key.WithHelp(cfg.Prune, "prune"),
 		)
 	}
+	if cfg.PruneGone != "" {
+		km.PruneGone = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.PruneGone)...),
+			key.WithHelp(cfg.PruneGone, "prune gone"),
+		)
+	}
 	if cfg.Stash != "" {
 		km.Stash = key.NewBinding(
 			key.WithKeys(parseKeys(cfg.Stash)...),
@@ -201,12 +404,54 @@ func KeyMapFromConfig(cfg *config.KeysConfig) KeyMap {
 			key.WithHelp(cfg.Sort, "sort"),
 		)
 	}
+	if cfg.Push != "" {
+		km.Push = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Push)...),
+			key.WithHelp(cfg.Push, "push"),
+		)
+	}
+	if cfg.Mark != "" {
+		km.Mark = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Mark)...),
+			key.WithHelp(cfg.Mark, "mark"),
+		)
+	}
+	if cfg.Branches != "" {
+		km.Branches = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Branches)...),
+			key.WithHelp(cfg.Branches, "branches"),
+		)
+	}
+	if cfg.Tree != "" {
+		km.Tree = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Tree)...),
+			key.WithHelp(cfg.Tree, "tree"),
+		)
+	}
+	if cfg.Collapse != "" {
+		km.Collapse = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Collapse)...),
+			key.WithHelp(cfg.Collapse, "collapse"),
+		)
+	}
+	if cfg.Expand != "" {
+		km.Expand = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Expand)...),
+			key.WithHelp(cfg.Expand, "expand"),
+		)
+	}
 	if cfg.Help != "" {
 		km.Help = key.NewBinding(
 			key.WithKeys(parseKeys(cfg.Help)...),
 			key.WithHelp(cfg.Help, "help"),
 		)
 	}
+	if cfg.Palette != "" {
+		km.Palette = key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Palette)...),
+			key.WithHelp(cfg.Palette, "commands"),
+		)
+	}
 	if cfg.Quit != "" {
 		km.Quit = key.NewBinding(
 			key.WithKeys(parseKeys(cfg.Quit)...),
@@ -214,56 +459,190 @@ func KeyMapFromConfig(cfg *config.KeysConfig) KeyMap {
 		)
 	}
 
+	modes := &cfg.Modes
+	overrideBinding(&km.Yes, modes.Confirm.Yes)
+	overrideBinding(&km.No, modes.Confirm.No)
+	overrideBinding(&km.IncludePinned, modes.Confirm.IncludePinned)
+	overrideBinding(&km.Confirm, modes.Picker.Select)
+	overrideBinding(&km.Cancel, modes.Picker.Cancel)
+	overrideBinding(&km.Reuse, modes.Clash.Reuse)
+	overrideBinding(&km.NewName, modes.Clash.NewName)
+	overrideBinding(&km.StashPop, modes.Stash.Pop)
+	overrideBinding(&km.StashApply, modes.Stash.Apply)
+	overrideBinding(&km.StashApplyTo, modes.Stash.ApplyTo)
+	overrideBinding(&km.StashBranch, modes.Stash.Branch)
+	overrideBinding(&km.StashDrop, modes.Stash.Drop)
+	overrideBinding(&km.BranchCreate, modes.Branches.Create)
+	overrideBinding(&km.BranchDelete, modes.Branches.Delete)
+	overrideBinding(&km.BranchPrune, modes.Branches.Prune)
+	overrideBinding(&km.BranchRename, modes.Branches.Rename)
+	overrideBinding(&km.BranchUpstream, modes.Branches.Upstream)
+
 	return km
 }
 
-// parseKeys parses a comma-separated list of keys.
+// overrideBinding replaces the keys of b with the configured ones, keeping
+// its description. An empty setting keeps the default.
+func overrideBinding(b *key.Binding, keys string) {
+	if keys == "" {
+		return
+	}
+	*b = key.NewBinding(
+		key.WithKeys(parseKeys(keys)...),
+		key.WithHelp(keys, b.Help().Desc),
+	)
+}
+
+// parseKeys parses a comma-separated list of keys. Each entry may be a
+// space-separated sequence such as "g g", stored with single spaces.
+// "space" is accepted as a name for the space bar, which bubbletea reports as " ".
 func parseKeys(s string) []string {
 	parts := strings.Split(s, ",")
 	var keys []string
 	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p != "" {
-			keys = append(keys, p)
+		seq := strings.Fields(p)
+		for i, k := range seq {
+			if k == "space" {
+				seq[i] = " "
+			}
+		}
+		if len(seq) > 0 {
+			keys = append(keys, strings.Join(seq, " "))
 		}
 	}
 	return keys
 }
 
-// HelpSections returns the help sections for display, generated from the KeyMap.
+// HelpSections returns the help sections for display, generated from the
+// list actions and their bindings.
 func (km KeyMap) HelpSections() []ui.HelpSection {
-	return []ui.HelpSection{
-		{
-			Title: "Navigation",
-			Bindings: []ui.HelpBinding{
-				{Keys: km.Up.Help().Key, Desc: "Move up"},
-				{Keys: km.Down.Help().Key, Desc: "Move down"},
-				{Keys: km.Home.Help().Key, Desc: "Go to first"},
-				{Keys: km.End.Help().Key, Desc: "Go to last"},
-				{Keys: km.Open.Help().Key, Desc: "Open worktree"},
-			},
-		},
-		{
-			Title: "Actions",
-			Bindings: []ui.HelpBinding{
-				{Keys: km.New.Help().Key, Desc: "New worktree"},
-				{Keys: km.Delete.Help().Key, Desc: "Delete worktree"},
-				{Keys: km.Rename.Help().Key, Desc: "Rename branch"},
-				{Keys: km.Fetch.Help().Key, Desc: "Fetch all remotes"},
-				{Keys: km.Prune.Help().Key, Desc: "Prune stale worktrees"},
-				{Keys: km.Stash.Help().Key, Desc: "Manage stashes"},
-				{Keys: km.Filter.Help().Key, Desc: "Filter worktrees"},
-				{Keys: km.Detail.Help().Key, Desc: "Toggle detail panel"},
-				{Keys: km.Sort.Help().Key, Desc: "Cycle sort order"},
-			},
-		},
-		{
-			Title: "General",
-			Bindings: []ui.HelpBinding{
-				{Keys: km.Help.Help().Key, Desc: "Toggle this help"},
-				{Keys: km.Quit.Help().Key, Desc: "Quit"},
-				{Keys: "esc", Desc: "Cancel / Close"},
-			},
-		},
+	// Keys that aren't actions of their own
+	extras := map[string][]ui.HelpBinding{
+		"Navigation": {{Keys: "1-9", Desc: "Open pinned worktree"}},
+		"Tree":       {{Keys: km.Open.Help().Key, Desc: "Toggle group"}},
+		"General":    {{Keys: "esc", Desc: "Cancel / Close"}},
+	}
+
+	var sections []ui.HelpSection
+	for _, a := range actions {
+		if len(sections) == 0 || sections[len(sections)-1].Title != a.Section {
+			sections = append(sections, ui.HelpSection{Title: a.Section})
+		}
+		section := &sections[len(sections)-1]
+		section.Bindings = append(section.Bindings, ui.HelpBinding{Keys: km.Binding(a.ID).Help().Key, Desc: a.Desc})
+	}
+	for i := range sections {
+		sections[i].Bindings = append(sections[i].Bindings, extras[sections[i].Title]...)
+	}
+
+	// The branches view has its own keys
+	sections = append(sections, ui.HelpSection{Title: "Branches view", Bindings: []ui.HelpBinding{
+		{Keys: km.BranchCreate.Help().Key, Desc: "Create a worktree for the branch"},
+		{Keys: km.BranchDelete.Help().Key, Desc: "Delete branch"},
+		{Keys: km.BranchRename.Help().Key, Desc: "Rename branch"},
+		{Keys: km.BranchUpstream.Help().Key, Desc: "Set upstream"},
+		{Keys: km.BranchPrune.Help().Key, Desc: "Prune merged branches"},
+	}})
+	return sections
+}
+
+// screenBindings returns the bindings active in the current state, or nil
+// for states that take text input, where keys are never chorded.
+func (m *Model) screenBindings() []key.Binding {
+	km := &m.keys
+	nav := []key.Binding{km.Up, km.Down, km.Home, km.End}
+	confirm := []key.Binding{km.Yes, km.No, km.IncludePinned}
+
+	switch m.state {
+	case StateList:
+		bindings := make([]key.Binding, 0, len(actions))
+		for _, a := range actions {
+			bindings = append(bindings, km.Binding(a.ID))
+		}
+		return bindings
+	case StateCreateSelectBase, StateSelectSparse, StateSelectLayout:
+		return append(nav, km.Confirm, km.Cancel)
+	case StateCreateRemoteClash:
+		return []key.Binding{km.Reuse, km.NewName, km.Cancel}
+	case StateStash:
+		return append(nav, km.StashPop, km.StashApply, km.StashApplyTo, km.StashBranch, km.StashDrop)
+	case StateStashApplyTo:
+		return append(nav, km.Confirm, km.Cancel)
+	case StateBranches:
+		return append(nav, km.BranchCreate, km.BranchDelete, km.BranchPrune, km.BranchRename, km.BranchUpstream)
+	case StateDelete:
+		if m.safetyInfo != nil && m.safetyInfo.Level == git.SafetyLevelDanger && m.config.Safety.RequireTypingForUnique {
+			return nil
+		}
+		return confirm
+	case StateDeleteConfirmCloseWindow, StateDeleteConfirmBranch, StatePruneConfirm,
+		StatePruneGoneConfirm, StatePushConfirmForce, StateBranchDeleteConfirm:
+		return confirm
+	}
+	return nil
+}
+
+// resolveSequence tracks multi-key sequences such as "g g". It returns the
+// key to handle, which for a completed sequence is a synthetic key whose
+// String() is the whole sequence so key.Matches works on it, and false
+// while a sequence is still being typed. A key that starts a sequence
+// always waits for the next one; a key that doesn't continue the pending
+// sequence drops it and is handled on its own.
+func (m *Model) resolveSequence(msg tea.KeyMsg, bindings []key.Binding) (tea.KeyMsg, bool) {
+	if len(m.pendingKeys) > 0 && msg.Type == tea.KeyEsc {
+		m.pendingKeys = nil
+		return msg, false
+	}
+
+	pending := m.pendingKeys
+	m.pendingKeys = nil
+	seq := strings.Join(append(slices.Clone(pending), msg.String()), " ")
+
+	complete := false
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		for _, k := range b.Keys() {
+			if strings.HasPrefix(k, seq+" ") {
+				m.pendingKeys = append(pending, msg.String())
+				return msg, false
+			}
+			if k == seq {
+				complete = true
+			}
+		}
+	}
+
+	switch {
+	case complete && len(pending) > 0:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(seq)}, true
+	case len(pending) > 0:
+		return m.resolveSequence(msg, bindings)
+	}
+	return msg, true
+}
+
+// Hints returns the keys named in screen footers.
+func (km KeyMap) Hints() ui.KeyHints {
+	k := func(b key.Binding) string { return b.Help().Key }
+	return ui.KeyHints{
+		Yes:            k(km.Yes),
+		No:             k(km.No),
+		IncludePinned:  k(km.IncludePinned),
+		Confirm:        k(km.Confirm),
+		Cancel:         k(km.Cancel),
+		Reuse:          k(km.Reuse),
+		NewName:        k(km.NewName),
+		StashPop:       k(km.StashPop),
+		StashApply:     k(km.StashApply),
+		StashApplyTo:   k(km.StashApplyTo),
+		StashBranch:    k(km.StashBranch),
+		StashDrop:      k(km.StashDrop),
+		BranchCreate:   k(km.BranchCreate),
+		BranchDelete:   k(km.BranchDelete),
+		BranchPrune:    k(km.BranchPrune),
+		BranchRename:   k(km.BranchRename),
+		BranchUpstream: k(km.BranchUpstream),
 	}
 }
@@ -2,7 +2,13 @@
 package app
 
 import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/henri123lemoine/grove/internal/config"
 	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
 )
 
 // Message types for the bubbletea app.
@@ -30,6 +36,10 @@ type WorktreeCreatedMsg struct {
 	Path   string
 	Branch string
 	Err    error
+
+	// SubmoduleErr reports a failed submodule update; the worktree itself
+	// was still created.
+	SubmoduleErr error
 }
 
 // WorktreeDeletedMsg is sent when a worktree is deleted.
@@ -40,6 +50,7 @@ type WorktreeDeletedMsg struct {
 
 // WorktreeOpenedMsg is sent when a worktree is opened.
 type WorktreeOpenedMsg struct {
+	Path        string
 	Err         error
 	IsNewWindow bool
 }
@@ -61,6 +72,27 @@ type BranchRenamedMsg struct {
 	Err     error
 }
 
+// WorktreeMovedMsg is sent when a worktree move completes.
+type WorktreeMovedMsg struct {
+	OldPath string
+	NewPath string
+	Err     error
+}
+
+// WorktreeLockChangedMsg is sent when a worktree is locked or unlocked.
+type WorktreeLockChangedMsg struct {
+	Path   string
+	Locked bool
+	Err    error
+}
+
+// NoteSavedMsg is sent when a worktree's note has been saved.
+type NoteSavedMsg struct {
+	Path string
+	Note state.Note
+	Err  error
+}
+
 // StashCreatedMsg is sent when a stash is created.
 type StashCreatedMsg struct {
 	Err error
@@ -71,11 +103,24 @@ type StashPoppedMsg struct {
 	Err error
 }
 
+// FileCopyProgressMsg is sent periodically while files are copied into a new worktree.
+type FileCopyProgressMsg struct {
+	Progress git.CopyProgress
+	updates  <-chan tea.Msg // Source of the next progress or completion message
+}
+
 // FileCopyCompletedMsg is sent when file copy completes.
 type FileCopyCompletedMsg struct {
 	Err error
 }
 
+// PortsAllocatedMsg is sent when a new worktree's port block is reserved.
+type PortsAllocatedMsg struct {
+	Path  string
+	Block state.PortBlock
+	Err   error
+}
+
 // PruneCompletedMsg is sent when worktree pruning completes.
 type PruneCompletedMsg struct {
 	PrunedCount int
@@ -88,6 +133,13 @@ type StashListLoadedMsg struct {
 	Err     error
 }
 
+// StashDiffLoadedMsg is sent when the preview of a stash is loaded.
+type StashDiffLoadedMsg struct {
+	Index int
+	Diff  string
+	Err   error
+}
+
 // StashOperationCompletedMsg is sent when a stash operation completes.
 type StashOperationCompletedMsg struct {
 	Operation string // "pop", "apply", or "drop"
@@ -100,11 +152,29 @@ type DetailLoadedMsg struct {
 	LastCommitHash    string
 	LastCommitMessage string
 	LastCommitTime    string
+	Sparse            *git.SparseCheckout
+}
+
+// SparseWidenedMsg is sent when a worktree's sparse checkout is widened.
+type SparseWidenedMsg struct {
+	Path string
+	Err  error
+}
+
+// RecentOpensLoadedMsg is sent when the open history has been read.
+type RecentOpensLoadedMsg struct {
+	Opened map[string]time.Time
+}
+
+// ListViewLoadedMsg is sent when the saved list layout has been read.
+type ListViewLoadedMsg struct {
+	View state.ListView
 }
 
 // UpstreamLoadedMsg is sent when upstream status is loaded for all worktrees.
 type UpstreamLoadedMsg struct {
 	Worktrees []git.Worktree
+	Merged    bool // IsMerged and UniqueCommits were loaded too
 }
 
 // BranchDeletedMsg is sent when a branch is deleted.
@@ -120,3 +190,47 @@ type WorktreesCachedMsg struct {
 	FromCache bool
 	Err       error
 }
+
+// PushCompletedMsg is sent when pushing one or more worktrees completes.
+type PushCompletedMsg struct {
+	Pushed int
+	Err    error
+}
+
+// BranchDetailsLoadedMsg is sent when the branches view data is loaded.
+type BranchDetailsLoadedMsg struct {
+	Branches []git.BranchInfo
+	Err      error
+}
+
+// BranchOperationCompletedMsg is sent when a branches view operation completes.
+type BranchOperationCompletedMsg struct {
+	Operation string // "delete", "rename", or "upstream"
+	Err       error
+}
+
+// GoneBranchesCheckedMsg is sent when gone-upstream branches have been found
+// and safety-checked.
+type GoneBranchesCheckedMsg struct {
+	Branches []git.GoneBranch
+	Err      error
+}
+
+// PruneGoneCompletedMsg is sent when pruning gone-upstream branches completes.
+type PruneGoneCompletedMsg struct {
+	Pruned int
+	Err    error
+}
+
+// ConfigReloadedMsg is sent when the config file changed on disk and was
+// read again.
+type ConfigReloadedMsg struct {
+	Config  *config.Config
+	ModTime time.Time
+	Err     error
+}
+
+// configPollMsg re-arms the config watcher when the file is unchanged.
+type configPollMsg struct {
+	modTime time.Time
+}
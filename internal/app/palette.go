@@ -0,0 +1,105 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+
+	"github.com/henri123lemoine/grove/internal/ui"
+)
+
+// paletteSource implements fuzzy.Source over palette candidates, matching
+// on the description followed by the config name.
+type paletteSource []action
+
+func (s paletteSource) String(i int) string { return s[i].Desc + " " + string(s[i].ID) }
+func (s paletteSource) Len() int            { return len(s) }
+
+// openPalette shows the command palette for the current selection.
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.state = StatePalette
+	m.paletteInput.Reset()
+	m.paletteInput.Focus()
+	m.paletteCursor = 0
+	m.updatePalette()
+	return m, textinput.Blink
+}
+
+// updatePalette recomputes the palette entries from the typed query. Only
+// actions available for the current selection are offered; an empty query
+// keeps help order, otherwise entries are ranked by fuzzy score.
+func (m *Model) updatePalette() {
+	var candidates []action
+	for _, a := range actions {
+		if a.hidden || (a.treeOnly && !m.treeView) {
+			continue
+		}
+		if a.available != nil && !a.available(m) {
+			continue
+		}
+		candidates = append(candidates, a)
+	}
+
+	m.paletteMatches = nil
+	if query := m.paletteInput.Value(); query != "" {
+		for _, match := range fuzzy.FindFrom(query, paletteSource(candidates)) {
+			m.paletteMatches = append(m.paletteMatches, candidates[match.Index].ID)
+		}
+	} else {
+		for _, a := range candidates {
+			m.paletteMatches = append(m.paletteMatches, a.ID)
+		}
+	}
+
+	if m.paletteCursor >= len(m.paletteMatches) {
+		m.paletteCursor = max(len(m.paletteMatches)-1, 0)
+	}
+}
+
+// handlePaletteKeys handles key presses in the command palette.
+func (m Model) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = StateList
+		m.paletteInput.Blur()
+		return m, nil
+	case "enter":
+		m.state = StateList
+		m.paletteInput.Blur()
+		if m.paletteCursor >= len(m.paletteMatches) {
+			return m, nil
+		}
+		return m.runAction(m.paletteMatches[m.paletteCursor])
+	case "up", "ctrl+p":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if m.paletteCursor < len(m.paletteMatches)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteCursor = 0
+	m.updatePalette()
+	return m, cmd
+}
+
+// paletteEntries returns the palette matches with their current key bindings.
+func (m Model) paletteEntries() []ui.HelpBinding {
+	entries := make([]ui.HelpBinding, 0, len(m.paletteMatches))
+	for _, id := range m.paletteMatches {
+		for _, a := range actions {
+			if a.ID == id {
+				entries = append(entries, ui.HelpBinding{Keys: m.keys.Binding(id).Help().Key, Desc: a.Desc})
+				break
+			}
+		}
+	}
+	return entries
+}
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henri123lemoine/grove/internal/git"
+)
+
+// filterQuery is a parsed filter: qualifiers such as is:dirty or age:>14d
+// narrow the list, and the remaining text is fuzzy-matched.
+type filterQuery struct {
+	text       string
+	qualifiers []qualifier
+	invalid    []string // Terms that look like qualifiers but aren't valid
+}
+
+// qualifier is a single key:value term, optionally negated with a leading -.
+type qualifier struct {
+	term   string // As typed, e.g. "-is:dirty"
+	negate bool
+	match  func(wt *git.Worktree) bool
+	merged bool // Needs merge status, which is loaded on demand
+}
+
+// isQualifiers are the states accepted by is:.
+var isQualifiers = map[string]func(wt *git.Worktree) bool{
+	"dirty":    func(wt *git.Worktree) bool { return wt.IsDirty },
+	"clean":    func(wt *git.Worktree) bool { return !wt.IsDirty },
+	"merged":   func(wt *git.Worktree) bool { return wt.IsMerged },
+	"ahead":    func(wt *git.Worktree) bool { return wt.Ahead > 0 },
+	"behind":   func(wt *git.Worktree) bool { return wt.Behind > 0 },
+	"detached": func(wt *git.Worktree) bool { return wt.IsDetached },
+	"locked":   func(wt *git.Worktree) bool { return wt.IsLocked },
+	"pinned":   func(wt *git.Worktree) bool { return wt.Pin > 0 },
+	"main":     func(wt *git.Worktree) bool { return wt.IsMain },
+}
+
+// hasQualifiers are the properties accepted by has:.
+var hasQualifiers = map[string]func(wt *git.Worktree) bool{
+	"upstream": func(wt *git.Worktree) bool { return wt.HasUpstream },
+	"note":     func(wt *git.Worktree) bool { return wt.Note != "" },
+	"labels":   func(wt *git.Worktree) bool { return len(wt.Labels) > 0 },
+}
+
+// parseFilterQuery splits filter input into qualifiers and fuzzy text.
+// Words that aren't qualifiers, including unknown key:value words, are
+// kept as text; malformed values of known keys are reported as invalid.
+func parseFilterQuery(input string, now time.Time) filterQuery {
+	var q filterQuery
+	var words []string
+	for _, word := range strings.Fields(input) {
+		key, value, ok := strings.Cut(strings.TrimPrefix(word, "-"), ":")
+		if !ok || !isQualifierKey(key) {
+			words = append(words, word)
+			continue
+		}
+		qual, err := parseQualifier(key, value, now)
+		if err != nil {
+			q.invalid = append(q.invalid, word)
+			continue
+		}
+		qual.term = word
+		qual.negate = strings.HasPrefix(word, "-")
+		q.qualifiers = append(q.qualifiers, qual)
+	}
+	q.text = strings.Join(words, " ")
+	return q
+}
+
+func isQualifierKey(key string) bool {
+	switch key {
+	case "is", "has", "age", "label":
+		return true
+	}
+	return false
+}
+
+func parseQualifier(key, value string, now time.Time) (qualifier, error) {
+	switch key {
+	case "is":
+		if match, ok := isQualifiers[value]; ok {
+			return qualifier{match: match, merged: value == "merged"}, nil
+		}
+		return qualifier{}, fmt.Errorf("unknown state %q", value)
+	case "has":
+		if match, ok := hasQualifiers[value]; ok {
+			return qualifier{match: match}, nil
+		}
+		return qualifier{}, fmt.Errorf("unknown property %q", value)
+	case "label":
+		if value == "" {
+			return qualifier{}, fmt.Errorf("missing label")
+		}
+		label := strings.TrimPrefix(value, "#")
+		return qualifier{match: func(wt *git.Worktree) bool {
+			return slices.Contains(wt.Labels, label)
+		}}, nil
+	case "age":
+		return parseAgeQualifier(value, now)
+	}
+	return qualifier{}, fmt.Errorf("unknown qualifier %q", key)
+}
+
+// parseAgeQualifier parses "<N" or ">N" with N a number followed by h, d
+// or w, compared against the age of the worktree's last commit. Worktrees
+// whose commit time hasn't loaded yet match neither.
+func parseAgeQualifier(value string, now time.Time) (qualifier, error) {
+	if value == "" {
+		return qualifier{}, fmt.Errorf("missing age")
+	}
+	older := value[0] == '>'
+	if !older && value[0] != '<' {
+		return qualifier{}, fmt.Errorf("age needs > or <")
+	}
+	value = value[1:]
+	if len(value) < 2 {
+		return qualifier{}, fmt.Errorf("age needs a number and a unit")
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return qualifier{}, fmt.Errorf("invalid age %q", value)
+	}
+	var unit time.Duration
+	switch value[len(value)-1] {
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return qualifier{}, fmt.Errorf("unknown age unit in %q", value)
+	}
+	cutoff := now.Add(-time.Duration(n) * unit)
+	return qualifier{match: func(wt *git.Worktree) bool {
+		if wt.CommitTime.IsZero() {
+			return false
+		}
+		if older {
+			return wt.CommitTime.Before(cutoff)
+		}
+		return wt.CommitTime.After(cutoff)
+	}}, nil
+}
+
+// matches reports whether wt satisfies every qualifier. The text is matched
+// separately so results keep their fuzzy ranking.
+func (q filterQuery) matches(wt *git.Worktree) bool {
+	for _, qual := range q.qualifiers {
+		if qual.match(wt) == qual.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// needsMerged reports whether a qualifier depends on merge status.
+func (q filterQuery) needsMerged() bool {
+	return slices.ContainsFunc(q.qualifiers, func(qual qualifier) bool { return qual.merged })
+}
+
+// terms returns the active qualifiers as typed.
+func (q filterQuery) terms() []string {
+	terms := make([]string, len(q.qualifiers))
+	for i, qual := range q.qualifiers {
+		terms[i] = qual.term
+	}
+	return terms
+}
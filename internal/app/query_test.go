@@ -0,0 +1,76 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/henri123lemoine/grove/internal/git"
+)
+
+func TestParseFilterQuery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		text    string
+		terms   []string
+		invalid []string
+	}{
+		{"", "", nil, nil},
+		{"auth", "auth", nil, nil},
+		{"is:dirty auth -is:merged", "auth", []string{"is:dirty", "-is:merged"}, nil},
+		{"age:>14d has:upstream label:#wip", "", []string{"age:>14d", "has:upstream", "label:#wip"}, nil},
+		{"is:shiny age:14d age:>2y", "", nil, []string{"is:shiny", "age:14d", "age:>2y"}},
+		{"foo:bar -x", "foo:bar -x", nil, nil},
+	}
+	for _, tt := range tests {
+		q := parseFilterQuery(tt.input, now)
+		if q.text != tt.text {
+			t.Errorf("parseFilterQuery(%q).text = %q, want %q", tt.input, q.text, tt.text)
+		}
+		if terms := q.terms(); !slices.Equal(terms, tt.terms) {
+			t.Errorf("parseFilterQuery(%q) terms = %v, want %v", tt.input, terms, tt.terms)
+		}
+		if !slices.Equal(q.invalid, tt.invalid) {
+			t.Errorf("parseFilterQuery(%q) invalid = %v, want %v", tt.input, q.invalid, tt.invalid)
+		}
+	}
+}
+
+func TestFilterQueryMatches(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := git.Worktree{Branch: "old", IsMerged: true, HasUpstream: true, CommitTime: now.AddDate(0, 0, -30)}
+	fresh := git.Worktree{Branch: "fresh", IsDirty: true, Ahead: 2, Labels: []string{"wip"}, CommitTime: now.Add(-time.Hour)}
+	unloaded := git.Worktree{Branch: "unloaded"}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"is:dirty", []string{"fresh"}},
+		{"-is:dirty", []string{"old", "unloaded"}},
+		{"-is:dirty is:merged age:>14d", []string{"old"}},
+		{"age:<1d", []string{"fresh"}},
+		{"is:ahead label:wip", []string{"fresh"}},
+		{"-has:upstream", []string{"fresh", "unloaded"}},
+	}
+	for _, tt := range tests {
+		q := parseFilterQuery(tt.query, now)
+		var got []string
+		for _, wt := range []git.Worktree{old, fresh, unloaded} {
+			if q.matches(&wt) {
+				got = append(got, wt.Branch)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if !parseFilterQuery("-is:merged", now).needsMerged() {
+		t.Error("is:merged should need merge status")
+	}
+	if parseFilterQuery("is:dirty", now).needsMerged() {
+		t.Error("is:dirty should not need merge status")
+	}
+}
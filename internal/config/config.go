@@ -3,9 +3,12 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
@@ -17,10 +20,15 @@ type Config struct {
 	Open     OpenConfig     `toml:"open"`
 	Delete   DeleteConfig   `toml:"delete"`
 	Worktree WorktreeConfig `toml:"worktree"`
+	Branch   BranchConfig   `toml:"branch"`
+	Ports    PortsConfig    `toml:"ports"`
 	Safety   SafetyConfig   `toml:"safety"`
 	UI       UIConfig       `toml:"ui"`
 	Keys     KeysConfig     `toml:"keys"`
 	Layouts  []LayoutConfig `toml:"layouts"`
+
+	// User-defined color themes, selected by name with ui.theme
+	Themes map[string]ThemeConfig `toml:"themes"`
 }
 
 // GeneralConfig contains general settings.
@@ -31,6 +39,12 @@ type GeneralConfig struct {
 	// Directory for worktrees (relative to main worktree root)
 	WorktreeDir string `toml:"worktree_dir"`
 
+	// Path template for new worktrees; overrides worktree_dir when set.
+	// Relative paths are resolved against the main worktree root, and a
+	// leading ~ expands to the home directory.
+	// Template variables: {repo}, {branch}, {branch_short}, {user}, {date}
+	WorktreePath string `toml:"worktree_path"`
+
 	// Default remote name (empty = auto-detect)
 	Remote string `toml:"remote"`
 }
@@ -38,7 +52,8 @@ type GeneralConfig struct {
 // OpenConfig contains settings for opening worktrees.
 type OpenConfig struct {
 	// Command to run when opening a worktree
-	// Template variables: {path}, {branch}, {branch_short}, {repo}, {window_name}
+	// Template variables: {path}, {branch}, {branch_short}, {repo}, {window_name},
+	// {note}, plus {port} and {port_N} when [ports] is enabled
 	Command string `toml:"command"`
 
 	// How to detect existing windows: "path", "name", or "none"
@@ -73,15 +88,78 @@ type DeleteConfig struct {
 	DeleteBranchAction string `toml:"delete_branch_action"`
 }
 
+// BranchConfig contains settings for naming new branches.
+type BranchConfig struct {
+	// Prefixes offered as tab completions in the create input (e.g., "feat/")
+	Prefixes []string `toml:"prefixes"`
+
+	// Name templates filled in field by field (e.g., "feat/{ticket}-{slug}").
+	// Any {field} is prompted for; {slug} input is slugified.
+	Templates []string `toml:"templates"`
+
+	// Regular expression new branch names must match (empty = no restriction)
+	Pattern string `toml:"pattern"`
+}
+
+// PortsConfig contains settings for per-worktree port allocation.
+type PortsConfig struct {
+	// Reserve a block of ports for each worktree
+	Enabled bool `toml:"enabled"`
+
+	// First port of the first block
+	Base int `toml:"base"`
+
+	// Number of ports in each block ({port}, {port_1}, ...)
+	BlockSize int `toml:"block_size"`
+
+	// Maximum number of blocks handed out across all repositories
+	MaxBlocks int `toml:"max_blocks"`
+
+	// Write the allocated ports to .env.grove in new worktrees
+	WriteEnv bool `toml:"write_env"`
+}
+
 // WorktreeConfig contains settings for worktree creation.
 type WorktreeConfig struct {
-	// File patterns to copy to new worktrees (e.g., ".env*")
-	// Uses filepath.Glob syntax (*, ?, [abc]). Note: ** is not supported.
+	// File patterns to copy to new worktrees (e.g., ".env*", "**/node_modules")
+	// Uses glob syntax (*, ?, [abc]); ** matches any number of directories.
 	CopyPatterns []string `toml:"copy_patterns"`
 
-	// File patterns to ignore when copying (matched against file/directory names)
-	// Uses filepath.Match syntax (*, ?, [abc]). Note: ** is not supported.
+	// File patterns to ignore when copying (matched against paths and names)
+	// Uses glob syntax (*, ?, [abc]); ** matches any number of directories.
 	CopyIgnores []string `toml:"copy_ignores"`
+
+	// How matched files are copied: "auto", "copy", "reflink", "hardlink", "symlink"
+	// "auto" - copy-on-write clone where the filesystem supports it, else copy
+	CopyMode string `toml:"copy_mode"`
+
+	// Per-pattern overrides of copy_mode, keyed by copy_patterns entry
+	CopyModes map[string]string `toml:"copy_modes"`
+
+	// Move the worktree directory to match the new branch name after a rename
+	MoveOnRename bool `toml:"move_on_rename"`
+
+	// Run "git submodule update --init --recursive" in new worktrees
+	InitSubmodules bool `toml:"init_submodules"`
+
+	// Borrow submodule objects from the main worktree's checkouts
+	// (--reference) instead of fetching them again
+	SubmoduleReference bool `toml:"submodule_reference"`
+
+	// Named sparse-checkout profiles offered when creating a worktree
+	SparseProfiles []SparseProfile `toml:"sparse_profiles"`
+}
+
+// SparseProfile is a named set of directories for a cone-mode sparse checkout.
+type SparseProfile struct {
+	// Unique name for this profile
+	Name string `toml:"name"`
+
+	// Human-readable description
+	Description string `toml:"description"`
+
+	// Directories to check out, relative to the repository root
+	Paths []string `toml:"paths"`
 }
 
 // PaneConfig defines a pane in a layout.
@@ -134,31 +212,140 @@ type UIConfig struct {
 	// Show upstream tracking status
 	ShowUpstream bool `toml:"show_upstream"`
 
-	// Color theme: auto, dark, light
+	// Color theme: auto, dark, light, a built-in theme (e.g. catppuccin-mocha,
+	// gruvbox-dark) or the name of a [themes.*] table
 	Theme string `toml:"theme"`
 
-	// Default sort order: "default", "name", "name-desc", "dirty", "clean"
+	// Per-element color overrides applied on top of the theme
+	Colors ColorOverrides `toml:"colors"`
+
+	// List columns in display order. Append ":N" to cap a column at N cells,
+	// e.g. "last_commit:40". See ListColumns for the available names.
+	Columns []string `toml:"columns"`
+
+	// Colors for worktree labels (label -> hex or 0-255). Other labels get
+	// a theme color picked from the label name.
+	LabelColors map[string]string `toml:"label_colors"`
+
+	// Default sort order: "default", "name", "name-desc", "dirty", "clean",
+	// "recent", "last-commit"
 	DefaultSort string `toml:"default_sort"`
 }
 
-// KeysConfig contains keybinding settings.
+// ColorOverrides replaces the theme color of individual elements. Colors
+// are hex (#rgb, #rrggbb) or ANSI 256 indexes ("0"-"255").
+type ColorOverrides struct {
+	Dirty      string `toml:"dirty"`
+	Ahead      string `toml:"ahead"`
+	Behind     string `toml:"behind"`
+	Danger     string `toml:"danger"`
+	Selected   string `toml:"selected"`    // Selected row text
+	SelectedBg string `toml:"selected_bg"` // Selected row background
+}
+
+// ThemeConfig is a color palette. Unset colors come from Base.
+type ThemeConfig struct {
+	// Palette to start from: auto, dark, light or a built-in theme
+	Base string `toml:"base"`
+
+	Primary    string `toml:"primary"`
+	Secondary  string `toml:"secondary"`
+	Success    string `toml:"success"`
+	Warning    string `toml:"warning"`
+	Danger     string `toml:"danger"`
+	Muted      string `toml:"muted"`
+	Highlight  string `toml:"highlight"`
+	Text       string `toml:"text"`
+	Purple     string `toml:"purple"`
+	Background string `toml:"background"`
+}
+
+// KeysConfig contains keybinding settings. The top-level keys apply to the
+// worktree list; Modes holds the keys of the other screens. Each value is a
+// comma-separated list of keys or space-separated key sequences ("d d").
 type KeysConfig struct {
-	Up     string `toml:"up"`
-	Down   string `toml:"down"`
-	Home   string `toml:"home"`
-	End    string `toml:"end"`
-	Open   string `toml:"open"`
-	New    string `toml:"new"`
-	Delete string `toml:"delete"`
-	Rename string `toml:"rename"`
-	Filter string `toml:"filter"`
-	Fetch  string `toml:"fetch"`
-	Detail string `toml:"detail"`
-	Prune  string `toml:"prune"`
-	Stash  string `toml:"stash"`
-	Sort   string `toml:"sort"`
-	Help   string `toml:"help"`
-	Quit   string `toml:"quit"`
+	Up        string `toml:"up"`
+	Down      string `toml:"down"`
+	Home      string `toml:"home"`
+	End       string `toml:"end"`
+	Open      string `toml:"open"`
+	Previous  string `toml:"previous"`
+	New       string `toml:"new"`
+	Delete    string `toml:"delete"`
+	Rename    string `toml:"rename"`
+	Move      string `toml:"move"`
+	Lock      string `toml:"lock"`
+	Note      string `toml:"note"`
+	Pin       string `toml:"pin"`
+	Widen     string `toml:"widen"`
+	Filter    string `toml:"filter"`
+	Fetch     string `toml:"fetch"`
+	Detail    string `toml:"detail"`
+	Prune     string `toml:"prune"`
+	PruneGone string `toml:"prune_gone"`
+	Stash     string `toml:"stash"`
+	Sort      string `toml:"sort"`
+	Push      string `toml:"push"`
+	Mark      string `toml:"mark"`
+	Branches  string `toml:"branches"`
+	Tree      string `toml:"tree"`
+	Collapse  string `toml:"collapse"`
+	Expand    string `toml:"expand"`
+	Palette   string `toml:"palette"`
+	Help      string `toml:"help"`
+	Quit      string `toml:"quit"`
+
+	Modes ModeKeysConfig `toml:"modes"`
+}
+
+// ModeKeysConfig contains the keybindings of screens other than the list.
+// Screens with a cursor also use the list's up, down, home and end keys.
+type ModeKeysConfig struct {
+	Confirm  ConfirmKeysConfig  `toml:"confirm"`
+	Picker   PickerKeysConfig   `toml:"picker"`
+	Clash    ClashKeysConfig    `toml:"clash"`
+	Stash    StashKeysConfig    `toml:"stash"`
+	Branches BranchesKeysConfig `toml:"branches"`
+}
+
+// ConfirmKeysConfig contains the keys of yes/no prompts, such as delete,
+// prune and force-push confirmations.
+type ConfirmKeysConfig struct {
+	Yes           string `toml:"yes"`
+	No            string `toml:"no"`
+	IncludePinned string `toml:"include_pinned"` // Prune screens only
+}
+
+// PickerKeysConfig contains the keys of the base branch, sparse profile and
+// layout pickers.
+type PickerKeysConfig struct {
+	Select string `toml:"select"`
+	Cancel string `toml:"cancel"`
+}
+
+// ClashKeysConfig contains the keys of the prompt shown when a remote
+// branch's local name is already taken.
+type ClashKeysConfig struct {
+	Reuse   string `toml:"reuse"`
+	NewName string `toml:"new_name"`
+}
+
+// StashKeysConfig contains the keys of the stash view.
+type StashKeysConfig struct {
+	Pop     string `toml:"pop"`
+	Apply   string `toml:"apply"`
+	ApplyTo string `toml:"apply_to"` // Apply in another worktree
+	Branch  string `toml:"branch"`   // New branch and worktree from the stash
+	Drop    string `toml:"drop"`
+}
+
+// BranchesKeysConfig contains the keys of the branches view.
+type BranchesKeysConfig struct {
+	Create   string `toml:"create"`
+	Delete   string `toml:"delete"`
+	Prune    string `toml:"prune"`
+	Rename   string `toml:"rename"`
+	Upstream string `toml:"upstream"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -183,6 +370,19 @@ func DefaultConfig() *Config {
 		Worktree: WorktreeConfig{
 			CopyPatterns: []string{},
 			CopyIgnores:  []string{},
+			CopyMode:     "auto",
+			CopyModes:    map[string]string{},
+		},
+		Branch: BranchConfig{
+			Prefixes:  []string{},
+			Templates: []string{},
+		},
+		Ports: PortsConfig{
+			Enabled:   false,
+			Base:      3000,
+			BlockSize: 10,
+			MaxBlocks: 100,
+			WriteEnv:  false,
 		},
 		Safety: SafetyConfig{
 			ConfirmDirty:           true,
@@ -195,29 +395,61 @@ func DefaultConfig() *Config {
 			ShowUpstream:    true,
 			Theme:           "auto",
 			DefaultSort:     "default",
+			Columns:         []string{"pin", "branch", "dirty", "upstream", "lock", "labels", "note"},
 		},
 		Keys: KeysConfig{
-			Up:     "up,k",
-			Down:   "down,j",
-			Home:   "home,g",
-			End:    "end,G",
-			Open:   "enter",
-			New:    "n",
-			Delete: "d",
-			Rename: "r",
-			Filter: "/",
-			Fetch:  "f",
-			Detail: "tab",
-			Prune:  "P",
-			Stash:  "s",
-			Sort:   "o",
-			Help:   "?",
-			Quit:   "q,ctrl+c",
+			Up:        "up,k",
+			Down:      "down,j",
+			Home:      "home,g",
+			End:       "end,G",
+			Open:      "enter",
+			Previous:  "-",
+			New:       "n",
+			Delete:    "d",
+			Rename:    "r",
+			Move:      "m",
+			Lock:      "L",
+			Note:      "N",
+			Pin:       "*",
+			Widen:     "w",
+			Filter:    "/",
+			Fetch:     "f",
+			Detail:    "tab",
+			Prune:     "P",
+			PruneGone: "X",
+			Stash:     "s",
+			Sort:      "o",
+			Push:      "p",
+			Mark:      "space",
+			Branches:  "b",
+			Tree:      "t",
+			Collapse:  "left,h",
+			Expand:    "right,l",
+			Palette:   ":,ctrl+p",
+			Help:      "?",
+			Quit:      "q,ctrl+c",
+			Modes: ModeKeysConfig{
+				Confirm:  ConfirmKeysConfig{Yes: "y,Y", No: "n,N", IncludePinned: "a"},
+				Picker:   PickerKeysConfig{Select: "enter", Cancel: "esc,q"},
+				Clash:    ClashKeysConfig{Reuse: "r", NewName: "n"},
+				Stash:    StashKeysConfig{Pop: "p", Apply: "a", ApplyTo: "t", Branch: "b", Drop: "d,x"},
+				Branches: BranchesKeysConfig{Create: "enter,w", Delete: "d,x", Prune: "P", Rename: "r", Upstream: "u"},
+			},
 		},
 		Layouts: []LayoutConfig{},
 	}
 }
 
+// GetSparseProfile returns the sparse profile with the given name, or nil if not found.
+func (c *Config) GetSparseProfile(name string) *SparseProfile {
+	for i := range c.Worktree.SparseProfiles {
+		if c.Worktree.SparseProfiles[i].Name == name {
+			return &c.Worktree.SparseProfiles[i]
+		}
+	}
+	return nil
+}
+
 // GetLayoutByName returns the layout with the given name, or nil if not found.
 func (c *Config) GetLayoutByName(name string) *LayoutConfig {
 	for i := range c.Layouts {
@@ -337,12 +569,16 @@ func generateDefaultConfigContent() string {
 	b.WriteString("# Default base branch for new worktrees\n")
 	fmt.Fprintf(&b, "default_base_branch = %q\n", cfg.General.DefaultBaseBranch)
 	b.WriteString("# Directory for worktrees (relative to main worktree root)\n")
-	fmt.Fprintf(&b, "worktree_dir = %q\n\n", cfg.General.WorktreeDir)
+	fmt.Fprintf(&b, "worktree_dir = %q\n", cfg.General.WorktreeDir)
+	b.WriteString("# Path template for new worktrees (overrides worktree_dir when set)\n")
+	b.WriteString("# Variables: {repo}, {branch}, {branch_short}, {user}, {date}\n")
+	b.WriteString("# worktree_path = \"~/worktrees/{repo}/{branch}\"\n\n")
 
 	b.WriteString("[open]\n")
 	b.WriteString("# Command to run when opening a worktree (auto-detected if not set)\n")
 	b.WriteString("# Grove auto-detects tmux/zellij at runtime. Only set this to override.\n")
-	b.WriteString("# Template variables: {path}, {branch}, {branch_short}, {repo}, {window_name}\n")
+	b.WriteString("# Template variables: {path}, {branch}, {branch_short}, {repo}, {window_name},\n")
+	b.WriteString("# {note}, and {port}, {port_1}, ... when [ports] is enabled\n")
 	b.WriteString("# Variables are shell-escaped for safety.\n")
 	b.WriteString("# command = \"tmux new-window -n {branch_short} -c {path}\"\n")
 	b.WriteString("# How to detect existing windows: \"path\", \"name\", or \"none\"\n")
@@ -371,11 +607,47 @@ func generateDefaultConfigContent() string {
 
 	b.WriteString("[worktree]\n")
 	b.WriteString("# File patterns to copy to new worktrees\n")
-	b.WriteString("# Uses filepath.Glob syntax (*, ?, [abc]). Note: ** is not supported.\n")
+	b.WriteString("# Glob syntax (*, ?, [abc]); ** matches any number of directories.\n")
 	b.WriteString("# Directories are copied recursively.\n")
-	b.WriteString("# copy_patterns = [\".env*\"]\n")
-	b.WriteString("# File patterns to ignore when copying (matched against names)\n")
-	b.WriteString("# copy_ignores = [\"node_modules\", \"*.log\"]\n\n")
+	b.WriteString("# copy_patterns = [\".env*\", \"**/node_modules\"]\n")
+	b.WriteString("# File patterns to ignore when copying (matched against paths and names)\n")
+	b.WriteString("# copy_ignores = [\"*.log\"]\n")
+	b.WriteString("# How files are copied: \"auto\" (copy-on-write clone where supported),\n")
+	b.WriteString("# \"copy\", \"reflink\", \"hardlink\" or \"symlink\"\n")
+	fmt.Fprintf(&b, "copy_mode = %q\n", cfg.Worktree.CopyMode)
+	b.WriteString("# Per-pattern overrides of copy_mode\n")
+	b.WriteString("# copy_modes = { \"**/node_modules\" = \"symlink\" }\n")
+	b.WriteString("# Move the worktree directory to follow the branch name on rename\n")
+	fmt.Fprintf(&b, "move_on_rename = %v\n", cfg.Worktree.MoveOnRename)
+	b.WriteString("# Initialize submodules (recursively) in new worktrees\n")
+	fmt.Fprintf(&b, "init_submodules = %v\n", cfg.Worktree.InitSubmodules)
+	b.WriteString("# Share objects with the main worktree's submodules instead of fetching again\n")
+	fmt.Fprintf(&b, "submodule_reference = %v\n", cfg.Worktree.SubmoduleReference)
+	b.WriteString("# Sparse-checkout profiles offered when creating a worktree\n")
+	b.WriteString("# [[worktree.sparse_profiles]]\n")
+	b.WriteString("# name = \"api\"\n")
+	b.WriteString("# description = \"API service only\"\n")
+	b.WriteString("# paths = [\"services/api\", \"libs/shared\"]\n\n")
+
+	b.WriteString("[branch]\n")
+	b.WriteString("# Prefixes offered as tab completions when naming a branch\n")
+	b.WriteString("# prefixes = [\"feat/\", \"fix/\", \"chore/\"]\n")
+	b.WriteString("# Name templates (ctrl+t in the create flow); each {field} is prompted for\n")
+	b.WriteString("# templates = [\"feat/{ticket}-{slug}\"]\n")
+	b.WriteString("# Regular expression new branch names must match\n")
+	b.WriteString("# pattern = \"^(feat|fix|chore)/[a-z0-9-]+$\"\n\n")
+
+	b.WriteString("[ports]\n")
+	b.WriteString("# Reserve a unique block of ports per worktree, exposed as {port}, {port_1}, ...\n")
+	fmt.Fprintf(&b, "enabled = %v\n", cfg.Ports.Enabled)
+	b.WriteString("# First port of the first block\n")
+	fmt.Fprintf(&b, "base = %d\n", cfg.Ports.Base)
+	b.WriteString("# Ports per worktree\n")
+	fmt.Fprintf(&b, "block_size = %d\n", cfg.Ports.BlockSize)
+	b.WriteString("# Maximum number of blocks handed out\n")
+	fmt.Fprintf(&b, "max_blocks = %d\n", cfg.Ports.MaxBlocks)
+	b.WriteString("# Write the ports to .env.grove in new worktrees\n")
+	fmt.Fprintf(&b, "write_env = %v\n\n", cfg.Ports.WriteEnv)
 
 	b.WriteString("[safety]\n")
 	b.WriteString("# Confirm before deleting dirty worktrees\n")
@@ -392,24 +664,54 @@ func generateDefaultConfigContent() string {
 	fmt.Fprintf(&b, "show_commits = %v\n", cfg.UI.ShowCommits)
 	b.WriteString("# Show upstream tracking status\n")
 	fmt.Fprintf(&b, "show_upstream = %v\n", cfg.UI.ShowUpstream)
-	b.WriteString("# Color theme: \"auto\", \"dark\", or \"light\"\n")
+	b.WriteString("# Color theme: \"auto\", \"dark\", \"light\", a built-in theme\n")
+	fmt.Fprintf(&b, "# (%s)\n", strings.Join(slices.Sorted(maps.Keys(BuiltinThemes)), ", "))
+	b.WriteString("# or the name of a [themes.<name>] table\n")
 	fmt.Fprintf(&b, "theme = %q\n", cfg.UI.Theme)
-	b.WriteString("# Default sort order: \"default\", \"name\", \"name-desc\", \"dirty\", \"clean\"\n")
-	fmt.Fprintf(&b, "default_sort = %q\n\n", cfg.UI.DefaultSort)
+	b.WriteString("# Default sort order: \"default\", \"name\", \"name-desc\", \"dirty\", \"clean\",\n")
+	b.WriteString("# \"recent\" (last opened first), \"last-commit\" (newest commit first)\n")
+	fmt.Fprintf(&b, "default_sort = %q\n", cfg.UI.DefaultSort)
+	fmt.Fprintf(&b, "# List columns (%s); \"name:N\" caps a column at N cells\n", strings.Join(ListColumns, ", "))
+	fmt.Fprintf(&b, "columns = [%s]\n", quoteList(cfg.UI.Columns))
+	b.WriteString("# Label colors (hex or 0-255); other labels get a theme color\n")
+	b.WriteString("# label_colors = { urgent = \"#f38ba8\", review = \"4\" }\n")
+	b.WriteString("# Per-element colors on top of the theme (hex or 0-255)\n")
+	b.WriteString("# colors = { dirty = \"#fab387\", selected = \"#1e1e2e\", selected_bg = \"#89b4fa\" }\n\n")
 
 	b.WriteString("[keys]\n")
-	b.WriteString("# Keybindings (comma-separated for multiple keys)\n")
+	b.WriteString("# Keybindings (comma-separated for multiple keys, space-separated for\n")
+	b.WriteString("# sequences such as \"d d\")\n")
 	fmt.Fprintf(&b, "# up = %q\n", cfg.Keys.Up)
 	fmt.Fprintf(&b, "# down = %q\n", cfg.Keys.Down)
 	fmt.Fprintf(&b, "# open = %q\n", cfg.Keys.Open)
+	fmt.Fprintf(&b, "# previous = %q\n", cfg.Keys.Previous)
 	fmt.Fprintf(&b, "# new = %q\n", cfg.Keys.New)
 	fmt.Fprintf(&b, "# delete = %q\n", cfg.Keys.Delete)
 	fmt.Fprintf(&b, "# rename = %q\n", cfg.Keys.Rename)
+	fmt.Fprintf(&b, "# move = %q\n", cfg.Keys.Move)
+	fmt.Fprintf(&b, "# lock = %q\n", cfg.Keys.Lock)
+	fmt.Fprintf(&b, "# note = %q\n", cfg.Keys.Note)
+	fmt.Fprintf(&b, "# pin = %q\n", cfg.Keys.Pin)
+	fmt.Fprintf(&b, "# widen = %q\n", cfg.Keys.Widen)
 	fmt.Fprintf(&b, "# filter = %q\n", cfg.Keys.Filter)
 	fmt.Fprintf(&b, "# fetch = %q\n", cfg.Keys.Fetch)
 	fmt.Fprintf(&b, "# detail = %q\n", cfg.Keys.Detail)
+	fmt.Fprintf(&b, "# prune_gone = %q\n", cfg.Keys.PruneGone)
+	fmt.Fprintf(&b, "# push = %q\n", cfg.Keys.Push)
+	fmt.Fprintf(&b, "# mark = %q\n", cfg.Keys.Mark)
+	fmt.Fprintf(&b, "# branches = %q\n", cfg.Keys.Branches)
+	fmt.Fprintf(&b, "# tree = %q\n", cfg.Keys.Tree)
+	fmt.Fprintf(&b, "# collapse = %q\n", cfg.Keys.Collapse)
+	fmt.Fprintf(&b, "# expand = %q\n", cfg.Keys.Expand)
+	fmt.Fprintf(&b, "# palette = %q\n", cfg.Keys.Palette)
 	fmt.Fprintf(&b, "# help = %q\n", cfg.Keys.Help)
 	fmt.Fprintf(&b, "# quit = %q\n", cfg.Keys.Quit)
+	b.WriteString("# Other screens have their own tables, e.g.:\n")
+	b.WriteString("# [keys.modes.confirm]\n")
+	fmt.Fprintf(&b, "# yes = %q\n", cfg.Keys.Modes.Confirm.Yes)
+	fmt.Fprintf(&b, "# no = %q\n", cfg.Keys.Modes.Confirm.No)
+	b.WriteString("# [keys.modes.stash]\n")
+	fmt.Fprintf(&b, "# drop = %q\n", cfg.Keys.Modes.Stash.Drop)
 
 	b.WriteString("\n# Example layout (edit commands as needed)\n")
 	b.WriteString("# [[layouts]]\n")
@@ -420,9 +722,73 @@ func generateDefaultConfigContent() string {
 	b.WriteString("#   { split_from = 0, direction = \"right\", size = 50, command = \"claude\" }\n")
 	b.WriteString("# ]\n")
 
+	b.WriteString("\n# Example theme, selected with ui.theme = \"mine\"\n")
+	b.WriteString("# [themes.mine]\n")
+	b.WriteString("# base = \"dark\"\n")
+	b.WriteString("# primary = \"#7aa2f7\"\n")
+	b.WriteString("# warning = \"#e0af68\"\n")
+
 	return b.String()
 }
 
+// ListColumns are the column names accepted by ui.columns.
+var ListColumns = []string{"pin", "branch", "dirty", "upstream", "lock", "labels", "note", "age", "last_commit", "merged", "path"}
+
+// ParseColumn splits a ui.columns entry such as "last_commit:40" into the
+// column name and its width cap (0 = no cap).
+func ParseColumn(spec string) (name string, maxWidth int, err error) {
+	name, width, hasWidth := strings.Cut(spec, ":")
+	if !slices.Contains(ListColumns, name) {
+		return "", 0, fmt.Errorf("unknown column (expected one of %s)", strings.Join(ListColumns, ", "))
+	}
+	if hasWidth {
+		maxWidth, err = strconv.Atoi(width)
+		if err != nil || maxWidth <= 0 {
+			return "", 0, fmt.Errorf("width must be a positive number")
+		}
+	}
+	return name, maxWidth, nil
+}
+
+// HasColumn reports whether the list shows the named column.
+func (u UIConfig) HasColumn(name string) bool {
+	for _, spec := range u.Columns {
+		if n, _, _ := strings.Cut(spec, ":"); n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteList formats strings as the inside of a TOML array.
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = strconv.Quote(item)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// WorktreePathVars lists the template variables allowed in general.worktree_path.
+var WorktreePathVars = []string{"{repo}", "{branch}", "{branch_short}", "{user}", "{date}"}
+
+// portVarRe matches the {port} and {port_N} template variables.
+var portVarRe = regexp.MustCompile(`^\{port(?:_(\d+))?\}$`)
+
+// isPortVar reports whether v is {port}, or {port_N} with N inside the
+// configured block.
+func (c *Config) isPortVar(v string) bool {
+	m := portVarRe.FindStringSubmatch(v)
+	if m == nil {
+		return false
+	}
+	if m[1] == "" {
+		return true
+	}
+	n, err := strconv.Atoi(m[1])
+	return err == nil && n >= 1 && (c.Ports.BlockSize < 1 || n < c.Ports.BlockSize)
+}
+
 // Validate validates the configuration and returns warnings.
 func (c *Config) Validate() []string {
 	var warnings []string
@@ -439,6 +805,36 @@ func (c *Config) Validate() []string {
 		}
 	}
 
+	if c.General.WorktreePath != "" {
+		for _, v := range extractTemplateVars(c.General.WorktreePath) {
+			found := false
+			for _, valid := range WorktreePathVars {
+				if v == valid {
+					found = true
+					break
+				}
+			}
+			if !found {
+				warnings = append(warnings, fmt.Sprintf("Unknown template variable in general.worktree_path: %s", v))
+			}
+		}
+		if !strings.Contains(c.General.WorktreePath, "{branch}") && !strings.Contains(c.General.WorktreePath, "{branch_short}") {
+			warnings = append(warnings, "general.worktree_path should include {branch} or {branch_short} so each worktree gets its own directory")
+		}
+	}
+
+	// Validate BranchConfig
+	if c.Branch.Pattern != "" {
+		if _, err := regexp.Compile(c.Branch.Pattern); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Invalid regular expression in branch.pattern: %v", err))
+		}
+	}
+	for _, tmpl := range c.Branch.Templates {
+		if len(extractTemplateVars(tmpl)) == 0 {
+			warnings = append(warnings, fmt.Sprintf("branch.templates entry %q has no {fields} to fill in", tmpl))
+		}
+	}
+
 	// Validate WorktreeConfig copy patterns
 	for _, pattern := range c.Worktree.CopyPatterns {
 		_, err := filepath.Match(pattern, "test")
@@ -457,8 +853,52 @@ func (c *Config) Validate() []string {
 		}
 	}
 
+	// Validate sparse profiles
+	sparseNames := make(map[string]bool)
+	for _, profile := range c.Worktree.SparseProfiles {
+		if profile.Name == "" {
+			warnings = append(warnings, "Sparse profile has empty name")
+		} else if sparseNames[profile.Name] {
+			warnings = append(warnings, fmt.Sprintf("Duplicate sparse profile name: %s", profile.Name))
+		}
+		sparseNames[profile.Name] = true
+
+		if len(profile.Paths) == 0 {
+			warnings = append(warnings, fmt.Sprintf("Sparse profile %s has no paths", profile.Name))
+		}
+		for _, p := range profile.Paths {
+			if filepath.IsAbs(p) || strings.Contains(p, "..") {
+				warnings = append(warnings, fmt.Sprintf("Sparse profile %s: path %s must be relative to the repository root", profile.Name, p))
+			}
+			if strings.ContainsAny(p, "*?[") {
+				warnings = append(warnings, fmt.Sprintf("Sparse profile %s: path %s must be a directory, not a pattern (cone mode)", profile.Name, p))
+			}
+		}
+	}
+
+	// Check submodule options
+	if c.Worktree.SubmoduleReference && !c.Worktree.InitSubmodules {
+		warnings = append(warnings, "worktree.submodule_reference has no effect without worktree.init_submodules")
+	}
+
+	// Check copy modes
+	validCopyModes := map[string]bool{"auto": true, "copy": true, "reflink": true, "hardlink": true, "symlink": true}
+	if c.Worktree.CopyMode != "" && !validCopyModes[c.Worktree.CopyMode] {
+		warnings = append(warnings, fmt.Sprintf("Invalid value for worktree.copy_mode: %s (expected auto, copy, reflink, hardlink, or symlink)", c.Worktree.CopyMode))
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(c.Worktree.CopyModes)) {
+		mode := c.Worktree.CopyModes[pattern]
+		if !validCopyModes[mode] {
+			warnings = append(warnings, fmt.Sprintf("Invalid value for worktree.copy_modes[%q]: %s (expected auto, copy, reflink, hardlink, or symlink)", pattern, mode))
+		}
+		if !slices.Contains(c.Worktree.CopyPatterns, pattern) {
+			warnings = append(warnings, fmt.Sprintf("worktree.copy_modes[%q] does not match any copy_patterns entry", pattern))
+		}
+	}
+
 	// Check template variables in command
-	validVars := []string{"{path}", "{branch}", "{branch_short}", "{repo}", "{window_name}"}
+	usesPorts := false
+	validVars := []string{"{path}", "{branch}", "{branch_short}", "{repo}", "{window_name}", "{note}"}
 	vars := extractTemplateVars(c.Open.Command)
 	for _, v := range vars {
 		found := false
@@ -468,6 +908,10 @@ func (c *Config) Validate() []string {
 				break
 			}
 		}
+		if !found && c.isPortVar(v) {
+			found = true
+			usesPorts = true
+		}
 		if !found {
 			warnings = append(warnings, fmt.Sprintf("Unknown template variable in open.command: %s", v))
 		}
@@ -504,13 +948,8 @@ func (c *Config) Validate() []string {
 		warnings = append(warnings, fmt.Sprintf("Invalid value for delete.delete_branch_action: %s (expected ask, always, or never)", c.Delete.DeleteBranchAction))
 	}
 
-	// Check theme value
-	if c.UI.Theme != "" &&
-		c.UI.Theme != "auto" &&
-		c.UI.Theme != "dark" &&
-		c.UI.Theme != "light" {
-		warnings = append(warnings, fmt.Sprintf("Invalid value for ui.theme: %s (expected auto, dark, or light)", c.UI.Theme))
-	}
+	// Check theme, color overrides and user-defined themes
+	warnings = append(warnings, c.validateThemes()...)
 
 	// Check default_sort value
 	if c.UI.DefaultSort != "" &&
@@ -518,8 +957,24 @@ func (c *Config) Validate() []string {
 		c.UI.DefaultSort != "name" &&
 		c.UI.DefaultSort != "name-desc" &&
 		c.UI.DefaultSort != "dirty" &&
-		c.UI.DefaultSort != "clean" {
-		warnings = append(warnings, fmt.Sprintf("Invalid value for ui.default_sort: %s (expected default, name, name-desc, dirty, or clean)", c.UI.DefaultSort))
+		c.UI.DefaultSort != "clean" &&
+		c.UI.DefaultSort != "recent" &&
+		c.UI.DefaultSort != "last-commit" {
+		warnings = append(warnings, fmt.Sprintf("Invalid value for ui.default_sort: %s (expected default, name, name-desc, dirty, clean, recent, or last-commit)", c.UI.DefaultSort))
+	}
+
+	// Check list columns
+	seenColumns := make(map[string]bool)
+	for _, spec := range c.UI.Columns {
+		name, _, err := ParseColumn(spec)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Invalid ui.columns entry %q: %v", spec, err))
+			continue
+		}
+		if seenColumns[name] {
+			warnings = append(warnings, fmt.Sprintf("Duplicate ui.columns entry: %s", name))
+		}
+		seenColumns[name] = true
 	}
 
 	// Validate layouts
@@ -571,6 +1026,10 @@ func (c *Config) Validate() []string {
 							break
 						}
 					}
+					if !found && c.isPortVar(v) {
+						found = true
+						usesPorts = true
+					}
 					if !found {
 						warnings = append(warnings, fmt.Sprintf("Layout %s pane %d: unknown template variable %s", layout.Name, i, v))
 					}
@@ -579,47 +1038,172 @@ func (c *Config) Validate() []string {
 		}
 	}
 
-	// Validate key bindings for conflicts
-	keyBindings := map[string][]string{
-		"up":     strings.Split(c.Keys.Up, ","),
-		"down":   strings.Split(c.Keys.Down, ","),
-		"home":   strings.Split(c.Keys.Home, ","),
-		"end":    strings.Split(c.Keys.End, ","),
-		"open":   strings.Split(c.Keys.Open, ","),
-		"new":    strings.Split(c.Keys.New, ","),
-		"delete": strings.Split(c.Keys.Delete, ","),
-		"rename": strings.Split(c.Keys.Rename, ","),
-		"filter": strings.Split(c.Keys.Filter, ","),
-		"fetch":  strings.Split(c.Keys.Fetch, ","),
-		"detail": strings.Split(c.Keys.Detail, ","),
-		"prune":  strings.Split(c.Keys.Prune, ","),
-		"stash":  strings.Split(c.Keys.Stash, ","),
-		"sort":   strings.Split(c.Keys.Sort, ","),
-		"help":   strings.Split(c.Keys.Help, ","),
-		"quit":   strings.Split(c.Keys.Quit, ","),
-	}
-
-	// Build reverse map: key -> action(s)
-	keyToActions := make(map[string][]string)
-	for action, keys := range keyBindings {
-		for _, key := range keys {
-			key = strings.TrimSpace(key)
-			if key != "" {
+	// Validate PortsConfig
+	if c.Ports.Enabled {
+		if c.Ports.Base < 1 || c.Ports.Base > 65535 {
+			warnings = append(warnings, fmt.Sprintf("ports.base must be 1-65535, got %d", c.Ports.Base))
+		}
+		if c.Ports.BlockSize < 1 {
+			warnings = append(warnings, fmt.Sprintf("ports.block_size must be at least 1, got %d", c.Ports.BlockSize))
+		}
+		if c.Ports.MaxBlocks < 1 {
+			warnings = append(warnings, fmt.Sprintf("ports.max_blocks must be at least 1, got %d", c.Ports.MaxBlocks))
+		}
+		if last := c.Ports.Base + c.Ports.BlockSize*c.Ports.MaxBlocks - 1; c.Ports.BlockSize > 0 && c.Ports.MaxBlocks > 0 && last > 65535 {
+			warnings = append(warnings, fmt.Sprintf("ports range ends at %d, beyond 65535; fewer blocks will be available", last))
+		}
+	} else if usesPorts {
+		warnings = append(warnings, "{port} template variables are used but ports.enabled is false")
+	}
+
+	warnings = append(warnings, c.Keys.validate()...)
+
+	return warnings
+}
+
+// keyScope is the set of bindings active on one screen.
+type keyScope struct {
+	name     string // Screen name used in warnings; empty for the list
+	bindings map[string]string
+}
+
+// validate checks key bindings for conflicts on each screen, including
+// sequences shadowed by a key that starts them.
+func (k KeysConfig) validate() []string {
+	var warnings []string
+
+	nav := map[string]string{"up": k.Up, "down": k.Down, "home": k.Home, "end": k.End}
+	withNav := func(bindings map[string]string) map[string]string {
+		for action, keys := range nav {
+			bindings[action] = keys
+		}
+		return bindings
+	}
+	m := k.Modes
+	scopes := []keyScope{
+		{bindings: withNav(map[string]string{
+			"open":       k.Open,
+			"previous":   k.Previous,
+			"new":        k.New,
+			"delete":     k.Delete,
+			"rename":     k.Rename,
+			"move":       k.Move,
+			"lock":       k.Lock,
+			"note":       k.Note,
+			"pin":        k.Pin,
+			"widen":      k.Widen,
+			"filter":     k.Filter,
+			"fetch":      k.Fetch,
+			"detail":     k.Detail,
+			"prune":      k.Prune,
+			"prune_gone": k.PruneGone,
+			"stash":      k.Stash,
+			"sort":       k.Sort,
+			"push":       k.Push,
+			"mark":       k.Mark,
+			"branches":   k.Branches,
+			"tree":       k.Tree,
+			"collapse":   k.Collapse,
+			"expand":     k.Expand,
+			"palette":    k.Palette,
+			"help":       k.Help,
+			"quit":       k.Quit,
+		})},
+		{name: "confirm prompts", bindings: map[string]string{
+			"yes":            m.Confirm.Yes,
+			"no":             m.Confirm.No,
+			"include_pinned": m.Confirm.IncludePinned,
+		}},
+		{name: "pickers", bindings: withNav(map[string]string{
+			"select": m.Picker.Select,
+			"cancel": m.Picker.Cancel,
+		})},
+		{name: "the remote clash prompt", bindings: map[string]string{
+			"reuse":    m.Clash.Reuse,
+			"new_name": m.Clash.NewName,
+			"cancel":   m.Picker.Cancel,
+		}},
+		{name: "the stash view", bindings: withNav(map[string]string{
+			"pop":      m.Stash.Pop,
+			"apply":    m.Stash.Apply,
+			"apply_to": m.Stash.ApplyTo,
+			"branch":   m.Stash.Branch,
+			"drop":     m.Stash.Drop,
+		})},
+		{name: "the branches view", bindings: withNav(map[string]string{
+			"create":   m.Branches.Create,
+			"delete":   m.Branches.Delete,
+			"prune":    m.Branches.Prune,
+			"rename":   m.Branches.Rename,
+			"upstream": m.Branches.Upstream,
+		})},
+	}
+
+	for _, scope := range scopes {
+		where := ""
+		if scope.name != "" {
+			where = " in " + scope.name
+		}
+
+		// Build reverse map: key -> action(s)
+		keyToActions := make(map[string][]string)
+		for action, keys := range scope.bindings {
+			for _, key := range splitKeys(keys) {
 				keyToActions[key] = append(keyToActions[key], action)
 			}
 		}
+
+		for _, key := range slices.Sorted(maps.Keys(keyToActions)) {
+			actions := keyToActions[key]
+			slices.Sort(actions)
+			if len(actions) > 1 {
+				warnings = append(warnings, fmt.Sprintf("Key '%s' is bound to multiple actions%s: %s", key, where, strings.Join(actions, ", ")))
+			}
+			if scope.name == "" && len(key) == 1 && key >= "1" && key <= "9" {
+				warnings = append(warnings, fmt.Sprintf("Key '%s' for %s is reserved for opening pinned worktrees", key, actions[0]))
+			}
+			for _, other := range slices.Sorted(maps.Keys(keyToActions)) {
+				if strings.HasPrefix(other, key+" ") {
+					warnings = append(warnings, fmt.Sprintf("Key '%s' for %s starts the sequence '%s' for %s%s, so it never fires on its own",
+						key, actions[0], other, keyToActions[other][0], where))
+				}
+			}
+		}
 	}
 
-	// Check for conflicts
-	for key, actions := range keyToActions {
-		if len(actions) > 1 {
-			warnings = append(warnings, fmt.Sprintf("Key '%s' is bound to multiple actions: %s", key, strings.Join(actions, ", ")))
+	// A destructive action whose key also confirms it runs on a double press
+	yes := splitKeys(m.Confirm.Yes)
+	destructive := []struct{ action, keys string }{
+		{"delete", k.Delete},
+		{"prune", k.Prune},
+		{"prune_gone", k.PruneGone},
+		{"push", k.Push},
+		{"branches.delete", m.Branches.Delete},
+		{"branches.prune", m.Branches.Prune},
+	}
+	for _, d := range destructive {
+		for _, key := range splitKeys(d.keys) {
+			if slices.Contains(yes, key) {
+				warnings = append(warnings, fmt.Sprintf("Key '%s' both starts %s and confirms it, so pressing it twice skips the prompt", key, d.action))
+			}
 		}
 	}
 
 	return warnings
 }
 
+// splitKeys splits a comma-separated binding into its keys, normalizing the
+// spacing of key sequences.
+func splitKeys(s string) []string {
+	var keys []string
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.Join(strings.Fields(key), " "); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // extractTemplateVars extracts template variables from a string.
 func extractTemplateVars(s string) []string {
 	re := regexp.MustCompile(`\{[^}]+\}`)
@@ -70,6 +70,115 @@ func TestValidate(t *testing.T) {
 			},
 			wantWarning: true,
 		},
+		{
+			name: "built-in theme with color overrides",
+			config: &Config{
+				UI: UIConfig{
+					Theme:  "catppuccin-mocha",
+					Colors: ColorOverrides{Dirty: "#fab387", SelectedBg: "237"},
+				},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "user-defined theme",
+			config: &Config{
+				UI:     UIConfig{Theme: "mine"},
+				Themes: map[string]ThemeConfig{"mine": {Base: "nord", Primary: "#7aa2f7"}},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "invalid theme color",
+			config: &Config{
+				Themes: map[string]ThemeConfig{"mine": {Primary: "blue"}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "invalid color override",
+			config: &Config{
+				UI: UIConfig{Colors: ColorOverrides{Ahead: "#12345"}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "invalid label color",
+			config: &Config{
+				UI: UIConfig{LabelColors: map[string]string{"urgent": "red"}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "digit key reserved for pins",
+			config: &Config{
+				Keys: KeysConfig{Fetch: "1"},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "key sequences",
+			config: &Config{
+				Keys: KeysConfig{Delete: "d d", Home: "g g", Modes: ModeKeysConfig{Stash: StashKeysConfig{Drop: "d  d"}}},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "key shadows a sequence",
+			config: &Config{
+				Keys: KeysConfig{Home: "g", Delete: "g d"},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "conflict within a mode",
+			config: &Config{
+				Keys: KeysConfig{Modes: ModeKeysConfig{Stash: StashKeysConfig{Pop: "p", Drop: "p"}}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "mode key conflicts with navigation",
+			config: &Config{
+				Keys: KeysConfig{Down: "j", Modes: ModeKeysConfig{Branches: BranchesKeysConfig{Delete: "j"}}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "destructive key confirms itself",
+			config: &Config{
+				Keys: KeysConfig{Delete: "y", Modes: ModeKeysConfig{Confirm: ConfirmKeysConfig{Yes: "y"}}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "recent sort order",
+			config: &Config{
+				UI: UIConfig{DefaultSort: "recent"},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "valid columns with width caps",
+			config: &Config{
+				UI: UIConfig{Columns: []string{"branch:30", "dirty", "upstream", "age", "last_commit:40", "merged", "path"}},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "unknown column",
+			config: &Config{
+				UI: UIConfig{Columns: []string{"branch", "author"}},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "invalid column width",
+			config: &Config{
+				UI: UIConfig{Columns: []string{"branch:wide"}},
+			},
+			wantWarning: true,
+		},
 		{
 			name: "valid template variables",
 			config: &Config{
@@ -88,6 +197,62 @@ func TestValidate(t *testing.T) {
 			},
 			wantWarning: true,
 		},
+		{
+			name: "valid worktree_path template",
+			config: &Config{
+				General: GeneralConfig{
+					WorktreePath: "~/worktrees/{repo}/{user}-{date}/{branch}",
+				},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "unknown variable in worktree_path",
+			config: &Config{
+				General: GeneralConfig{
+					WorktreePath: "~/worktrees/{project}/{branch}",
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "worktree_path without branch variable",
+			config: &Config{
+				General: GeneralConfig{
+					WorktreePath: "~/worktrees/{repo}",
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "invalid branch pattern",
+			config: &Config{
+				Branch: BranchConfig{
+					Pattern: "feat/(",
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "branch template without fields",
+			config: &Config{
+				Branch: BranchConfig{
+					Templates: []string{"feat/static"},
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "valid branch settings",
+			config: &Config{
+				Branch: BranchConfig{
+					Prefixes:  []string{"feat/", "fix/"},
+					Templates: []string{"feat/{ticket}-{slug}"},
+					Pattern:   "^(feat|fix)/",
+				},
+			},
+			wantWarning: false,
+		},
 		{
 			name: "path traversal in worktree_dir",
 			config: &Config{
@@ -116,6 +281,105 @@ func TestValidate(t *testing.T) {
 			},
 			wantWarning: false,
 		},
+		{
+			name: "valid sparse profiles",
+			config: &Config{
+				Worktree: WorktreeConfig{
+					SparseProfiles: []SparseProfile{
+						{Name: "api", Paths: []string{"services/api", "libs/shared"}},
+					},
+				},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "sparse profile with glob path",
+			config: &Config{
+				Worktree: WorktreeConfig{
+					SparseProfiles: []SparseProfile{{Name: "api", Paths: []string{"services/*"}}},
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "duplicate sparse profile",
+			config: &Config{
+				Worktree: WorktreeConfig{
+					SparseProfiles: []SparseProfile{
+						{Name: "api", Paths: []string{"a"}},
+						{Name: "api", Paths: []string{"b"}},
+					},
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "valid copy modes",
+			config: &Config{
+				Worktree: WorktreeConfig{
+					CopyPatterns: []string{".env*", "**/node_modules"},
+					CopyMode:     "auto",
+					CopyModes:    map[string]string{"**/node_modules": "symlink"},
+				},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "invalid copy_mode",
+			config: &Config{
+				Worktree: WorktreeConfig{CopyMode: "clone"},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "copy_modes key without matching pattern",
+			config: &Config{
+				Worktree: WorktreeConfig{
+					CopyPatterns: []string{".env*"},
+					CopyModes:    map[string]string{"node_modules": "hardlink"},
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "submodule_reference without init_submodules",
+			config: &Config{
+				Worktree: WorktreeConfig{SubmoduleReference: true},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "port variables with ports enabled",
+			config: &Config{
+				Open:  OpenConfig{Command: "PORT={port} API={port_1} tmux new-window -c {path}"},
+				Ports: PortsConfig{Enabled: true, Base: 3000, BlockSize: 10, MaxBlocks: 100},
+			},
+			wantWarning: false,
+		},
+		{
+			name: "port variable beyond block size",
+			config: &Config{
+				Open:  OpenConfig{Command: "serve --port {port_10}"},
+				Ports: PortsConfig{Enabled: true, Base: 3000, BlockSize: 10, MaxBlocks: 100},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "port variable with ports disabled",
+			config: &Config{
+				Layouts: []LayoutConfig{
+					{Name: "dev", Panes: []PaneConfig{{Command: "npm run dev -- --port {port}"}}},
+				},
+			},
+			wantWarning: true,
+		},
+		{
+			name: "port range beyond 65535",
+			config: &Config{
+				Ports: PortsConfig{Enabled: true, Base: 60000, BlockSize: 100, MaxBlocks: 100},
+			},
+			wantWarning: true,
+		},
 		{
 			name: "negative split_from",
 			config: &Config{
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// BuiltinThemes are the named palettes available as ui.theme besides
+// auto, dark and light.
+var BuiltinThemes = map[string]ThemeConfig{
+	"catppuccin-mocha": {
+		Primary: "#89b4fa", Secondary: "#585b70", Success: "#a6e3a1", Warning: "#f9e2af", Danger: "#f38ba8",
+		Muted: "#7f849c", Highlight: "#89dceb", Text: "#cdd6f4", Purple: "#cba6f7", Background: "#1e1e2e",
+	},
+	"catppuccin-latte": {
+		Primary: "#1e66f5", Secondary: "#acb0be", Success: "#40a02b", Warning: "#df8e1d", Danger: "#d20f39",
+		Muted: "#8c8fa1", Highlight: "#04a5e5", Text: "#4c4f69", Purple: "#8839ef", Background: "#eff1f5",
+	},
+	"gruvbox-dark": {
+		Primary: "#83a598", Secondary: "#665c54", Success: "#b8bb26", Warning: "#fabd2f", Danger: "#fb4934",
+		Muted: "#928374", Highlight: "#8ec07c", Text: "#ebdbb2", Purple: "#d3869b", Background: "#282828",
+	},
+	"gruvbox-light": {
+		Primary: "#076678", Secondary: "#bdae93", Success: "#79740e", Warning: "#b57614", Danger: "#9d0006",
+		Muted: "#928374", Highlight: "#427b58", Text: "#3c3836", Purple: "#8f3f71", Background: "#fbf1c7",
+	},
+	"nord": {
+		Primary: "#81a1c1", Secondary: "#4c566a", Success: "#a3be8c", Warning: "#ebcb8b", Danger: "#bf616a",
+		Muted: "#616e88", Highlight: "#88c0d0", Text: "#d8dee9", Purple: "#b48ead", Background: "#2e3440",
+	},
+	"dracula": {
+		Primary: "#bd93f9", Secondary: "#44475a", Success: "#50fa7b", Warning: "#f1fa8c", Danger: "#ff5555",
+		Muted: "#6272a4", Highlight: "#8be9fd", Text: "#f8f8f2", Purple: "#ff79c6", Background: "#282a36",
+	},
+}
+
+// ThemeNames returns every name ui.theme accepts for this config, sorted:
+// the built-in modes and palettes, then user-defined themes.
+func (c *Config) ThemeNames() []string {
+	names := []string{"auto", "dark", "light"}
+	names = append(names, slices.Sorted(maps.Keys(BuiltinThemes))...)
+	for _, name := range slices.Sorted(maps.Keys(c.Themes)) {
+		if _, ok := BuiltinThemes[name]; !ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hexColorPattern matches #rgb and #rrggbb.
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// isValidColor reports whether s is a hex color or an ANSI 256 color index.
+func isValidColor(s string) bool {
+	if hexColorPattern.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateThemes checks ui.theme, ui.colors and the [themes.*] tables.
+func (c *Config) validateThemes() []string {
+	var warnings []string
+	checkColor := func(field, value string) {
+		if value != "" && !isValidColor(value) {
+			warnings = append(warnings, fmt.Sprintf("Invalid color for %s: %s (expected #rgb, #rrggbb, or 0-255)", field, value))
+		}
+	}
+
+	if c.UI.Theme != "" && !slices.Contains(c.ThemeNames(), c.UI.Theme) {
+		warnings = append(warnings, fmt.Sprintf("Invalid value for ui.theme: %s (expected auto, dark, light, a built-in theme, or a [themes.*] name)", c.UI.Theme))
+	}
+
+	colors := c.UI.Colors
+	for _, f := range []struct{ name, value string }{
+		{"dirty", colors.Dirty}, {"ahead", colors.Ahead}, {"behind", colors.Behind},
+		{"danger", colors.Danger}, {"selected", colors.Selected}, {"selected_bg", colors.SelectedBg},
+	} {
+		checkColor("ui.colors."+f.name, f.value)
+	}
+	for _, label := range slices.Sorted(maps.Keys(c.UI.LabelColors)) {
+		checkColor("ui.label_colors."+label, c.UI.LabelColors[label])
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Themes)) {
+		theme := c.Themes[name]
+		prefix := "themes." + name + "."
+		if theme.Base != "" && theme.Base != "auto" && theme.Base != "dark" && theme.Base != "light" {
+			if _, ok := BuiltinThemes[theme.Base]; !ok {
+				warnings = append(warnings, fmt.Sprintf("Invalid value for %sbase: %s (expected auto, dark, light, or a built-in theme)", prefix, theme.Base))
+			}
+		}
+		for _, f := range theme.fields() {
+			checkColor(prefix+f.name, *f.value)
+		}
+	}
+	return warnings
+}
+
+// themeField names one palette color of a ThemeConfig.
+type themeField struct {
+	name  string
+	value *string
+}
+
+// fields lists the palette colors in config order.
+func (t *ThemeConfig) fields() []themeField {
+	return []themeField{
+		{"primary", &t.Primary}, {"secondary", &t.Secondary}, {"success", &t.Success},
+		{"warning", &t.Warning}, {"danger", &t.Danger}, {"muted", &t.Muted},
+		{"highlight", &t.Highlight}, {"text", &t.Text}, {"purple", &t.Purple},
+		{"background", &t.Background},
+	}
+}
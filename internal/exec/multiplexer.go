@@ -40,6 +40,9 @@ type MultiplexerBackend interface {
 	// CloseWindow closes a window/tab by ID.
 	CloseWindow(windowID string) error
 
+	// RenameWindow renames a window/tab by ID.
+	RenameWindow(windowID, name string) error
+
 	// ApplyNamedLayout applies a named layout with multiple panes.
 	ApplyNamedLayout(layout *config.LayoutConfig, wt *git.Worktree, repo *git.Repo, cfg *config.Config) error
 }
@@ -134,6 +137,11 @@ func (t *tmuxBackend) CloseWindow(windowID string) error {
 	return cmd.Run()
 }
 
+func (t *tmuxBackend) RenameWindow(windowID, name string) error {
+	cmd := osExec.Command("tmux", "rename-window", "-t", windowID, name)
+	return cmd.Run()
+}
+
 func (t *tmuxBackend) ApplyNamedLayout(layout *config.LayoutConfig, wt *git.Worktree, repo *git.Repo, cfg *config.Config) error {
 	if len(layout.Panes) == 0 {
 		return nil
@@ -294,6 +302,53 @@ func (z *zellijBackend) CloseWindow(tabIndex string) error {
 	return closeCmd.Run()
 }
 
+func (z *zellijBackend) RenameWindow(tabIndex, name string) error {
+	// rename-tab only acts on the focused tab, so focus the target and then
+	// return to the tab the user was on
+	focused := z.focusedTab()
+	goCmd := osExec.Command("zellij", "action", "go-to-tab", tabIndex)
+	if err := goCmd.Run(); err != nil {
+		return err
+	}
+	renameCmd := osExec.Command("zellij", "action", "rename-tab", name)
+	renameErr := renameCmd.Run()
+	if focused != "" && focused != tabIndex {
+		backCmd := osExec.Command("zellij", "action", "go-to-tab", focused)
+		if err := backCmd.Run(); err != nil && renameErr == nil {
+			return err
+		}
+	}
+	return renameErr
+}
+
+// focusedTab returns the 1-based index of the focused tab, or "" if it
+// can't be determined.
+func (z *zellijBackend) focusedTab() string {
+	cmd := osExec.Command("zellij", "action", "dump-layout")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return parseFocusedTab(string(output))
+}
+
+// parseFocusedTab finds the focused tab in zellij's dump-layout output.
+// Only named tabs are counted; swap layout entries are unnamed.
+func parseFocusedTab(layout string) string {
+	index := 0
+	for _, line := range strings.Split(layout, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "tab name=") {
+			continue
+		}
+		index++
+		if strings.Contains(line, "focus=true") {
+			return fmt.Sprintf("%d", index)
+		}
+	}
+	return ""
+}
+
 func (z *zellijBackend) ApplyNamedLayout(layout *config.LayoutConfig, wt *git.Worktree, repo *git.Repo, cfg *config.Config) error {
 	if len(layout.Panes) == 0 {
 		return nil
@@ -355,6 +410,7 @@ func (n *noneBackend) FindWindowByName(string) string     { return "" }
 func (n *noneBackend) SwitchToWindow(string) error        { return nil }
 func (n *noneBackend) FindWindowsForPath(string) []string { return nil }
 func (n *noneBackend) CloseWindow(string) error           { return nil }
+func (n *noneBackend) RenameWindow(string, string) error  { return nil }
 func (n *noneBackend) ApplyNamedLayout(*config.LayoutConfig, *git.Worktree, *git.Repo, *config.Config) error {
 	return nil
 }
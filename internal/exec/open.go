@@ -7,10 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/henri123lemoine/grove/internal/config"
 	"github.com/henri123lemoine/grove/internal/git"
+	"github.com/henri123lemoine/grove/internal/state"
 )
 
 // Open executes the open command for a worktree.
@@ -162,12 +164,35 @@ func expandTemplate(command string, wt *git.Worktree, repo *git.Repo, cfg *confi
 		{"{branch_short}", shellQuote(branchShort)},
 		{"{repo}", shellQuote(repoName)},
 		{"{window_name}", shellQuote(windowName)},
+		{"{note}", shellQuote(wt.Note)},
 	}
 
 	for _, repl := range replacements {
 		result = strings.ReplaceAll(result, repl.key, repl.value)
 	}
 
+	return expandPorts(result, wt, repo, cfg)
+}
+
+// expandPorts replaces {port} and {port_N} with the worktree's port block.
+// The block is allocated on demand so worktrees created before ports were
+// enabled, or opened while post-create setup is still running, get one too.
+// Unknown or out-of-range variables are left untouched for the config
+// validator to report.
+func expandPorts(command string, wt *git.Worktree, repo *git.Repo, cfg *config.Config) string {
+	if cfg == nil || !cfg.Ports.Enabled || !strings.Contains(command, "{port") {
+		return command
+	}
+	live, _ := git.LivePaths()
+	block, err := state.AllocatePorts(repo.MainWorktreeRoot, wt.Path, live, cfg.Ports.Base, cfg.Ports.BlockSize, cfg.Ports.MaxBlocks)
+	if err != nil {
+		return command
+	}
+
+	result := strings.ReplaceAll(command, "{port}", strconv.Itoa(block.Port(0)))
+	for i := 1; i < block.Size; i++ {
+		result = strings.ReplaceAll(result, fmt.Sprintf("{port_%d}", i), strconv.Itoa(block.Port(i)))
+	}
 	return result
 }
 
@@ -252,6 +277,20 @@ func CloseWindow(windowID string) error {
 	return Backend().CloseWindow(windowID)
 }
 
+// RenameWindow renames a window/tab by ID.
+func RenameWindow(windowID, name string) error {
+	return Backend().RenameWindow(windowID, name)
+}
+
+// WindowNameFor returns the window/tab name grove uses for a worktree,
+// following the configured window_name_style.
+func WindowNameFor(cfg *config.Config, wt *git.Worktree) string {
+	if cfg != nil && cfg.Open.WindowNameStyle == "full" {
+		return wt.Branch
+	}
+	return wt.BranchShort()
+}
+
 // InMultiplexer returns true if we're running inside a supported multiplexer.
 func InMultiplexer() bool {
 	return Backend().Name() != ""
@@ -11,6 +11,7 @@ func TestExpandTemplate(t *testing.T) {
 	wt := &git.Worktree{
 		Path:   "/home/user/project/.worktrees/feature-auth",
 		Branch: "feature/auth",
+		Note:   "waiting on review",
 	}
 	repo := &git.Repo{
 		Root: "/home/user/project",
@@ -47,6 +48,11 @@ func TestExpandTemplate(t *testing.T) {
 			template: "tmux new-window -n {window_name}",
 			expected: "tmux new-window -n auth",
 		},
+		{
+			name:     "note variable",
+			template: "echo {note}",
+			expected: "echo 'waiting on review'",
+		},
 		{
 			name:     "multiple variables",
 			template: "tmux new-window -n {branch_short} -c {path}",
@@ -88,6 +94,28 @@ func TestExpandTemplateFullWindowName(t *testing.T) {
 	}
 }
 
+func TestExpandTemplatePorts(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	wt := &git.Worktree{Path: dir, Branch: "feature/auth"}
+	repo := &git.Repo{Root: dir, MainWorktreeRoot: dir}
+	cfg := config.DefaultConfig()
+
+	// Disabled: variables are left alone
+	if got := expandTemplate("serve --port {port}", wt, repo, cfg); got != "serve --port {port}" {
+		t.Errorf("expandTemplate() with ports disabled = %q", got)
+	}
+
+	cfg.Ports.Enabled = true
+	cfg.Ports.Base = 4100
+	cfg.Ports.BlockSize = 3
+	got := expandTemplate("PORT={port} API={port_1} DB={port_2} X={port_3}", wt, repo, cfg)
+	want := "PORT=4100 API=4101 DB=4102 X={port_3}"
+	if got != want {
+		t.Errorf("expandTemplate() = %q, want %q", got, want)
+	}
+}
+
 func TestExpandTemplateZellij(t *testing.T) {
 	wt := &git.Worktree{
 		Path:   "/home/user/project/.worktrees/fix-bug",
@@ -232,6 +260,7 @@ type mockBackend struct {
 	allWindowsForPath map[string][]string
 	switchCalls       []string
 	closeCalls        []string
+	renameCalls       []string
 	layoutCalls       int
 }
 
@@ -272,6 +301,11 @@ func (m *mockBackend) CloseWindow(windowID string) error {
 	return nil
 }
 
+func (m *mockBackend) RenameWindow(windowID, name string) error {
+	m.renameCalls = append(m.renameCalls, windowID+"="+name)
+	return nil
+}
+
 func (m *mockBackend) ApplyNamedLayout(*config.LayoutConfig, *git.Worktree, *git.Repo, *config.Config) error {
 	m.layoutCalls++
 	return nil
@@ -395,6 +429,56 @@ func TestCloseWindow_WithMock(t *testing.T) {
 	}
 }
 
+func TestRenameWindow_WithMock(t *testing.T) {
+	mock := newMockBackend()
+	cleanup := setMockBackend(mock)
+	defer cleanup()
+
+	wt := &git.Worktree{Path: "/some/path", Branch: "feature/login"}
+	cfg := config.DefaultConfig()
+
+	if err := RenameWindow("@1", WindowNameFor(cfg, wt)); err != nil {
+		t.Errorf("RenameWindow failed: %v", err)
+	}
+	cfg.Open.WindowNameStyle = "full"
+	if err := RenameWindow("@2", WindowNameFor(cfg, wt)); err != nil {
+		t.Errorf("RenameWindow failed: %v", err)
+	}
+
+	want := []string{"@1=login", "@2=feature/login"}
+	if len(mock.renameCalls) != len(want) {
+		t.Fatalf("rename calls = %v, want %v", mock.renameCalls, want)
+	}
+	for i := range want {
+		if mock.renameCalls[i] != want[i] {
+			t.Errorf("rename call %d = %q, want %q", i, mock.renameCalls[i], want[i])
+		}
+	}
+}
+
+func TestParseFocusedTab(t *testing.T) {
+	layout := `layout {
+    cwd "/repo"
+    tab name="main" hide_floating_panes=true {
+        pane
+    }
+    tab name="feature" focus=true hide_floating_panes=true {
+        pane
+    }
+    swap_tiled_layout name="vertical" {
+        tab max_panes=5 {
+            pane split_direction="vertical"
+        }
+    }
+}`
+	if got := parseFocusedTab(layout); got != "2" {
+		t.Errorf("parseFocusedTab() = %q, want %q", got, "2")
+	}
+	if got := parseFocusedTab(""); got != "" {
+		t.Errorf("parseFocusedTab(\"\") = %q, want empty", got)
+	}
+}
+
 func TestInMultiplexer_WithMock(t *testing.T) {
 	// Test with active multiplexer
 	mock := newMockBackend()
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MovedWorktree is a worktree directory that was moved without telling git,
+// leaving its registered path prunable.
+type MovedWorktree struct {
+	OldPath string // Path git still has on record
+	NewPath string // Where the worktree is now
+}
+
+// FindMovedWorktrees searches dirs (up to maxDepth levels deep) for the new
+// location of prunable worktrees. A worktree's .git file names its admin
+// directory, whose gitdir file still records the old path.
+func FindMovedWorktrees(worktrees []Worktree, dirs []string, maxDepth int) []MovedWorktree {
+	missing := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.IsPrunable {
+			missing[filepath.Clean(wt.Path)] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var moved []MovedWorktree
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			rel, _ := filepath.Rel(dir, path)
+			if rel != "." && strings.Count(rel, string(filepath.Separator))+1 > maxDepth {
+				return filepath.SkipDir
+			}
+
+			info, err := os.Lstat(filepath.Join(path, ".git"))
+			if err != nil {
+				return nil
+			}
+			if info.Mode().IsRegular() {
+				if old := registeredPath(path); missing[old] && !seen[old] {
+					seen[old] = true
+					moved = append(moved, MovedWorktree{OldPath: old, NewPath: path})
+				}
+			}
+			// Don't descend into checkouts
+			if rel != "." {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+	return moved
+}
+
+// registeredPath returns the path git has on record for the worktree at
+// path, or "" if path isn't a linked worktree.
+func registeredPath(path string) string {
+	content, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return ""
+	}
+	adminDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(adminDir) {
+		adminDir = filepath.Join(path, adminDir)
+	}
+
+	gitdir, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(filepath.Clean(strings.TrimSpace(string(gitdir))))
+}
+
+// RepairWorktrees runs git worktree repair for worktrees at their current
+// paths, updating the links between them and the repository.
+func RepairWorktrees(paths []string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"worktree", "repair", "--"}, paths...)
+	if _, err := runGitInDir(repo.MainWorktreeRoot, args...); err != nil {
+		return fmt.Errorf("failed to repair worktrees: %w", err)
+	}
+	_, _ = ListAndCache()
+	return nil
+}
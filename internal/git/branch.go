@@ -1,6 +1,8 @@
 package git
 
 import (
+	"fmt"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -14,6 +16,22 @@ type Branch struct {
 	IsTag      bool // This is a tag, not a branch
 }
 
+// BranchInfo describes a local branch for the branches view.
+type BranchInfo struct {
+	Name           string
+	LastCommitTime string // Relative committer date, e.g. "3 weeks ago"
+	Upstream       string // Upstream ref, e.g. "origin/feature" (empty if none)
+	UpstreamGone   bool   // Upstream is configured but no longer exists on the remote
+	IsMerged       bool   // Merged into the default branch
+	IsSquashMerged bool   // Changes landed on the default branch via squash or rebase
+}
+
+// IsStale reports whether the branch can be pruned without losing work:
+// it is merged (normally or by squash) into the default branch.
+func (b BranchInfo) IsStale() bool {
+	return b.IsMerged || b.IsSquashMerged
+}
+
 // ListBranches returns all local branches.
 func ListBranches() ([]Branch, error) {
 	// Use --list to get branches with current indicator
@@ -40,6 +58,130 @@ func ListBranches() ([]Branch, error) {
 	return branches, nil
 }
 
+// ResolveDetachTarget reports whether rev names a commit that should be
+// checked out detached rather than used as a new branch name: a tag, a
+// commit SHA (7+ hex chars), or a revision expression like HEAD~3.
+// Local and remote branch names are never detach targets. On success it
+// returns the commit's ShortHash.
+func ResolveDetachTarget(rev string) (string, bool) {
+	if rev == "" || BranchExists(rev) || refExists("refs/remotes/"+rev) {
+		return "", false
+	}
+
+	sha, err := runGit("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", false
+	}
+	sha = strings.TrimSpace(sha)
+
+	isTag := refExists("refs/tags/" + rev)
+	isRevExpr := rev == "HEAD" || strings.ContainsAny(rev, "~^@:")
+	if !isTag && !isRevExpr && !isHexSHA(rev) {
+		return "", false
+	}
+	return ShortHash(sha), true
+}
+
+// ResolveRemoteBranch maps name to a remote-tracking branch and the local
+// branch it should be checked out as. "origin/feature-x" is used as given;
+// a bare "feature-x" matches <primaryRemote>/feature-x. Names that already
+// exist as local branches are not resolved here.
+func ResolveRemoteBranch(name, primaryRemote string) (remoteRef, localName string, ok bool) {
+	if name == "" || BranchExists(name) {
+		return "", "", false
+	}
+
+	if refExists("refs/remotes/" + name) {
+		output, err := runGit("remote")
+		if err == nil {
+			for _, remote := range strings.Fields(output) {
+				if local, found := strings.CutPrefix(name, remote+"/"); found && local != "" {
+					return name, local, true
+				}
+			}
+		}
+	}
+
+	if primaryRemote != "" && refExists("refs/remotes/"+primaryRemote+"/"+name) {
+		return primaryRemote + "/" + name, name, true
+	}
+
+	return "", "", false
+}
+
+// ValidateBranchName checks name against the rules git check-ref-format
+// applies to branch names, without running git. The error describes the
+// first problem found.
+func ValidateBranchName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("branch name is empty")
+	case name == "@":
+		return fmt.Errorf("branch name cannot be '@'")
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("branch name cannot start with '-'")
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return fmt.Errorf("branch name cannot start or end with '/'")
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("branch name cannot end with '.'")
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("branch name cannot contain '..'")
+	case strings.Contains(name, "//"):
+		return fmt.Errorf("branch name cannot contain '//'")
+	case strings.Contains(name, "@{"):
+		return fmt.Errorf("branch name cannot contain '@{'")
+	}
+
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f {
+			return fmt.Errorf("branch name cannot contain control characters")
+		}
+		if strings.ContainsRune(" ~^:?*[\\", c) {
+			return fmt.Errorf("branch name cannot contain %q", c)
+		}
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return fmt.Errorf("branch name components cannot start with '.'")
+		}
+		if strings.HasSuffix(part, ".lock") {
+			return fmt.Errorf("branch name components cannot end with '.lock'")
+		}
+	}
+
+	return nil
+}
+
+// refExists checks whether a fully qualified ref exists.
+func refExists(ref string) bool {
+	_, err := runGit("show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// isHexSHA reports whether s looks like an abbreviated or full commit hash.
+// ShortHash abbreviates a commit hash the way detached worktrees are shown
+// and named. The length is fixed rather than taken from rev-parse --short,
+// which grows with the repository, so names and paths always agree.
+func ShortHash(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func isHexSHA(s string) bool {
+	if len(s) < 7 || len(s) > 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
 // ListRemoteBranches returns all remote branches.
 func ListRemoteBranches() ([]Branch, error) {
 	output, err := runGit("branch", "-r", "--format=%(refname:short)")
@@ -237,3 +379,103 @@ func GetWorktreeBranches() (map[string]bool, error) {
 
 	return result, nil
 }
+
+// branchDetailsFormat is the for-each-ref format parsed by parseBranchDetails.
+const branchDetailsFormat = "--format=%(refname:short)%00%(committerdate:relative)%00%(upstream:short)%00%(upstream:track)"
+
+// ListBranchesWithoutWorktrees returns local branches that aren't checked out
+// in any worktree, with last-commit age, upstream and merge status.
+// Sorted by most recent commit first.
+func ListBranchesWithoutWorktrees(defaultBranch string) ([]BranchInfo, error) {
+	output, err := runGit("for-each-ref", "--sort=-committerdate", branchDetailsFormat, "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+
+	worktreeBranches, err := GetWorktreeBranches()
+	if err != nil {
+		worktreeBranches = make(map[string]bool)
+	}
+
+	var merged map[string]bool
+	if defaultBranch != "" {
+		// Ignore errors - merge status is informational
+		merged, _ = GetMergedBranches(defaultBranch)
+	}
+
+	var branches []BranchInfo
+	var unmerged []string
+	for _, b := range parseBranchDetails(output) {
+		if worktreeBranches[b.Name] {
+			continue
+		}
+		if defaultBranch != "" && b.Name != defaultBranch {
+			b.IsMerged = merged[b.Name]
+			if !b.IsMerged {
+				unmerged = append(unmerged, b.Name)
+			}
+		}
+		branches = append(branches, b)
+	}
+
+	if len(unmerged) > 0 {
+		squash := NewSquashChecker(defaultBranch, unmerged...)
+		for i := range branches {
+			if slices.Contains(unmerged, branches[i].Name) {
+				branches[i].IsSquashMerged, _ = squash.IsSquashMerged(branches[i].Name)
+			}
+		}
+	}
+
+	return branches, nil
+}
+
+// parseBranchDetails parses NUL-separated for-each-ref output of
+// name, relative date, upstream and upstream tracking state.
+func parseBranchDetails(output string) []BranchInfo {
+	var branches []BranchInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\x00")
+		if len(parts) < 4 {
+			continue
+		}
+		branches = append(branches, BranchInfo{
+			Name:           parts[0],
+			LastCommitTime: parts[1],
+			Upstream:       parts[2],
+			UpstreamGone:   parts[2] != "" && parts[3] == "[gone]",
+		})
+	}
+	return branches
+}
+
+// GetGoneBranches returns the set of local branches whose upstream is
+// configured but no longer exists (typically deleted after a PR merged).
+// Only as fresh as the last fetch with --prune.
+func GetGoneBranches() (map[string]bool, error) {
+	output, err := runGit("for-each-ref", branchDetailsFormat, "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+
+	gone := make(map[string]bool)
+	for _, b := range parseBranchDetails(output) {
+		if b.UpstreamGone {
+			gone[b.Name] = true
+		}
+	}
+	return gone, nil
+}
+
+// SetBranchUpstream sets the upstream of a local branch (e.g. "origin/feature").
+func SetBranchUpstream(branch, upstream string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+	_, err = runGitInDir(repo.MainWorktreeRoot, "branch", "--set-upstream-to="+upstream, branch)
+	return err
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BareDirName is the directory holding the repository in a worktree-first
+// layout. The directory above it acts as the main worktree root.
+const BareDirName = ".bare"
+
+// CloneDir returns the directory "git clone" would pick for source, e.g.
+// "grove" for "git@github.com:henri123lemoine/grove.git".
+func CloneDir(source string) string {
+	name := strings.TrimRight(source, "/\\")
+	name = strings.TrimSuffix(name, ".git")
+	name = strings.TrimRight(name, "/\\")
+	if i := strings.LastIndexAny(name, "/\\:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// CloneBare clones source into dir using the worktree-first layout: the
+// repository lives in dir/.bare and dir/.git points at it, so git commands
+// work from dir and worktrees can sit next to .bare. Unlike a plain bare
+// clone, remote branches are fetched as origin/* and only the default
+// branch is kept as a local branch, tracking its remote. Returns the
+// default branch. On failure nothing is left behind in dir.
+func CloneBare(source, dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("destination %s already exists and is not empty", dir)
+	}
+	created := err != nil
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	branch, err := cloneBare(source, dir)
+	if err != nil {
+		if created {
+			_ = os.RemoveAll(dir)
+		} else {
+			_ = os.RemoveAll(filepath.Join(dir, BareDirName))
+			_ = os.Remove(filepath.Join(dir, ".git"))
+		}
+		return "", err
+	}
+	return branch, nil
+}
+
+// cloneBare does the work of CloneBare in an existing, empty dir.
+func cloneBare(source, dir string) (string, error) {
+	bareDir := filepath.Join(dir, BareDirName)
+	if _, err := runGitInDir(dir, "clone", "--bare", source, bareDir); err != nil {
+		return "", fmt.Errorf("failed to clone: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: ./"+BareDirName+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write .git file: %w", err)
+	}
+
+	// Bare clones map remote branches straight onto local ones and set no
+	// fetch refspec, so fetches would never update origin/*
+	if _, err := runGitInDir(bareDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return "", fmt.Errorf("failed to configure fetch refspec: %w", err)
+	}
+	if _, err := runGitInDir(bareDir, "fetch", "origin"); err != nil {
+		return "", fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	output, err := runGitInDir(bareDir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to detect default branch: %w", err)
+	}
+	branch := strings.TrimSpace(output)
+	_, _ = runGitInDir(bareDir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/"+branch)
+
+	// Drop the local copies of every other branch; they stay available as
+	// origin/* and get a local branch when a worktree is created for them
+	output, err = runGitInDir(bareDir, "for-each-ref", "--format=%(refname)", "refs/heads/")
+	if err != nil {
+		return "", fmt.Errorf("failed to list branches: %w", err)
+	}
+	for _, ref := range strings.Fields(output) {
+		if ref != "refs/heads/"+branch {
+			_, _ = runGitInDir(bareDir, "update-ref", "-d", ref)
+		}
+	}
+	if _, err := runGitInDir(bareDir, "branch", "--set-upstream-to=origin/"+branch, branch); err != nil {
+		return "", fmt.Errorf("failed to set upstream: %w", err)
+	}
+
+	return branch, nil
+}
@@ -0,0 +1,16 @@
+//go:build darwin
+
+package git
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile makes dst a copy-on-write clone of src with clonefile(2) (APFS).
+// clonefile refuses to overwrite, so an existing dst is removed first.
+func cloneFile(src, dst string, _ os.FileMode) error {
+	_ = os.Remove(dst)
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
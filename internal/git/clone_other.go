@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package git
+
+import (
+	"errors"
+	"os"
+)
+
+var errReflinkUnsupported = errors.New("copy-on-write clones are not supported on this platform")
+
+// cloneFile is unsupported here; copy_mode "auto" falls back to a copy.
+func cloneFile(_, _ string, _ os.FileMode) error {
+	return errReflinkUnsupported
+}
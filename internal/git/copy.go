@@ -0,0 +1,353 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Copy modes for worktree.copy_mode and worktree.copy_modes.
+const (
+	CopyModeCopy     = "copy"     // Byte-by-byte copy
+	CopyModeReflink  = "reflink"  // Copy-on-write clone; fails where unsupported
+	CopyModeAuto     = "auto"     // Copy-on-write clone, falling back to a copy
+	CopyModeHardlink = "hardlink" // Hard link each file, falling back to a copy across filesystems
+	CopyModeSymlink  = "symlink"  // Symlink the matched path back to the source worktree
+)
+
+// CopyProgress reports how far CopyFiles has got.
+type CopyProgress struct {
+	Path  string // Last path copied, relative to the source worktree
+	Files int    // Files copied, cloned or linked so far
+	Bytes int64  // Total size of those files
+}
+
+// CopyOptions controls CopyFiles.
+type CopyOptions struct {
+	// Patterns select what to copy. ** matches any number of directories.
+	Patterns []string
+
+	// Ignores are matched against relative paths and base names.
+	Ignores []string
+
+	// Mode is the default copy mode (empty = CopyModeCopy).
+	Mode string
+
+	// Modes overrides Mode for individual patterns, keyed by pattern.
+	Modes map[string]string
+
+	// Progress, when set, is called after every file.
+	Progress func(CopyProgress)
+}
+
+type copier struct {
+	opts     CopyOptions
+	progress CopyProgress
+}
+
+// CopyFiles copies files matching patterns from source to dest worktree.
+// Matched directories are copied recursively; symlinks inside them are
+// recreated rather than followed.
+func CopyFiles(sourceDir, destDir string, opts CopyOptions) error {
+	// Resolve source directory for path traversal validation
+	resolvedSource := ResolvePath(sourceDir)
+	c := &copier{opts: opts}
+
+	for _, pattern := range opts.Patterns {
+		// Find files matching pattern
+		matches, err := globPattern(sourceDir, pattern, opts.Ignores)
+		if err != nil {
+			continue
+		}
+
+		mode := opts.Mode
+		if m, ok := opts.Modes[pattern]; ok {
+			mode = m
+		}
+		if mode == "" {
+			mode = CopyModeCopy
+		}
+
+		for _, srcPath := range matches {
+			// Validate path is within source directory (prevent path traversal)
+			resolvedSrc := ResolvePath(srcPath)
+			if !isWithinPath(resolvedSource, resolvedSrc) {
+				continue
+			}
+
+			// Check if ignored
+			relPath, _ := filepath.Rel(sourceDir, srcPath)
+			if isIgnored(relPath, opts.Ignores) {
+				continue
+			}
+
+			info, err := os.Stat(srcPath)
+			if err != nil {
+				continue
+			}
+
+			destPath := filepath.Join(destDir, relPath)
+			switch {
+			case mode == CopyModeSymlink:
+				err = c.symlink(srcPath, destPath, relPath, info)
+			case info.IsDir():
+				err = c.copyDir(srcPath, destPath, relPath, mode)
+			default:
+				err = c.copyFile(srcPath, destPath, relPath, mode, info)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to copy %s: %w", relPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// globPattern expands pattern under root. Patterns without ** go through
+// filepath.Glob. For ** patterns the tree is walked, skipping .git, ignored
+// directories and nested repositories or worktrees; a matching directory is
+// returned whole instead of being descended into.
+func globPattern(root, pattern string, ignores []string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(filepath.Join(root, pattern))
+	}
+
+	pattern = filepath.ToSlash(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" || isIgnored(rel, ignores) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(p, ".git")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		if matchDoublestar(pattern, filepath.ToSlash(rel)) {
+			matches = append(matches, p)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// matchDoublestar matches a slash-separated path against a pattern in which
+// a ** segment stands for zero or more directories and every other segment
+// uses path.Match syntax.
+func matchDoublestar(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pat, segs []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pat[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pat[0], segs[0]); err != nil || !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// isIgnored checks if a path (relative to the source worktree) matches any
+// ignore pattern. Patterns are tried against the full path and the base
+// name, so "node_modules" or "*.log" apply at any depth.
+func isIgnored(relPath string, ignores []string) bool {
+	slashPath := filepath.ToSlash(relPath)
+	base := path.Base(slashPath)
+	for _, pattern := range ignores {
+		pattern = filepath.ToSlash(pattern)
+
+		// Handle dir/** patterns (e.g., node_modules/**)
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if slashPath == prefix || base == prefix || strings.HasPrefix(slashPath, prefix+"/") {
+				return true
+			}
+		}
+		if strings.Contains(pattern, "**") && matchDoublestar(pattern, slashPath) {
+			return true
+		}
+
+		if matched, err := path.Match(pattern, slashPath); err == nil && matched {
+			return true
+		}
+		if matched, err := path.Match(pattern, base); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// symlink links dst to the absolute source path. Existing destinations are
+// left alone and reported, since replacing a checked-out path with a link
+// would show up as a change in the new worktree.
+func (c *copier) symlink(src, dst, rel string, info os.FileInfo) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return errors.New("destination already exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Symlink(abs, dst); err != nil {
+		return err
+	}
+	c.report(rel, info.Size())
+	return nil
+}
+
+// copyDir copies a directory recursively.
+func (c *copier) copyDir(src, dst, rel, mode string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, srcInfo.Mode().Perm()); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryRel := filepath.Join(rel, entry.Name())
+		if isIgnored(entryRel, c.opts.Ignores) {
+			continue
+		}
+
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			if err := copySymlink(srcPath, dstPath); err != nil {
+				return err
+			}
+			c.report(entryRel, 0)
+		case entry.IsDir():
+			if err := c.copyDir(srcPath, dstPath, entryRel, mode); err != nil {
+				return err
+			}
+		default:
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := c.copyFile(srcPath, dstPath, entryRel, mode, info); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyFile copies a single file using the given mode.
+func (c *copier) copyFile(src, dst, rel, mode string, info os.FileInfo) error {
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+
+	var err error
+	switch mode {
+	case CopyModeReflink:
+		err = cloneFile(src, dst, info.Mode().Perm())
+	case CopyModeAuto:
+		if err = cloneFile(src, dst, info.Mode().Perm()); err != nil {
+			err = copyFileContents(src, dst)
+		}
+	case CopyModeHardlink:
+		_ = os.Remove(dst)
+		if err = os.Link(src, dst); err != nil {
+			err = copyFileContents(src, dst)
+		}
+	default:
+		err = copyFileContents(src, dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.report(rel, info.Size())
+	return nil
+}
+
+func (c *copier) report(rel string, size int64) {
+	c.progress.Path = rel
+	c.progress.Files++
+	c.progress.Bytes += size
+	if c.opts.Progress != nil {
+		c.opts.Progress(c.progress)
+	}
+}
+
+// copyFileContents copies a file's bytes and permissions.
+func copyFileContents(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srcFile.Close() }()
+
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dstFile.Close() }()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
+
+// copySymlink recreates a symlink with the same target.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(dst)
+	return os.Symlink(target, dst)
+}
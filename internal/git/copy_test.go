@@ -0,0 +1,163 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMatchDoublestar(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"**/node_modules", "node_modules", true},
+		{"**/node_modules", "packages/web/node_modules", true},
+		{"**/node_modules", "packages/web/node_modules/x", false},
+		{"**/.env*", "services/api/.env.local", true},
+		{"apps/**/dist", "apps/dist", true},
+		{"apps/**/dist", "apps/web/dist", true},
+		{"apps/**/dist", "libs/web/dist", false},
+		{"cache/**", "cache", true},
+		{"cache/**", "cache/a/b", true},
+		{"*.log", "a/b.log", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchDoublestar(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchDoublestar(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	tests := []struct {
+		path    string
+		ignores []string
+		want    bool
+	}{
+		{"node_modules", []string{"node_modules/**"}, true},
+		{filepath.Join("pkg", "node_modules"), []string{"node_modules/**"}, true},
+		{filepath.Join("logs", "app.log"), []string{"*.log"}, true},
+		{filepath.Join("a", "b", "cache"), []string{"**/b/cache"}, true},
+		{filepath.Join("a", "c", "cache"), []string{"**/b/cache"}, false},
+		{"src", []string{"*.log"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isIgnored(tt.path, tt.ignores); got != tt.want {
+			t.Errorf("isIgnored(%q, %v) = %v, want %v", tt.path, tt.ignores, got, tt.want)
+		}
+	}
+}
+
+// writeTree creates files (relative path -> content) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCopyFilesDoublestar(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	writeTree(t, src, map[string]string{
+		".env":                           "root",
+		"services/api/.env":              "api",
+		"services/api/node_modules/a.js": "a",
+		"services/api/debug.log":         "log",
+		".worktrees/other/.env":          "other worktree",
+		".worktrees/other/.git":          "gitdir: elsewhere",
+	})
+
+	var last CopyProgress
+	err := CopyFiles(src, dst, CopyOptions{
+		Patterns: []string{"**/.env", "**/node_modules"},
+		Ignores:  []string{"*.log"},
+		Progress: func(p CopyProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("CopyFiles error: %v", err)
+	}
+
+	for rel, want := range map[string]string{
+		".env":                           "root",
+		"services/api/.env":              "api",
+		"services/api/node_modules/a.js": "a",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(rel)))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", rel, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".worktrees")); !os.IsNotExist(err) {
+		t.Error("files from a nested worktree were copied")
+	}
+	if last.Files != 3 {
+		t.Errorf("progress reported %d files, want 3", last.Files)
+	}
+}
+
+func TestCopyFilesModes(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{
+		"deps/lib/index.js": "module.exports = 1",
+		"cache/blob":        "data",
+		"settings.json":     "{}",
+	})
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("index.js", filepath.Join(src, "deps", "lib", "main.js")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := t.TempDir()
+	err := CopyFiles(src, dst, CopyOptions{
+		Patterns: []string{"deps", "cache", "settings.json"},
+		Mode:     CopyModeAuto,
+		Modes:    map[string]string{"deps": CopyModeHardlink, "cache": CopyModeSymlink},
+	})
+	if err != nil {
+		t.Fatalf("CopyFiles error: %v", err)
+	}
+
+	// hardlink: same file on disk
+	srcInfo, _ := os.Stat(filepath.Join(src, "deps", "lib", "index.js"))
+	dstInfo, err := os.Stat(filepath.Join(dst, "deps", "lib", "index.js"))
+	if err != nil || !os.SameFile(srcInfo, dstInfo) {
+		t.Errorf("deps/lib/index.js is not a hard link (err %v)", err)
+	}
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(filepath.Join(dst, "deps", "lib", "main.js")); err != nil || target != "index.js" {
+			t.Errorf("nested symlink = %q, %v; want index.js", target, err)
+		}
+	}
+
+	// symlink: whole directory linked back to the source
+	if runtime.GOOS != "windows" {
+		target, err := os.Readlink(filepath.Join(dst, "cache"))
+		if err != nil || target != filepath.Join(src, "cache") {
+			t.Errorf("cache symlink = %q, %v; want %q", target, err, filepath.Join(src, "cache"))
+		}
+	}
+
+	// auto: independent copy with the same content
+	got, err := os.ReadFile(filepath.Join(dst, "settings.json"))
+	if err != nil || string(got) != "{}" {
+		t.Errorf("settings.json = %q, %v", got, err)
+	}
+	srcInfo, _ = os.Stat(filepath.Join(src, "settings.json"))
+	dstInfo, _ = os.Stat(filepath.Join(dst, "settings.json"))
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Error("auto mode linked settings.json instead of copying it")
+	}
+}
@@ -174,6 +174,102 @@ detached
 	}
 }
 
+func TestParseWorktreeListLocked(t *testing.T) {
+	input := `worktree /path/to/repo
+HEAD abc123def456
+branch refs/heads/main
+
+worktree /path/to/repo/.worktrees/usb
+HEAD def789abc012
+branch refs/heads/usb
+locked on removable drive
+
+worktree /path/to/repo/.worktrees/bare-lock
+HEAD 0123456789ab
+branch refs/heads/bare-lock
+locked
+
+`
+	result := parseWorktreeList(input)
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 worktrees, got %d", len(result))
+	}
+	if result[0].IsLocked {
+		t.Error("main worktree should not be locked")
+	}
+	if !result[1].IsLocked || result[1].LockReason != "on removable drive" {
+		t.Errorf("usb: IsLocked=%v LockReason=%q", result[1].IsLocked, result[1].LockReason)
+	}
+	if !result[2].IsLocked || result[2].LockReason != "" {
+		t.Errorf("bare-lock: IsLocked=%v LockReason=%q", result[2].IsLocked, result[2].LockReason)
+	}
+}
+
+func TestParseWorktreeListPrunable(t *testing.T) {
+	input := `worktree /path/to/repo
+HEAD abc123def456
+branch refs/heads/main
+
+worktree /path/to/old-feature
+HEAD def789abc012
+branch refs/heads/feature
+prunable gitdir file points to non-existent location
+
+`
+	result := parseWorktreeList(input)
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 worktrees, got %d", len(result))
+	}
+	if result[0].IsPrunable {
+		t.Error("main worktree should not be prunable")
+	}
+	if !result[1].IsPrunable || result[1].PrunableReason != "gitdir file points to non-existent location" {
+		t.Errorf("feature: IsPrunable=%v PrunableReason=%q", result[1].IsPrunable, result[1].PrunableReason)
+	}
+}
+
+func TestParseBranchDetails(t *testing.T) {
+	output := "feature\x002 days ago\x00origin/feature\x00[ahead 1]\n" +
+		"stale\x003 weeks ago\x00origin/stale\x00[gone]\n" +
+		"local\x005 minutes ago\x00\x00\n"
+
+	branches := parseBranchDetails(output)
+	if len(branches) != 3 {
+		t.Fatalf("Expected 3 branches, got %d", len(branches))
+	}
+
+	if branches[0].Name != "feature" || branches[0].Upstream != "origin/feature" || branches[0].UpstreamGone {
+		t.Errorf("Unexpected feature branch: %+v", branches[0])
+	}
+	if !branches[1].UpstreamGone {
+		t.Errorf("Expected stale branch upstream to be gone: %+v", branches[1])
+	}
+	if branches[2].Upstream != "" || branches[2].UpstreamGone || branches[2].LastCommitTime != "5 minutes ago" {
+		t.Errorf("Unexpected local branch: %+v", branches[2])
+	}
+}
+
+// TestValidateBranchName tests branch name validation against git's ref rules.
+func TestValidateBranchName(t *testing.T) {
+	valid := []string{"main", "feature/auth", "feat/ABC-123-login", "v1.2", "user@host"}
+	invalid := []string{
+		"", "@", "-x", "/x", "x/", "x.", "a..b", "a//b", "a@{b",
+		"has space", "a~1", "a^", "a:b", "a?", "a*", "a[b", "a\\b", "a\tb",
+		".hidden", "x/.hidden", "x.lock", "x.lock/y",
+	}
+
+	for _, name := range valid {
+		if err := ValidateBranchName(name); err != nil {
+			t.Errorf("ValidateBranchName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range invalid {
+		if err := ValidateBranchName(name); err == nil {
+			t.Errorf("ValidateBranchName(%q) = nil, want error", name)
+		}
+	}
+}
+
 // TestSafetyLevel tests safety level string conversion.
 func TestSafetyLevel(t *testing.T) {
 	tests := []struct {
@@ -359,3 +455,57 @@ func TestGetUniqueCommits(t *testing.T) {
 		t.Logf("  %s: %s", c.Hash, c.Message)
 	}
 }
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	const hash = "c30d1f42724596282a33ea41dc907d42609f9485"
+	tests := []struct {
+		line string
+		want SubmoduleChange
+		ok   bool
+	}{
+		{"1 .M S.MU 160000 160000 160000 " + hash + " " + hash + " vendor/lib", SubmoduleChange{Path: "vendor/lib", Modified: true, Untracked: true}, true},
+		{"1 .M SC.. 160000 160000 160000 " + hash + " " + hash + " deps/my lib", SubmoduleChange{Path: "deps/my lib", NewCommits: true}, true},
+		{"1 .M N... 100644 100644 100644 " + hash + " " + hash + " README.md", SubmoduleChange{}, false},
+		{"? vendor/new", SubmoduleChange{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseSubmoduleStatus(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseSubmoduleStatus(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseStashMessage(t *testing.T) {
+	tests := []struct {
+		msg, branch, subject string
+	}{
+		{"On main: wip", "main", "wip"},
+		{"WIP on feat/auth: abc1234 add login", "feat/auth", "abc1234 add login"},
+		{"On (no branch): detached work", "", "detached work"},
+		{"custom message", "", "custom message"},
+	}
+
+	for _, tt := range tests {
+		branch, subject := parseStashMessage(tt.msg)
+		if branch != tt.branch || subject != tt.subject {
+			t.Errorf("parseStashMessage(%q) = %q, %q; want %q, %q", tt.msg, branch, subject, tt.branch, tt.subject)
+		}
+	}
+}
+
+func TestCloneDir(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:henri123lemoine/grove.git": "grove",
+		"https://github.com/henri123lemoine/grove": "grove",
+		"https://example.com/repo.git/":            "repo",
+		"../projects/lib":                          "lib",
+		"/srv/git/app.git":                         "app",
+	}
+	for source, want := range tests {
+		if got := CloneDir(source); got != want {
+			t.Errorf("CloneDir(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
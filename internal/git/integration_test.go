@@ -137,7 +137,7 @@ func TestWorktreeCreateAndDelete(t *testing.T) {
 
 	// Create a new worktree with new branch
 	wtPath := filepath.Join(repoDir, ".worktrees", "feature-test")
-	if err := Create(wtPath, "feature-test", true, ""); err != nil {
+	if err := Create(wtPath, "feature-test", true, "", nil); err != nil {
 		t.Fatalf("Create worktree failed: %v", err)
 	}
 
@@ -190,6 +190,52 @@ func TestWorktreeCreateAndDelete(t *testing.T) {
 	}
 }
 
+// TestCreateConflictsExpandedPath tests the preflight checks applied to
+// expanded worktree path templates.
+func TestCreateConflictsExpandedPath(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	bad := map[string]string{
+		"unexpanded variable": filepath.Join(repoDir, ".worktrees", "{bogus}"),
+		"inside git dir":      filepath.Join(repoDir, ".git", "wt"),
+		"contains main":       filepath.Dir(repoDir),
+	}
+	for name, path := range bad {
+		if err := checkCreateConflicts(path, "feature"); err == nil {
+			t.Errorf("%s: expected error for %s", name, path)
+		}
+	}
+
+	// Outside the repo is fine, and must not add an exclude entry
+	outside, err := os.MkdirTemp("", "grove-outside-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(outside) }()
+
+	wtPath := filepath.Join(outside, "grove", "feature")
+	if err := Create(wtPath, "feature", true, "", nil); err != nil {
+		t.Fatalf("Create outside repo failed: %v", err)
+	}
+	defer func() { _ = Remove(wtPath, true) }()
+
+	exclude, _ := os.ReadFile(filepath.Join(repoDir, ".git", "info", "exclude"))
+	if strings.Contains(string(exclude), "..") || strings.Contains(string(exclude), "grove-outside") {
+		t.Errorf("exclude file should not reference outside paths:\n%s", exclude)
+	}
+}
+
 // TestWorktreeFromExistingBranch tests creating a worktree from an existing branch.
 func TestWorktreeFromExistingBranch(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
@@ -212,7 +258,7 @@ func TestWorktreeFromExistingBranch(t *testing.T) {
 
 	// Create worktree from existing branch
 	wtPath := filepath.Join(repoDir, ".worktrees", "existing")
-	if err := Create(wtPath, "existing-branch", false, ""); err != nil {
+	if err := Create(wtPath, "existing-branch", false, "", nil); err != nil {
 		t.Fatalf("Create worktree from existing branch failed: %v", err)
 	}
 
@@ -254,7 +300,7 @@ func TestWorktreeDirExcluded(t *testing.T) {
 
 	// Create a worktree
 	wtPath := filepath.Join(repoDir, ".worktrees", "test-exclude")
-	if err := Create(wtPath, "test-exclude", true, ""); err != nil {
+	if err := Create(wtPath, "test-exclude", true, "", nil); err != nil {
 		t.Fatalf("Create worktree failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath, false) }()
@@ -271,7 +317,7 @@ func TestWorktreeDirExcluded(t *testing.T) {
 
 	// Create another worktree - should not duplicate the entry
 	wtPath2 := filepath.Join(repoDir, ".worktrees", "test-exclude-2")
-	if err := Create(wtPath2, "test-exclude-2", true, ""); err != nil {
+	if err := Create(wtPath2, "test-exclude-2", true, "", nil); err != nil {
 		t.Fatalf("Create second worktree failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath2, false) }()
@@ -404,6 +450,55 @@ func TestGetLastCommit(t *testing.T) {
 	}
 }
 
+func TestEnrichWorktreesCommitTime(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	wtPath := filepath.Join(filepath.Dir(repoDir), filepath.Base(repoDir)+"-old")
+	defer func() { _ = os.RemoveAll(wtPath) }()
+	if err := runIn(repoDir, "git", "worktree", "add", "-b", "old", wtPath); err != nil {
+		t.Fatalf("git worktree add failed: %v", err)
+	}
+
+	// Move main to a commit with a fixed committer date
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "dated")
+	cmd.Dir = repoDir
+	cmd.Env = append(filterGitEnv(os.Environ()), "GIT_COMMITTER_DATE=2001-02-03T04:05:06Z")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if err := EnrichWorktreesCommitTime(worktrees); err != nil {
+		t.Fatalf("EnrichWorktreesCommitTime failed: %v", err)
+	}
+	for _, wt := range worktrees {
+		switch wt.Branch {
+		case "old":
+			if wt.CommitTime.Year() < 2020 {
+				t.Errorf("old: unexpected commit time %v", wt.CommitTime)
+			}
+		default:
+			if wt.CommitTime.Unix() != 981173106 {
+				t.Errorf("%s: commit time = %v, want 2001-02-03T04:05:06Z", wt.Branch, wt.CommitTime.UTC())
+			}
+		}
+	}
+}
+
 // TestSafetyCheck tests the safety check for worktree deletion.
 func TestSafetyCheckIntegration(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
@@ -426,7 +521,7 @@ func TestSafetyCheckIntegration(t *testing.T) {
 
 	// Create a worktree with a new branch
 	wtPath := filepath.Join(repoDir, ".worktrees", "feature")
-	if err := Create(wtPath, "feature", true, ""); err != nil {
+	if err := Create(wtPath, "feature", true, "", nil); err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath, true) }()
@@ -532,6 +627,98 @@ func TestSafetyCheckDetachedHead(t *testing.T) {
 	}
 }
 
+// TestDetachedWorktreeAtTag tests creating a detached worktree at a tag,
+// flagging commits made on it, and converting it to a branch.
+func TestDetachedWorktreeAtTag(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	repo, err := GetRepo()
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "tag", "v1.0"); err != nil {
+		t.Fatalf("git tag failed: %v", err)
+	}
+
+	sha, ok := ResolveDetachTarget("v1.0")
+	if !ok || sha == "" {
+		t.Fatalf("ResolveDetachTarget(v1.0) = %q, %v; want a commit", sha, ok)
+	}
+	if _, ok := ResolveDetachTarget("HEAD"); !ok {
+		t.Error("HEAD should be a detach target")
+	}
+	if _, ok := ResolveDetachTarget(repo.DefaultBranch); ok {
+		t.Error("a local branch should not be a detach target")
+	}
+	if _, ok := ResolveDetachTarget("new-feature"); ok {
+		t.Error("an unknown name should not be a detach target")
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "detached", sha)
+	if err := CreateDetached(wtPath, "v1.0", nil); err != nil {
+		t.Fatalf("CreateDetached failed: %v", err)
+	}
+	defer func() { _ = Remove(wtPath, true) }()
+
+	// The listed name uses the same short hash as the path
+	detachedBranch := sha + " (detached)"
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if filepath.Base(wt.Path) == sha {
+			found = wt.Branch == detachedBranch
+		}
+	}
+	if !found {
+		t.Errorf("detached worktree not listed as %q", detachedBranch)
+	}
+
+	safety, err := CheckSafety(wtPath, detachedBranch, repo.DefaultBranch)
+	if err != nil {
+		t.Fatalf("CheckSafety failed: %v", err)
+	}
+	if safety.Level != SafetyLevelSafe {
+		t.Errorf("clean worktree at a tag should be safe, got %s", safety.Level)
+	}
+
+	// A commit made on the detached HEAD is reachable from no ref
+	if err := runIn(wtPath, "git", "commit", "--allow-empty", "-m", "bisect note"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+	safety, err = CheckSafety(wtPath, detachedBranch, repo.DefaultBranch)
+	if err != nil {
+		t.Fatalf("CheckSafety failed: %v", err)
+	}
+	if safety.Level != SafetyLevelDanger || safety.UniqueCommitCount != 1 {
+		t.Errorf("detached commit: level=%s unique=%d, want danger with 1", safety.Level, safety.UniqueCommitCount)
+	}
+
+	if err := ConvertToBranch(wtPath, "bisect-fix"); err != nil {
+		t.Fatalf("ConvertToBranch failed: %v", err)
+	}
+	if !BranchExists("bisect-fix") {
+		t.Error("bisect-fix branch should exist after conversion")
+	}
+	branch, err := runGitInDir(wtPath, "branch", "--show-current")
+	if err != nil || strings.TrimSpace(branch) != "bisect-fix" {
+		t.Errorf("worktree branch = %q (err %v), want bisect-fix", branch, err)
+	}
+}
+
 // TestSafetyCheckEmptyDefaultBranch tests CheckSafety with empty default branch.
 func TestSafetyCheckEmptyDefaultBranch(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
@@ -549,7 +736,7 @@ func TestSafetyCheckEmptyDefaultBranch(t *testing.T) {
 
 	// Create a worktree
 	wtPath := filepath.Join(repoDir, ".worktrees", "feature")
-	if err := Create(wtPath, "feature", true, ""); err != nil {
+	if err := Create(wtPath, "feature", true, "", nil); err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath, true) }()
@@ -706,7 +893,7 @@ func TestRenameBranch(t *testing.T) {
 
 	// Create a worktree with a branch
 	wtPath := filepath.Join(repoDir, ".worktrees", "feature")
-	if err := Create(wtPath, "feature", true, ""); err != nil {
+	if err := Create(wtPath, "feature", true, "", nil); err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath, true) }()
@@ -725,6 +912,188 @@ func TestRenameBranch(t *testing.T) {
 	}
 }
 
+// TestMoveWorktree tests moving a worktree and cleaning up its old parents.
+func TestMoveWorktree(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	oldPath := filepath.Join(repoDir, ".worktrees", "feature", "auth")
+	if err := Create(oldPath, "feature/auth", true, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	newPath := filepath.Join(repoDir, ".worktrees", "login")
+	if err := Move(oldPath, newPath); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	defer func() { _ = Remove(newPath, true) }()
+
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("moved worktree should exist: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, ".worktrees", "feature")); !os.IsNotExist(err) {
+		t.Error("empty parent directory of old path should be removed")
+	}
+
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if wt.Branch == "feature/auth" {
+			found = true
+			if wt.Path != newPath {
+				t.Errorf("worktree path = %q, want %q", wt.Path, newPath)
+			}
+		}
+	}
+	if !found {
+		t.Error("feature/auth worktree missing after move")
+	}
+
+	// Moving onto an existing path must fail without touching the worktree
+	if err := Move(newPath, repoDir); err == nil {
+		t.Error("Move onto an existing path should fail")
+	}
+}
+
+// TestLockUnlockWorktree tests locking with a reason and unlocking.
+func TestLockUnlockWorktree(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "usb")
+	if err := Create(wtPath, "usb", true, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer func() {
+		_ = Unlock(wtPath)
+		_ = Remove(wtPath, true)
+	}()
+
+	findWorktree := func() Worktree {
+		t.Helper()
+		worktrees, err := List()
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		for _, wt := range worktrees {
+			if wt.Branch == "usb" {
+				return wt
+			}
+		}
+		t.Fatal("usb worktree not found")
+		return Worktree{}
+	}
+
+	if err := Lock(wtPath, "on removable drive"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	wt := findWorktree()
+	if !wt.IsLocked || wt.LockReason != "on removable drive" {
+		t.Errorf("after lock: IsLocked=%v LockReason=%q", wt.IsLocked, wt.LockReason)
+	}
+
+	if err := Remove(wtPath, false); err == nil {
+		t.Error("Remove should refuse a locked worktree")
+	}
+
+	if err := Unlock(wtPath); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if wt := findWorktree(); wt.IsLocked {
+		t.Error("worktree should be unlocked")
+	}
+}
+
+func TestPruneKeepsWorktrees(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	keptPath := filepath.Join(repoDir, ".worktrees", "kept")
+	gonePath := filepath.Join(repoDir, ".worktrees", "gone")
+	lockedPath := filepath.Join(repoDir, ".worktrees", "locked")
+	for _, path := range []string{keptPath, gonePath, lockedPath} {
+		if err := Create(path, filepath.Base(path), true, "", nil); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := Lock(lockedPath, "on a removable drive"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	// Delete both directories behind git's back, leaving stale entries
+	if err := os.RemoveAll(filepath.Join(repoDir, ".worktrees")); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := Prune(keptPath)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Prune removed %d entries, want 1", count)
+	}
+
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var kept, locked *Worktree
+	for i := range worktrees {
+		switch worktrees[i].Branch {
+		case "kept":
+			kept = &worktrees[i]
+		case "locked":
+			locked = &worktrees[i]
+		case "gone":
+			t.Error("gone worktree should have been pruned")
+		}
+	}
+	if kept == nil {
+		t.Fatal("kept worktree was pruned")
+	}
+	if kept.IsLocked {
+		t.Error("Prune should not lock the kept worktree")
+	}
+	if locked == nil {
+		t.Fatal("locked worktree was pruned")
+	}
+	if !locked.IsLocked || locked.LockReason != "on a removable drive" {
+		t.Errorf("locked worktree: IsLocked=%v LockReason=%q, want lock left alone", locked.IsLocked, locked.LockReason)
+	}
+}
+
 // TestListTags tests ListTags function.
 func TestListTags(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
@@ -796,7 +1165,7 @@ func TestGetWorktreeBranches(t *testing.T) {
 
 	// Create a worktree
 	wtPath := filepath.Join(repoDir, ".worktrees", "feature")
-	if err := Create(wtPath, "feature", true, ""); err != nil {
+	if err := Create(wtPath, "feature", true, "", nil); err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
 	defer func() { _ = Remove(wtPath, true) }()
@@ -965,6 +1334,77 @@ func TestStashListEmpty(t *testing.T) {
 	}
 }
 
+// TestStashTransfer tests previewing a stash, applying it in another
+// worktree, and turning it into a branch and worktree.
+func TestStashTransfer(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Stashed\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	if _, err := CreateStash(repoDir, "transfer me"); err != nil {
+		t.Fatalf("CreateStash failed: %v", err)
+	}
+
+	otherPath := filepath.Join(repoDir, ".worktrees", "other")
+	if err := Create(otherPath, "other", true, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	stashes, err := ListStashes(otherPath)
+	if err != nil || len(stashes) != 1 {
+		t.Fatalf("ListStashes from another worktree = %v, %v; want 1 stash", stashes, err)
+	}
+	if stashes[0].Subject != "transfer me" || stashes[0].Branch == "" {
+		t.Errorf("Expected branch and subject to be parsed, got %+v", stashes[0])
+	}
+
+	diff, err := StashDiff(repoDir, 0)
+	if err != nil {
+		t.Fatalf("StashDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "README.md") || !strings.Contains(diff, "+# Stashed") {
+		t.Errorf("Expected diffstat and patch for README.md, got:\n%s", diff)
+	}
+
+	// Apply in the other worktree; the stash is kept
+	if err := ApplyStash(otherPath, 0); err != nil {
+		t.Fatalf("ApplyStash in other worktree failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(otherPath, "README.md")); string(content) != "# Stashed\n" {
+		t.Errorf("Expected stash applied in other worktree, got %q", content)
+	}
+
+	branchPath := filepath.Join(repoDir, ".worktrees", "from-stash")
+	if err := CreateFromStash(branchPath, "from-stash", 0); err != nil {
+		t.Fatalf("CreateFromStash failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(branchPath, "README.md")); string(content) != "# Stashed\n" {
+		t.Errorf("Expected stash applied in new worktree, got %q", content)
+	}
+	if branch, _ := runGitInDir(branchPath, "branch", "--show-current"); strings.TrimSpace(branch) != "from-stash" {
+		t.Errorf("Expected new worktree on from-stash, got %q", branch)
+	}
+	if stashes, _ := ListStashes(repoDir); len(stashes) != 0 {
+		t.Errorf("Expected stash dropped after branching, got %d", len(stashes))
+	}
+
+	if err := CreateFromStash(filepath.Join(repoDir, ".worktrees", "again"), "from-stash", 0); err == nil {
+		t.Error("Expected an error for an existing branch")
+	}
+}
+
 // TestCacheOperations tests cache save/load.
 func TestCacheOperations(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
@@ -1010,3 +1450,739 @@ func TestCacheOperations(t *testing.T) {
 		}
 	}
 }
+
+// TestPushSetsUpstream tests pushing a branch without upstream to a local remote.
+func TestPushSetsUpstream(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remoteDir, err := os.MkdirTemp("", "grove-remote-*")
+	if err != nil {
+		t.Fatalf("Failed to create remote dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(remoteDir) }()
+	if err := runIn(remoteDir, "git", "init", "--bare"); err != nil {
+		t.Fatalf("git init --bare failed: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	if err := runIn(repoDir, "git", "remote", "add", "origin", remoteDir); err != nil {
+		t.Fatalf("git remote add failed: %v", err)
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "push-me")
+	if err := Create(wtPath, "push-me", true, "", nil); err != nil {
+		t.Fatalf("Create worktree failed: %v", err)
+	}
+
+	if _, _, hasUpstream, _ := GetUpstreamStatus(wtPath, "push-me"); hasUpstream {
+		t.Fatal("Expected no upstream before push")
+	}
+
+	if err := Push(wtPath, "push-me", PushOptions{Remote: "origin", SetUpstream: true}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	ahead, behind, hasUpstream, _ := GetUpstreamStatus(wtPath, "push-me")
+	if !hasUpstream {
+		t.Error("Expected upstream to be set after push -u")
+	}
+	if ahead != 0 || behind != 0 {
+		t.Errorf("Expected up to date after push, got ahead=%d behind=%d", ahead, behind)
+	}
+
+	// Later pushes name the upstream explicitly, so push.default doesn't matter
+	for _, args := range [][]string{
+		{"config", "push.default", "nothing"},
+		{"commit", "--allow-empty", "-m", "more"},
+	} {
+		if err := runIn(wtPath, "git", args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	if err := Push(wtPath, "push-me", PushOptions{Remote: "origin"}); err != nil {
+		t.Fatalf("Push with push.default=nothing failed: %v", err)
+	}
+	if ahead, _, _, _ := GetUpstreamStatus(wtPath, "push-me"); ahead != 0 {
+		t.Errorf("Expected up to date after second push, got ahead=%d", ahead)
+	}
+
+	// Pushing to a remote that doesn't exist surfaces git's stderr
+	err = Push(wtPath, "push-me", PushOptions{Remote: "nope", SetUpstream: true})
+	if err == nil {
+		t.Fatal("Expected push to unknown remote to fail")
+	}
+	if !strings.Contains(err.Error(), "nope") {
+		t.Errorf("Expected error to mention remote, got: %v", err)
+	}
+
+	if err := Push(wtPath, "abc1234 (detached)", PushOptions{}); err == nil {
+		t.Error("Expected pushing a detached HEAD to fail")
+	}
+}
+
+// TestCreateTrackingFromRemote tests checking out a teammate's remote branch
+// as a local branch that tracks it.
+func TestCreateTrackingFromRemote(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remoteDir, err := os.MkdirTemp("", "grove-remote-*")
+	if err != nil {
+		t.Fatalf("Failed to create remote dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(remoteDir) }()
+	if err := runIn(remoteDir, "git", "init", "--bare"); err != nil {
+		t.Fatalf("git init --bare failed: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	// Publish feature-x, then drop the local copy so only origin/feature-x remains
+	for _, args := range [][]string{
+		{"remote", "add", "origin", remoteDir},
+		{"branch", "feature-x"},
+		{"push", "origin", "feature-x"},
+		{"branch", "-D", "feature-x"},
+		{"fetch", "origin"},
+	} {
+		if err := runIn(repoDir, "git", args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	for _, name := range []string{"origin/feature-x", "feature-x"} {
+		remote, local, ok := ResolveRemoteBranch(name, "origin")
+		if !ok || remote != "origin/feature-x" || local != "feature-x" {
+			t.Errorf("ResolveRemoteBranch(%q) = %q, %q, %v", name, remote, local, ok)
+		}
+	}
+	if _, _, ok := ResolveRemoteBranch("nope", "origin"); ok {
+		t.Error("unknown branch should not resolve to a remote")
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "feature-x")
+	if err := CreateTracking(wtPath, "feature-x", "origin/feature-x", nil); err != nil {
+		t.Fatalf("CreateTracking failed: %v", err)
+	}
+	defer func() { _ = Remove(wtPath, true) }()
+
+	upstream, err := runGitInDir(wtPath, "rev-parse", "--abbrev-ref", "feature-x@{upstream}")
+	if err != nil || strings.TrimSpace(upstream) != "origin/feature-x" {
+		t.Errorf("upstream = %q (err %v), want origin/feature-x", upstream, err)
+	}
+
+	// Now that feature-x exists locally, the bare name is no longer a remote checkout
+	if _, _, ok := ResolveRemoteBranch("feature-x", "origin"); ok {
+		t.Error("existing local branch should not resolve to a remote")
+	}
+
+	// A new branch started from a remote base doesn't track it
+	otherPath := filepath.Join(repoDir, ".worktrees", "other")
+	if err := Create(otherPath, "other", true, "origin/feature-x", nil); err != nil {
+		t.Fatalf("Create from remote base failed: %v", err)
+	}
+	defer func() { _ = Remove(otherPath, true) }()
+	if upstream, err := runGitInDir(otherPath, "rev-parse", "--abbrev-ref", "other@{upstream}"); err == nil {
+		t.Errorf("new branch from a remote base tracks %q, want no upstream", strings.TrimSpace(upstream))
+	}
+}
+
+// TestListBranchesWithoutWorktrees tests merged, squash-merged and gone detection.
+func TestListBranchesWithoutWorktrees(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	defaultBranch, err := CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	commitOn := func(branch, file string) {
+		t.Helper()
+		if err := runIn(repoDir, "git", "checkout", "-q", branch); err != nil {
+			t.Fatalf("git checkout %s failed: %v", branch, err)
+		}
+		if err := os.WriteFile(filepath.Join(repoDir, file), []byte(file+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+		if err := runIn(repoDir, "git", "add", file); err != nil {
+			t.Fatalf("git add failed: %v", err)
+		}
+		if err := runIn(repoDir, "git", "commit", "-q", "-m", "add "+file); err != nil {
+			t.Fatalf("git commit failed: %v", err)
+		}
+	}
+
+	// merged: fast-forward ancestor of default
+	// squashed: changes landed on default via squash merge
+	// rebased: each commit landed on default as an equivalent commit
+	// unmerged: commits only on the branch
+	for _, b := range []string{"merged", "squashed", "rebased", "unmerged", "in-worktree"} {
+		if err := runIn(repoDir, "git", "branch", b); err != nil {
+			t.Fatalf("git branch %s failed: %v", b, err)
+		}
+	}
+	commitOn("squashed", "squashed.txt")
+	commitOn("squashed", "squashed2.txt")
+	commitOn("rebased", "rebased.txt")
+	commitOn("rebased", "rebased2.txt")
+	commitOn("unmerged", "unmerged.txt")
+	if err := runIn(repoDir, "git", "checkout", "-q", defaultBranch); err != nil {
+		t.Fatalf("git checkout failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "merge", "-q", "--squash", "squashed"); err != nil {
+		t.Fatalf("git merge --squash failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "commit", "-q", "-m", "squash"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "cherry-pick", "rebased~1", "rebased"); err != nil {
+		t.Fatalf("git cherry-pick failed: %v", err)
+	}
+
+	// Upstream that no longer exists
+	if err := runIn(repoDir, "git", "config", "branch.merged.remote", "."); err != nil {
+		t.Fatalf("git config failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "config", "branch.merged.merge", "refs/heads/deleted"); err != nil {
+		t.Fatalf("git config failed: %v", err)
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "in-worktree")
+	if err := Create(wtPath, "in-worktree", false, "", nil); err != nil {
+		t.Fatalf("Create worktree failed: %v", err)
+	}
+
+	objectsBefore, _ := runGitInDir(repoDir, "count-objects")
+	branches, err := ListBranchesWithoutWorktrees(defaultBranch)
+	if err != nil {
+		t.Fatalf("ListBranchesWithoutWorktrees failed: %v", err)
+	}
+	if objectsAfter, _ := runGitInDir(repoDir, "count-objects"); objectsAfter != objectsBefore {
+		t.Errorf("Listing branches wrote objects: %q -> %q", objectsBefore, objectsAfter)
+	}
+
+	byName := make(map[string]BranchInfo)
+	for _, b := range branches {
+		byName[b.Name] = b
+	}
+
+	if _, ok := byName["in-worktree"]; ok {
+		t.Error("Branches checked out in a worktree should be excluded")
+	}
+	if _, ok := byName[defaultBranch]; ok {
+		t.Error("Default branch is checked out in the main worktree and should be excluded")
+	}
+
+	if b := byName["merged"]; !b.IsMerged || b.IsSquashMerged {
+		t.Errorf("merged: expected IsMerged only, got %+v", b)
+	}
+	if b := byName["merged"]; !b.UpstreamGone {
+		t.Errorf("merged: expected upstream gone, got %+v", b)
+	}
+	if b := byName["squashed"]; b.IsMerged || !b.IsSquashMerged {
+		t.Errorf("squashed: expected IsSquashMerged only, got %+v", b)
+	}
+	if b := byName["rebased"]; b.IsMerged || !b.IsSquashMerged {
+		t.Errorf("rebased: expected IsSquashMerged only, got %+v", b)
+	}
+	if b := byName["unmerged"]; b.IsSquashMerged {
+		t.Errorf("unmerged: expected not squash-merged, got %+v", b)
+	}
+	if b := byName["unmerged"]; b.IsStale() {
+		t.Errorf("unmerged: expected not stale, got %+v", b)
+	}
+	if b := byName["unmerged"]; b.LastCommitTime == "" {
+		t.Error("unmerged: expected last commit time to be set")
+	}
+
+	// Set upstream to an existing branch
+	if err := SetBranchUpstream("unmerged", "squashed"); err != nil {
+		t.Fatalf("SetBranchUpstream failed: %v", err)
+	}
+	branches, _ = ListBranchesWithoutWorktrees(defaultBranch)
+	for _, b := range branches {
+		if b.Name == "unmerged" && b.Upstream != "squashed" {
+			t.Errorf("Expected upstream 'squashed', got %q", b.Upstream)
+		}
+	}
+}
+
+// TestFindGoneBranches tests gone-upstream detection and pruning safety.
+func TestFindGoneBranches(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	defaultBranch, err := CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+
+	// Point each branch's upstream at a ref that doesn't exist, which is
+	// exactly what for-each-ref reports as [gone]
+	setGone := func(branch string) {
+		t.Helper()
+		if err := runIn(repoDir, "git", "config", "branch."+branch+".remote", "."); err != nil {
+			t.Fatalf("git config failed: %v", err)
+		}
+		if err := runIn(repoDir, "git", "config", "branch."+branch+".merge", "refs/heads/deleted-"+branch); err != nil {
+			t.Fatalf("git config failed: %v", err)
+		}
+	}
+
+	for _, b := range []string{"gone-clean", "gone-unique", "gone-dirty", "tracked"} {
+		if err := runIn(repoDir, "git", "branch", b); err != nil {
+			t.Fatalf("git branch %s failed: %v", b, err)
+		}
+	}
+	setGone("gone-clean")
+	setGone("gone-unique")
+	setGone("gone-dirty")
+
+	// gone-unique gets a commit that isn't on the default branch
+	uniquePath := filepath.Join(repoDir, ".worktrees", "gone-unique")
+	if err := Create(uniquePath, "gone-unique", false, "", nil); err != nil {
+		t.Fatalf("Create worktree failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(uniquePath, "unique.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := runIn(uniquePath, "git", "add", "."); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+	if err := runIn(uniquePath, "git", "commit", "-q", "-m", "unique"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+
+	dirtyPath := filepath.Join(repoDir, ".worktrees", "gone-dirty")
+	if err := Create(dirtyPath, "gone-dirty", false, "", nil); err != nil {
+		t.Fatalf("Create worktree failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dirtyPath, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	EnrichWorktreesUpstream(worktrees)
+	for _, wt := range worktrees {
+		wantGone := wt.Branch == "gone-unique" || wt.Branch == "gone-dirty"
+		if wt.UpstreamGone != wantGone {
+			t.Errorf("Worktree %s: expected UpstreamGone=%v", wt.Branch, wantGone)
+		}
+	}
+
+	gone, err := FindGoneBranches(worktrees, defaultBranch)
+	if err != nil {
+		t.Fatalf("FindGoneBranches failed: %v", err)
+	}
+	if len(gone) != 3 {
+		t.Fatalf("Expected 3 gone branches, got %d: %+v", len(gone), gone)
+	}
+
+	byBranch := make(map[string]GoneBranch)
+	for _, g := range gone {
+		byBranch[g.Branch] = g
+	}
+	if g := byBranch["gone-clean"]; g.SkipReason != "" || g.Worktree != nil {
+		t.Errorf("gone-clean: expected prunable branch without worktree, got %+v", g)
+	}
+	if g := byBranch["gone-unique"]; !strings.Contains(g.SkipReason, "unique") {
+		t.Errorf("gone-unique: expected unique commits skip reason, got %q", g.SkipReason)
+	}
+	if g := byBranch["gone-dirty"]; !strings.Contains(g.SkipReason, "uncommitted") {
+		t.Errorf("gone-dirty: expected uncommitted changes skip reason, got %q", g.SkipReason)
+	}
+	if _, ok := byBranch["tracked"]; ok {
+		t.Error("Branch without an upstream should not be reported as gone")
+	}
+}
+
+func TestSparseWorktreeCreateAndWiden(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	for _, dir := range []string{"services/api", "services/web"} {
+		if err := os.MkdirAll(filepath.Join(repoDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoDir, dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := runIn(repoDir, "git", "add", "."); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "commit", "-m", "Add services"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "api-only")
+	sparse := &SparseCheckout{Profile: "api", Paths: []string{"services/api"}}
+	if err := Create(wtPath, "api-only", true, "", sparse); err != nil {
+		t.Fatalf("Create (sparse) failed: %v", err)
+	}
+
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(wtPath, filepath.FromSlash(rel)))
+		return err == nil
+	}
+	if !exists("services/api/main.go") || !exists("README.md") {
+		t.Error("sparse worktree is missing files from its profile")
+	}
+	if exists("services/web") {
+		t.Error("sparse worktree checked out services/web")
+	}
+	if dirty, _, _ := GetDirtyStatus(wtPath); dirty {
+		t.Error("sparse worktree should be clean after checkout")
+	}
+
+	status, err := GetSparseStatus(wtPath)
+	if err != nil || status == nil {
+		t.Fatalf("GetSparseStatus = %v, %v; want sparse", status, err)
+	}
+	if status.Profile != "api" || len(status.Paths) != 1 || status.Paths[0] != "services/api" {
+		t.Errorf("GetSparseStatus = %+v, want profile api with services/api", status)
+	}
+
+	// The main worktree is unaffected
+	if mainStatus, _ := GetSparseStatus(repoDir); mainStatus != nil {
+		t.Errorf("main worktree reported sparse: %+v", mainStatus)
+	}
+
+	// List reports it, so actions needing a sparse checkout work right away
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, wt := range worktrees {
+		if sparse := ResolvePath(wt.Path) == ResolvePath(wtPath); (wt.Sparse != nil) != sparse {
+			t.Errorf("List: %s Sparse = %+v, want sparse %v", wt.Path, wt.Sparse, sparse)
+		}
+	}
+
+	if err := WidenSparse(wtPath, []string{"services/web"}, "api+web"); err != nil {
+		t.Fatalf("WidenSparse failed: %v", err)
+	}
+	if !exists("services/web/main.go") {
+		t.Error("services/web not checked out after widening")
+	}
+	if status, _ := GetSparseStatus(wtPath); status == nil || status.Profile != "api+web" {
+		t.Errorf("profile after widening = %+v, want api+web", status)
+	}
+
+	if err := WidenSparse(wtPath, nil, ""); err != nil {
+		t.Fatalf("WidenSparse (disable) failed: %v", err)
+	}
+	if status, _ := GetSparseStatus(wtPath); status != nil {
+		t.Errorf("worktree still sparse after disabling: %+v", status)
+	}
+}
+
+func TestSubmoduleWorktree(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+	libDir, libCleanup := setupTestRepo(t)
+	defer libCleanup()
+
+	// Local file:// submodule URLs are refused by default since git 2.38.1
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	if err := runIn(repoDir, "git", "submodule", "add", libDir, "vendor/lib"); err != nil {
+		t.Fatalf("git submodule add failed: %v", err)
+	}
+	if err := runIn(repoDir, "git", "commit", "-m", "Add submodule"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+
+	wtPath := filepath.Join(repoDir, ".worktrees", "with-sub")
+	if err := Create(wtPath, "with-sub", true, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := UpdateSubmodules(wtPath, true); err != nil {
+		t.Fatalf("UpdateSubmodules failed: %v", err)
+	}
+	subDir := filepath.Join(wtPath, "vendor", "lib")
+	if _, err := os.Stat(filepath.Join(subDir, "README.md")); err != nil {
+		t.Fatalf("submodule not checked out: %v", err)
+	}
+	subGitDir, err := runGitInDir(subDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		t.Fatalf("rev-parse failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(strings.TrimSpace(subGitDir), "objects", "info", "alternates")); err != nil {
+		t.Error("submodule does not reference the main worktree's objects")
+	}
+
+	if files, subs, err := GetStatus(wtPath); err != nil || files != 0 || len(subs) != 0 {
+		t.Errorf("GetStatus on fresh worktree = %d, %v, %v; want clean", files, subs, err)
+	}
+
+	// Uncommitted work inside the submodule is reported separately and is dangerous
+	if err := os.WriteFile(filepath.Join(subDir, "README.md"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, subs, err := GetStatus(wtPath)
+	if err != nil || files != 0 || len(subs) != 1 {
+		t.Fatalf("GetStatus = %d, %v, %v; want one changed submodule", files, subs, err)
+	}
+	if subs[0].Path != "vendor/lib" || !subs[0].Modified || subs[0].NewCommits {
+		t.Errorf("submodule change = %+v, want vendor/lib modified", subs[0])
+	}
+
+	repo, err := GetRepo()
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	info, err := CheckSafety(wtPath, "with-sub", repo.DefaultBranch)
+	if err != nil {
+		t.Fatalf("CheckSafety failed: %v", err)
+	}
+	if info.Level != SafetyLevelDanger || !info.HasSubmoduleChanges || info.HasUncommittedChanges {
+		t.Errorf("CheckSafety = %+v, want danger from submodule changes only", info)
+	}
+
+	if err := Remove(wtPath, false); err == nil {
+		t.Fatal("Remove without force discarded submodule changes")
+	}
+
+	// A commit made only in the worktree's submodule would be lost with it
+	if err := runIn(subDir, "git", "-c", "user.email=test@test.com", "-c", "user.name=Test User", "commit", "-q", "-am", "Local only"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+	info, err = CheckSafety(wtPath, "with-sub", repo.DefaultBranch)
+	if err != nil {
+		t.Fatalf("CheckSafety failed: %v", err)
+	}
+	if info.Level != SafetyLevelDanger || len(info.SubmoduleChanges) != 1 || !info.SubmoduleChanges[0].Unpushed {
+		t.Errorf("CheckSafety = %+v, want danger from unpushed submodule commits", info)
+	}
+
+	// Once pushed, the submodule is only out of date
+	if err := runIn(subDir, "git", "push", "-q", "origin", "HEAD:refs/heads/local-work"); err != nil {
+		t.Fatalf("git push failed: %v", err)
+	}
+	info, err = CheckSafety(wtPath, "with-sub", repo.DefaultBranch)
+	if err != nil {
+		t.Fatalf("CheckSafety failed: %v", err)
+	}
+	if info.Level != SafetyLevelWarning || len(info.SubmoduleChanges) != 1 || info.SubmoduleChanges[0].Unpushed {
+		t.Errorf("CheckSafety = %+v, want warning for pushed submodule commits", info)
+	}
+
+	// Once clean, a worktree with submodules can be removed without force
+	if err := runIn(wtPath, "git", "submodule", "update"); err != nil {
+		t.Fatalf("git submodule update failed: %v", err)
+	}
+	if err := Remove(wtPath, false); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+}
+
+func TestCloneBare(t *testing.T) {
+	sourceDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+	if err := runIn(sourceDir, "git", "branch", "feature"); err != nil {
+		t.Fatalf("git branch failed: %v", err)
+	}
+	sourceBranch, err := runGitInDir(sourceDir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		t.Fatalf("symbolic-ref failed: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "project")
+	branch, err := CloneBare(sourceDir, dir)
+	if err != nil {
+		t.Fatalf("CloneBare failed: %v", err)
+	}
+	if branch != strings.TrimSpace(sourceBranch) {
+		t.Errorf("default branch = %q, want %q", branch, strings.TrimSpace(sourceBranch))
+	}
+
+	gitFile, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil || string(gitFile) != "gitdir: ./.bare\n" {
+		t.Errorf(".git = %q, %v; want gitdir: ./.bare", gitFile, err)
+	}
+	if refspec, _ := runGitInDir(dir, "config", "remote.origin.fetch"); strings.TrimSpace(refspec) != "+refs/heads/*:refs/remotes/origin/*" {
+		t.Errorf("remote.origin.fetch = %q", refspec)
+	}
+	if _, err := runGitInDir(dir, "rev-parse", "--verify", "refs/remotes/origin/feature"); err != nil {
+		t.Error("origin/feature was not fetched")
+	}
+	if _, err := runGitInDir(dir, "rev-parse", "--verify", "refs/heads/feature"); err == nil {
+		t.Error("feature should only exist as a remote branch")
+	}
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	repo, err := GetRepo()
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	resolvedDir, _ := filepath.EvalSymlinks(dir)
+	if !repo.IsBare || ResolvePath(repo.MainWorktreeRoot) != resolvedDir {
+		t.Errorf("GetRepo = bare %v, main root %q; want bare with root %q", repo.IsBare, repo.MainWorktreeRoot, resolvedDir)
+	}
+
+	wtPath := filepath.Join(dir, ".worktrees", branch)
+	if err := Create(wtPath, branch, false, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	upstream, err := runGitInDir(wtPath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	if err != nil || strings.TrimSpace(upstream) != "origin/"+branch {
+		t.Errorf("upstream = %q, %v; want origin/%s", upstream, err, branch)
+	}
+}
+
+func TestCloneBareCleansUpOnFailure(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	dir := filepath.Join(t.TempDir(), "project")
+	if _, err := CloneBare(missing, dir); err == nil {
+		t.Fatal("CloneBare of a missing source should fail")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("failed clone left %s behind", dir)
+	}
+
+	// A directory that already existed is kept, but emptied again
+	existing := t.TempDir()
+	if _, err := CloneBare(missing, existing); err == nil {
+		t.Fatal("CloneBare of a missing source should fail")
+	}
+	if entries, err := os.ReadDir(existing); err != nil || len(entries) != 0 {
+		t.Errorf("failed clone left %v in %s (err %v)", entries, existing, err)
+	}
+}
+
+func TestFindAndRepairMovedWorktree(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(originalDir)
+		ResetRepo()
+	}()
+	ResetRepo()
+
+	oldPath := filepath.Join(repoDir, "adhoc")
+	if err := Create(oldPath, "adhoc", true, "", nil); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	newPath := filepath.Join(repoDir, ".worktrees", "adhoc")
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	prunable := 0
+	for _, wt := range worktrees {
+		if wt.IsPrunable {
+			prunable++
+		}
+	}
+	if prunable != 1 {
+		t.Fatalf("found %d prunable worktrees, want 1", prunable)
+	}
+
+	moved := FindMovedWorktrees(worktrees, []string{filepath.Join(repoDir, ".worktrees")}, 3)
+	if len(moved) != 1 || moved[0].OldPath != oldPath || moved[0].NewPath != newPath {
+		t.Fatalf("FindMovedWorktrees = %+v, want %s -> %s", moved, oldPath, newPath)
+	}
+
+	if err := RepairWorktrees([]string{newPath}); err != nil {
+		t.Fatalf("RepairWorktrees failed: %v", err)
+	}
+	worktrees, err = List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsPrunable {
+			t.Errorf("%s still prunable after repair", wt.Path)
+		}
+		if wt.Branch == "adhoc" && wt.Path != newPath {
+			t.Errorf("adhoc registered at %s, want %s", wt.Path, newPath)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/henri123lemoine/grove/internal/debug"
+)
+
+// PushOptions controls how a worktree's branch is pushed.
+type PushOptions struct {
+	// Remote to push to when the branch has no upstream yet.
+	Remote string
+
+	// SetUpstream pushes with -u so the branch starts tracking Remote.
+	SetUpstream bool
+
+	// ForceWithLease overwrites the remote branch, but only if it still
+	// matches what we last fetched.
+	ForceWithLease bool
+}
+
+// Push pushes the branch checked out in worktreePath.
+// On failure, the returned error carries git's stderr (which includes any
+// messages from the remote) so it can be shown to the user as-is.
+func Push(worktreePath, branch string, opts PushOptions) error {
+	if branch == "" || isDetachedBranch(branch) {
+		return fmt.Errorf("cannot push detached HEAD")
+	}
+
+	remote := opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	// Always name the destination so push.default can't send the branch
+	// somewhere unexpected
+	args := []string{"push"}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	if opts.SetUpstream {
+		args = append(args, "-u", remote, branch)
+	} else if upRemote, upBranch, ok := pushUpstream(worktreePath, branch); ok {
+		args = append(args, upRemote, "refs/heads/"+branch+":refs/heads/"+upBranch)
+	} else {
+		args = append(args, remote, branch)
+	}
+
+	start := time.Now()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	debug.Log("git %s (in %s): %v", strings.Join(args, " "), worktreePath, time.Since(start))
+
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("push %s failed: %s", branch, msg)
+	}
+	return nil
+}
+
+// pushUpstream returns the remote and remote branch name the branch tracks.
+// Branches tracking another local branch report false.
+func pushUpstream(worktreePath, branch string) (remote, remoteBranch string, ok bool) {
+	remote, err := runGitInDir(worktreePath, "config", "--get", "branch."+branch+".remote")
+	if err != nil {
+		return "", "", false
+	}
+	merge, err := runGitInDir(worktreePath, "config", "--get", "branch."+branch+".merge")
+	if err != nil {
+		return "", "", false
+	}
+	remote = strings.TrimSpace(remote)
+	remoteBranch, found := strings.CutPrefix(strings.TrimSpace(merge), "refs/heads/")
+	if remote == "" || remote == "." || !found {
+		return "", "", false
+	}
+	return remote, remoteBranch, true
+}
@@ -21,7 +21,8 @@ type Repo struct {
 	Root string
 
 	// MainWorktreeRoot is the main worktree root (where .worktrees should be created).
-	// For bare repos, this is the git directory itself.
+	// For bare repos, this is the git directory itself, or the directory
+	// containing it for the .bare layout.
 	MainWorktreeRoot string
 
 	// GitDir is the path to the .git directory (the common git dir).
@@ -126,7 +127,10 @@ func detectRepo() (*Repo, error) {
 
 	// Get main worktree root (where .worktrees should be created)
 	var mainRoot string
-	if isBare {
+	if isBare && filepath.Base(gitDir) == BareDirName {
+		// Worktree-first layout (grove clone): the directory holding .bare
+		mainRoot = filepath.Dir(gitDir)
+	} else if isBare {
 		mainRoot = gitDir
 	} else {
 		// For normal repos, the main worktree is the parent of the .git directory
@@ -270,6 +274,22 @@ func runGit(args ...string) (string, error) {
 	return stdout.String(), nil
 }
 
+// runGitWithInput executes a git command with input on stdin.
+func runGitWithInput(input string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
 // runGitInDir executes a git command in a specific directory.
 func runGitInDir(dir string, args ...string) (string, error) {
 	start := time.Now()
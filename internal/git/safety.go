@@ -2,6 +2,9 @@ package git
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -18,10 +21,12 @@ const (
 	// SafetyLevelWarning means deletion may lose some work, but it's recoverable.
 	// - Has unpushed commits (but branch exists on remote)
 	// - Branch not merged (but exists on remote)
+	// - Submodule checked out at a different commit than recorded
 	SafetyLevelWarning
 
 	// SafetyLevelDanger means deletion will permanently lose work.
 	// - Has uncommitted changes (staged, unstaged, or untracked files)
+	// - Has submodules with uncommitted changes
 	// - Has commits that exist ONLY locally (not pushed, not merged)
 	SafetyLevelDanger
 )
@@ -34,6 +39,9 @@ type SafetyInfo struct {
 	HasUncommittedChanges bool
 	UncommittedFileCount  int
 
+	HasSubmoduleChanges bool
+	SubmoduleChanges    []SubmoduleChange
+
 	HasUnpushedCommits  bool
 	UnpushedCommitCount int
 
@@ -48,6 +56,12 @@ type SafetyInfo struct {
 	SafetyCheckErrors    []string
 }
 
+// HasLocalChanges reports whether the worktree or any of its submodules has
+// changes that "git worktree remove" would refuse to discard without --force.
+func (s *SafetyInfo) HasLocalChanges() bool {
+	return s.HasUncommittedChanges || s.HasSubmoduleChanges
+}
+
 // CommitInfo represents basic commit information.
 type CommitInfo struct {
 	Hash    string
@@ -86,13 +100,34 @@ func CheckSafety(worktreePath, branch, defaultBranch string) (*SafetyInfo, error
 
 	// 1. Check for uncommitted changes (staged, unstaged, untracked)
 	// These are truly unrecoverable, so this is Danger level
-	isDirty, count, err := GetDirtyStatus(worktreePath)
+	files, submodules, err := GetStatus(worktreePath)
 	if err != nil {
 		recordError("could not check uncommitted changes: %v", err)
-	} else if isDirty {
-		info.HasUncommittedChanges = true
-		info.UncommittedFileCount = count
-		info.Level = SafetyLevelDanger
+	} else {
+		if files > 0 {
+			info.HasUncommittedChanges = true
+			info.UncommittedFileCount = files
+			info.Level = SafetyLevelDanger
+		}
+		// Submodule checkouts are deleted along with the worktree. Local
+		// changes in them are lost, and so are commits no remote has; a
+		// moved HEAD that was pushed may only be out of date.
+		for _, sub := range submodules {
+			if sub.NewCommits {
+				pushed, err := submoduleHeadPushed(worktreePath, sub.Path)
+				if err != nil {
+					recordError("could not check submodule %s for unpushed commits: %v", sub.Path, err)
+				}
+				sub.Unpushed = err == nil && !pushed
+			}
+			info.HasSubmoduleChanges = true
+			info.SubmoduleChanges = append(info.SubmoduleChanges, sub)
+			if sub.HasUnsavedWork() {
+				info.Level = SafetyLevelDanger
+			} else if info.Level < SafetyLevelWarning {
+				info.Level = SafetyLevelWarning
+			}
+		}
 	}
 
 	// 2. Check if branch is merged to default
@@ -136,9 +171,20 @@ func CheckSafety(worktreePath, branch, defaultBranch string) (*SafetyInfo, error
 	}
 
 	// 4. Check for unique commits (the key safety feature)
-	// These are commits that exist ONLY on this branch and not on default
-	// For detached HEAD, we can't determine unique commits easily, so skip
-	if branch != "" && branch != defaultBranch && !isDetached && defaultBranch != "" {
+	// These are commits that exist ONLY on this branch and not on default.
+	// A detached HEAD has no branch to keep its commits alive, so anything
+	// not reachable from some branch, tag, or remote ref is lost on delete.
+	if isDetached {
+		commits, err := GetDetachedUniqueCommits(worktreePath)
+		if err != nil {
+			recordError("could not verify unique commits: %v", err)
+		} else if len(commits) > 0 {
+			info.HasUniqueCommits = true
+			info.UniqueCommitCount = len(commits)
+			info.UniqueCommits = commits
+			info.Level = SafetyLevelDanger
+		}
+	} else if branch != "" && branch != defaultBranch && defaultBranch != "" {
 		commits, err := GetUniqueCommits(branch, defaultBranch)
 		if err != nil {
 			recordError("could not verify unique commits: %v", err)
@@ -174,9 +220,24 @@ func GetUniqueCommits(branch, defaultBranch string) ([]CommitInfo, error) {
 		return nil, err
 	}
 
+	return parseCommitLines(output), nil
+}
+
+// GetDetachedUniqueCommits returns commits reachable from a detached
+// worktree's HEAD that no branch, tag, or remote-tracking ref contains.
+func GetDetachedUniqueCommits(worktreePath string) ([]CommitInfo, error) {
+	output, err := runGitInDir(worktreePath, "log", "HEAD", "--not", "--branches", "--tags", "--remotes", "--format=%h %s")
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLines(output), nil
+}
+
+// parseCommitLines parses "hash subject" lines from git log --format=%h %s.
+func parseCommitLines(output string) []CommitInfo {
 	output = strings.TrimSpace(output)
 	if output == "" {
-		return nil, nil
+		return nil
 	}
 
 	var commits []CommitInfo
@@ -198,7 +259,7 @@ func GetUniqueCommits(branch, defaultBranch string) ([]CommitInfo, error) {
 		}
 	}
 
-	return commits, nil
+	return commits
 }
 
 // IsBranchMerged checks if a branch is merged into another branch.
@@ -210,6 +271,128 @@ func IsBranchMerged(branch, intoBranch string) (bool, error) {
 	return merged[branch], nil
 }
 
+// IsSquashMerged checks if a branch's changes were integrated into another
+// branch without its commits (squash merge or rebase). To check several
+// branches against the same target, use a SquashChecker.
+func IsSquashMerged(branch, intoBranch string) (bool, error) {
+	return NewSquashChecker(intoBranch, branch).IsSquashMerged(branch)
+}
+
+// SquashChecker finds branches whose changes reached a target branch without
+// their commits. A rebase leaves an upstream commit with the same patch-id
+// for every branch commit; a squash merge leaves one upstream commit with
+// the patch-id of the branch's whole diff. The patch-ids of the target's
+// commits are computed once, on first use, and shared by every check.
+// Nothing is written to the repository.
+type SquashChecker struct {
+	into     string
+	branches []string
+	upstream map[string]bool // Patch-ids of the target's commits
+	err      error
+}
+
+// NewSquashChecker returns a checker for branches merged into intoBranch.
+// branches are the ones that will be checked; they bound how much of the
+// target's history is read.
+func NewSquashChecker(intoBranch string, branches ...string) *SquashChecker {
+	return &SquashChecker{into: intoBranch, branches: branches}
+}
+
+// IsSquashMerged reports whether branch was squash-merged or rebased into
+// the checker's target branch.
+func (c *SquashChecker) IsSquashMerged(branch string) (bool, error) {
+	mergeBase, err := runGit("merge-base", c.into, branch)
+	if err != nil {
+		return false, err
+	}
+	mergeBase = strings.TrimSpace(mergeBase)
+
+	// Nothing on the branch since it forked - it's plainly merged, not squashed
+	tip, err := runGit("rev-parse", branch)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(tip) == mergeBase {
+		return false, nil
+	}
+
+	upstream, err := c.upstreamPatchIDs()
+	if err != nil {
+		return false, err
+	}
+
+	// Rebased: every branch commit has an equivalent upstream
+	commits, err := runGit("log", "-p", "--no-ext-diff", "--no-merges", mergeBase+".."+branch)
+	if err != nil {
+		return false, err
+	}
+	commitIDs, err := patchIDs(commits)
+	if err != nil {
+		return false, err
+	}
+	if len(commitIDs) > 0 && !slices.ContainsFunc(commitIDs, func(id string) bool { return !upstream[id] }) {
+		return true, nil
+	}
+
+	// Squashed: the branch's whole diff matches a single upstream commit
+	diff, err := runGit("diff", "--no-ext-diff", mergeBase, branch)
+	if err != nil {
+		return false, err
+	}
+	branchIDs, err := patchIDs(diff)
+	if err != nil || len(branchIDs) == 0 {
+		return false, err
+	}
+	return upstream[branchIDs[0]], nil
+}
+
+// upstreamPatchIDs returns the patch-ids of the target's commits since it
+// forked from the branches being checked, loading them on first use.
+func (c *SquashChecker) upstreamPatchIDs() (map[string]bool, error) {
+	if c.upstream != nil || c.err != nil {
+		return c.upstream, c.err
+	}
+
+	// Commits older than the common ancestor of every branch can't hold
+	// their changes. Without one (unrelated histories) read everything.
+	revRange := c.into
+	args := append([]string{"merge-base", "--octopus", c.into}, c.branches...)
+	if base, err := runGit(args...); err == nil {
+		revRange = strings.TrimSpace(base) + ".." + c.into
+	}
+
+	log, err := runGit("log", "-p", "--no-ext-diff", "--no-merges", revRange)
+	if err != nil {
+		c.err = err
+		return nil, err
+	}
+	ids, err := patchIDs(log)
+	if err != nil {
+		c.err = err
+		return nil, err
+	}
+	c.upstream = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		c.upstream[id] = true
+	}
+	return c.upstream, nil
+}
+
+// patchIDs returns the stable patch-ids of the patches in a diff or log.
+func patchIDs(patches string) ([]string, error) {
+	output, err := runGitWithInput(patches, "patch-id", "--stable")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if id, _, ok := strings.Cut(line, " "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 // GetMergedBranches returns a set of all branches merged into the given branch.
 // Call this once and reuse the result to avoid repeated git calls.
 func GetMergedBranches(intoBranch string) (map[string]bool, error) {
@@ -230,6 +413,90 @@ func GetMergedBranches(intoBranch string) (map[string]bool, error) {
 	return merged, nil
 }
 
+// GoneBranch is a local branch whose upstream was deleted, together with the
+// worktree it's checked out in (if any) and why it can't be pruned (if it can't).
+type GoneBranch struct {
+	Branch     string
+	Worktree   *Worktree
+	SkipReason string
+}
+
+// FindGoneBranches returns branches with a gone upstream, checking each for
+// work that pruning would lose. Worktrees go through CheckSafety; branches
+// without a worktree are checked for unique commits. Unique commits are
+// acceptable when the branch was squash-merged into the default branch.
+func FindGoneBranches(worktrees []Worktree, defaultBranch string) ([]GoneBranch, error) {
+	gone, err := GetGoneBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	byBranch := make(map[string]*Worktree, len(worktrees))
+	for i := range worktrees {
+		if !worktrees[i].IsDetached && worktrees[i].Branch != "" {
+			byBranch[worktrees[i].Branch] = &worktrees[i]
+		}
+	}
+	squash := NewSquashChecker(defaultBranch, slices.Collect(maps.Keys(gone))...)
+
+	var result []GoneBranch
+	for branch := range gone {
+		if branch == defaultBranch {
+			continue
+		}
+		candidate := GoneBranch{Branch: branch, Worktree: byBranch[branch]}
+
+		switch {
+		case candidate.Worktree != nil && candidate.Worktree.IsMain:
+			candidate.SkipReason = "checked out in main worktree"
+		case defaultBranch == "":
+			candidate.SkipReason = "default branch could not be detected"
+		case candidate.Worktree != nil && candidate.Worktree.IsLocked:
+			candidate.SkipReason = "worktree is locked"
+		case candidate.Worktree != nil:
+			info, _ := CheckSafety(candidate.Worktree.Path, branch, defaultBranch)
+			candidate.SkipReason = goneSkipReason(info, branch, squash)
+		default:
+			commits, err := GetUniqueCommits(branch, defaultBranch)
+			if err != nil {
+				candidate.SkipReason = fmt.Sprintf("could not verify unique commits: %v", err)
+			} else if len(commits) > 0 {
+				if squashed, _ := squash.IsSquashMerged(branch); !squashed {
+					candidate.SkipReason = fmt.Sprintf("%d unique commits", len(commits))
+				}
+			}
+		}
+
+		result = append(result, candidate)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Branch < result[j].Branch })
+	return result, nil
+}
+
+// goneSkipReason explains why a gone-upstream worktree isn't safe to prune,
+// or returns "" if it is.
+func goneSkipReason(info *SafetyInfo, branch string, squash *SquashChecker) string {
+	if info == nil {
+		return "safety check failed"
+	}
+	if info.HasUncommittedChanges {
+		return fmt.Sprintf("%d uncommitted changes", info.UncommittedFileCount)
+	}
+	if info.HasSubmoduleChanges {
+		return fmt.Sprintf("%d changed submodules", len(info.SubmoduleChanges))
+	}
+	if info.HasSafetyCheckErrors {
+		return info.SafetyCheckErrors[0]
+	}
+	if info.HasUniqueCommits {
+		if squashed, _ := squash.IsSquashMerged(branch); !squashed {
+			return fmt.Sprintf("%d unique commits", info.UniqueCommitCount)
+		}
+	}
+	return ""
+}
+
 // String returns a human-readable string for the safety level.
 func (s SafetyLevel) String() string {
 	switch s {
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparseProfileKey records which grove profile a worktree was created with.
+// It is written with --worktree so every worktree keeps its own value.
+const sparseProfileKey = "grove.sparseProfile"
+
+// SparseCheckout describes a cone-mode sparse checkout.
+type SparseCheckout struct {
+	Profile string   // Profile name recorded for display (may be empty)
+	Paths   []string // Directories checked out in addition to top-level files
+}
+
+// SetSparse replaces a worktree's sparse-checkout directories (cone mode)
+// and records the profile name in its per-worktree config.
+func SetSparse(worktreePath string, sparse SparseCheckout) error {
+	args := append([]string{"sparse-checkout", "set", "--cone", "--"}, sparse.Paths...)
+	if _, err := runGitInDir(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to set sparse checkout: %w", err)
+	}
+	return setSparseProfile(worktreePath, sparse.Profile)
+}
+
+// WidenSparse adds directories to a sparse worktree and records the new
+// profile name. With no paths, sparse checkout is turned off and the whole
+// tree is checked out.
+func WidenSparse(worktreePath string, paths []string, profile string) error {
+	if len(paths) == 0 {
+		if _, err := runGitInDir(worktreePath, "sparse-checkout", "disable"); err != nil {
+			return fmt.Errorf("failed to disable sparse checkout: %w", err)
+		}
+		_, _ = runGitInDir(worktreePath, "config", "--worktree", "--unset", sparseProfileKey)
+		return nil
+	}
+
+	args := append([]string{"sparse-checkout", "add", "--"}, paths...)
+	if _, err := runGitInDir(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to widen sparse checkout: %w", err)
+	}
+	return setSparseProfile(worktreePath, profile)
+}
+
+func setSparseProfile(worktreePath, profile string) error {
+	if profile == "" {
+		return nil
+	}
+	if _, err := runGitInDir(worktreePath, "config", "--worktree", sparseProfileKey, profile); err != nil {
+		return fmt.Errorf("failed to record sparse profile: %w", err)
+	}
+	return nil
+}
+
+// GetSparseStatus returns the worktree's sparse checkout, or nil when it
+// checks out the whole tree.
+func GetSparseStatus(worktreePath string) (*SparseCheckout, error) {
+	// Exits non-zero when neither key is set, i.e. not sparse
+	output, err := runGitInDir(worktreePath, "config", "--get-regexp", `^(core\.sparsecheckout|grove\.sparseprofile)$`)
+	if err != nil {
+		return nil, nil
+	}
+
+	isSparse := false
+	sparse := &SparseCheckout{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "core.sparsecheckout":
+			isSparse = value == "true"
+		case "grove.sparseprofile":
+			sparse.Profile = value
+		}
+	}
+	if !isSparse {
+		return nil, nil
+	}
+
+	output, err = runGitInDir(worktreePath, "sparse-checkout", "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sparse checkout: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			sparse.Paths = append(sparse.Paths, line)
+		}
+	}
+	return sparse, nil
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,7 +10,9 @@ import (
 // StashEntry represents a single git stash entry.
 type StashEntry struct {
 	Index   int
-	Message string
+	Message string // As listed, e.g. "On main: wip"
+	Branch  string // Branch the stash was created on; empty when detached
+	Subject string // Message without the "On <branch>: " prefix
 }
 
 // ListStashes returns the list of stashes for the repository.
@@ -48,12 +51,39 @@ func ListStashes(worktreePath string) ([]StashEntry, error) {
 			continue
 		}
 
-		entries = append(entries, StashEntry{Index: idx, Message: msg})
+		branch, subject := parseStashMessage(msg)
+		entries = append(entries, StashEntry{Index: idx, Message: msg, Branch: branch, Subject: subject})
 	}
 
 	return entries, nil
 }
 
+// parseStashMessage splits a stash message such as "On main: wip" or
+// "WIP on main: abc1234 subject" into the branch it was created on and the
+// rest. Stashes made on a detached HEAD are "On (no branch): ..." and get
+// an empty branch.
+func parseStashMessage(msg string) (branch, subject string) {
+	rest, ok := strings.CutPrefix(msg, "WIP on ")
+	if !ok {
+		if rest, ok = strings.CutPrefix(msg, "On "); !ok {
+			return "", msg
+		}
+	}
+	branch, subject, ok = strings.Cut(rest, ": ")
+	if !ok {
+		return "", msg
+	}
+	if branch == "(no branch)" {
+		branch = ""
+	}
+	return branch, subject
+}
+
+// stashRef returns the ref of the stash entry at index.
+func stashRef(index int) string {
+	return fmt.Sprintf("stash@{%d}", index)
+}
+
 // CreateStash saves current changes to a new stash entry.
 func CreateStash(worktreePath, message string) (string, error) {
 	args := []string{"stash", "push"}
@@ -71,18 +101,54 @@ func CreateStash(worktreePath, message string) (string, error) {
 
 // PopStashAt applies and drops the stash entry at the given index.
 func PopStashAt(worktreePath string, index int) error {
-	_, err := runGitInDir(worktreePath, "stash", "pop", fmt.Sprintf("stash@{%d}", index))
+	_, err := runGitInDir(worktreePath, "stash", "pop", stashRef(index))
 	return err
 }
 
 // ApplyStash applies the stash entry at the given index without dropping it.
 func ApplyStash(worktreePath string, index int) error {
-	_, err := runGitInDir(worktreePath, "stash", "apply", fmt.Sprintf("stash@{%d}", index))
+	_, err := runGitInDir(worktreePath, "stash", "apply", stashRef(index))
 	return err
 }
 
 // DropStash removes the stash entry at the given index.
 func DropStash(worktreePath string, index int) error {
-	_, err := runGitInDir(worktreePath, "stash", "drop", fmt.Sprintf("stash@{%d}", index))
+	_, err := runGitInDir(worktreePath, "stash", "drop", stashRef(index))
 	return err
 }
+
+// StashDiff returns the diffstat and patch of the stash entry at index.
+func StashDiff(worktreePath string, index int) (string, error) {
+	return runGitInDir(worktreePath, "stash", "show", "--stat", "--patch", stashRef(index))
+}
+
+// CreateFromStash creates a worktree at path on a new branch made from the
+// stash entry at index, like "git stash branch": the branch starts at the
+// commit the stash was created on, and the stash is applied there and
+// dropped. Stashes are shared by all worktrees, so this works from any.
+func CreateFromStash(path, branch string, index int) error {
+	if BranchExists(branch) {
+		return fmt.Errorf("branch %q already exists", branch)
+	}
+	repo, err := prepareCreate(path, branch)
+	if err != nil {
+		return err
+	}
+
+	ref := stashRef(index)
+	if err := addWorktree(repo, path, nil, "worktree", "add", "--detach", path, ref+"^1"); err != nil {
+		return err
+	}
+	if _, err := runGitInDir(path, "stash", "branch", branch, ref); err != nil {
+		// The stash is only dropped on success, so undo the worktree and
+		// any branch git created for it
+		if rmErr := Remove(path, true); rmErr != nil {
+			return fmt.Errorf("stash could not be applied, and the worktree at %s could not be removed: %w", path, errors.Join(err, rmErr))
+		}
+		if BranchExists(branch) {
+			_ = DeleteBranch(branch, true)
+		}
+		return fmt.Errorf("stash could not be applied: %w", err)
+	}
+	return nil
+}
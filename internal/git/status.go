@@ -5,21 +5,37 @@ import (
 	"strings"
 )
 
-// GetDirtyStatus checks if a worktree has uncommitted changes.
+// GetDirtyStatus checks if a worktree has uncommitted changes. Changed
+// submodules count as changes.
 func GetDirtyStatus(worktreePath string) (isDirty bool, count int, err error) {
-	output, err := runGitInDir(worktreePath, "status", "--porcelain")
+	files, submodules, err := GetStatus(worktreePath)
 	if err != nil {
 		return false, 0, err
 	}
+	count = files + len(submodules)
+	return count > 0, count, nil
+}
 
-	output = strings.TrimSpace(output)
-	if output == "" {
-		return false, 0, nil
+// GetStatus returns the number of changed files (staged, unstaged or
+// untracked) and, separately, the submodules whose checkout differs from
+// what the worktree records.
+func GetStatus(worktreePath string) (files int, submodules []SubmoduleChange, err error) {
+	output, err := runGitInDir(worktreePath, "status", "--porcelain=v2")
+	if err != nil {
+		return 0, nil, err
 	}
 
-	// Count lines
-	lines := strings.Split(output, "\n")
-	return true, len(lines), nil
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		if change, ok := parseSubmoduleStatus(line); ok {
+			submodules = append(submodules, change)
+		} else {
+			files++
+		}
+	}
+	return files, submodules, nil
 }
 
 // GetUpstreamStatus returns how many commits a branch is ahead/behind its upstream.
@@ -0,0 +1,164 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SubmoduleChange describes a submodule whose checkout differs from the
+// commit recorded in the superproject.
+type SubmoduleChange struct {
+	Path       string
+	NewCommits bool // HEAD moved away from the recorded commit
+	Unpushed   bool // HEAD not on any remote ref; set by CheckSafety
+	Modified   bool // Changes to tracked files
+	Untracked  bool // Untracked files
+}
+
+// HasUncommittedWork reports whether the submodule holds changes that are
+// not committed anywhere.
+func (s SubmoduleChange) HasUncommittedWork() bool {
+	return s.Modified || s.Untracked
+}
+
+// HasUnsavedWork reports whether deleting the submodule checkout loses
+// work: uncommitted changes, or commits no remote has.
+func (s SubmoduleChange) HasUnsavedWork() bool {
+	return s.HasUncommittedWork() || s.Unpushed
+}
+
+// String returns e.g. "vendor/lib (new commits, modified)".
+func (s SubmoduleChange) String() string {
+	var kinds []string
+	if s.Unpushed {
+		kinds = append(kinds, "unpushed commits")
+	} else if s.NewCommits {
+		kinds = append(kinds, "new commits")
+	}
+	if s.Modified {
+		kinds = append(kinds, "modified")
+	}
+	if s.Untracked {
+		kinds = append(kinds, "untracked files")
+	}
+	return s.Path + " (" + strings.Join(kinds, ", ") + ")"
+}
+
+// parseSubmoduleStatus parses a "git status --porcelain=v2" line, returning
+// ok=false for anything other than a changed submodule.
+//
+//	1 XY sub mH mI mW hH hI path
+//	2 XY sub mH mI mW hH hI Xscore path<TAB>orig
+//	u XY sub m1 m2 m3 mW h1 h2 h3 path
+func parseSubmoduleStatus(line string) (SubmoduleChange, bool) {
+	var n int
+	switch {
+	case strings.HasPrefix(line, "1 "):
+		n = 9
+	case strings.HasPrefix(line, "2 "):
+		n = 10
+	case strings.HasPrefix(line, "u "):
+		n = 11
+	default:
+		return SubmoduleChange{}, false
+	}
+
+	fields := strings.SplitN(line, " ", n)
+	if len(fields) < n || len(fields[2]) != 4 || fields[2][0] != 'S' {
+		return SubmoduleChange{}, false
+	}
+	sub := fields[2]
+	path, _, _ := strings.Cut(fields[n-1], "\t")
+	return SubmoduleChange{
+		Path:       path,
+		NewCommits: sub[1] == 'C',
+		Modified:   sub[2] == 'M',
+		Untracked:  sub[3] == 'U',
+	}, true
+}
+
+// UpdateSubmodules initializes and checks out a worktree's submodules
+// recursively. With reference set, each top-level submodule that is checked
+// out in the main worktree borrows its objects from there (git clone
+// --reference), so they are not downloaded and stored twice.
+func UpdateSubmodules(worktreePath string, reference bool) error {
+	if reference {
+		if err := updateSubmodulesWithReference(worktreePath); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runGitInDir(worktreePath, "submodule", "update", "--init", "--recursive"); err != nil {
+		return fmt.Errorf("failed to update submodules: %w", err)
+	}
+	return nil
+}
+
+// updateSubmodulesWithReference initializes the top-level submodules that
+// the main worktree has checked out, one at a time since each needs its own
+// reference repository. Nested submodules are left to the recursive update.
+func updateSubmodulesWithReference(worktreePath string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+	if repo.IsBare {
+		return nil
+	}
+
+	for _, path := range submodulePaths(worktreePath) {
+		ref := filepath.Join(repo.MainWorktreeRoot, filepath.FromSlash(path))
+		if _, err := os.Stat(filepath.Join(ref, ".git")); err != nil {
+			continue
+		}
+		if _, err := runGitInDir(worktreePath, "submodule", "update", "--init", "--reference", ref, "--", path); err != nil {
+			return fmt.Errorf("failed to update submodule %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// submodulePaths lists the submodule paths declared in .gitmodules.
+func submodulePaths(worktreePath string) []string {
+	output, err := runGitInDir(worktreePath, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if _, path, ok := strings.Cut(line, " "); ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// submoduleHeadPushed reports whether the submodule's HEAD is reachable from
+// one of its remote refs. A linked worktree keeps its submodules' git dirs
+// under its own admin directory, so commits that exist nowhere else are
+// lost when the worktree is removed.
+func submoduleHeadPushed(worktreePath, path string) (bool, error) {
+	output, err := runGitInDir(filepath.Join(worktreePath, filepath.FromSlash(path)), "rev-list", "-n", "1", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(output) == "", nil
+}
+
+// hasSubmoduleCheckouts reports whether any of the worktree's submodules
+// are initialized.
+func hasSubmoduleCheckouts(worktreePath string) bool {
+	output, err := runGitInDir(worktreePath, "submodule", "status")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" && line[0] != '-' {
+			return true
+		}
+	}
+	return false
+}
@@ -3,12 +3,13 @@ package git
 import (
 	"bufio"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/henri123lemoine/grove/internal/debug"
 )
@@ -23,14 +24,25 @@ type Worktree struct {
 	Branch     string
 	IsCurrent  bool
 	IsMain     bool
-	IsDirty    bool
-	DirtyFiles int
-	IsDetached bool // True if HEAD is detached (not on a branch)
+	IsDirty    bool   // Any uncommitted change, including changed submodules
+	DirtyFiles int    // Changed files, not counting submodules
+	IsDetached bool   // True if HEAD is detached (not on a branch)
+	IsBare     bool   // True for the bare repository's own entry
+	IsLocked   bool   // True if locked with git worktree lock
+	LockReason string // Optional reason given when locking
+
+	// Submodules checked out at a different commit or with local changes
+	DirtySubmodules int
+
+	// Broken metadata, e.g. the directory was deleted or moved by hand
+	IsPrunable     bool
+	PrunableReason string
 
 	// Upstream tracking
-	HasUpstream bool // True if branch has upstream tracking configured
-	Ahead       int
-	Behind      int
+	HasUpstream  bool // True if branch has upstream tracking configured
+	UpstreamGone bool // True if the upstream branch was deleted on the remote
+	Ahead        int
+	Behind       int
 
 	// Safety info
 	IsMerged      bool
@@ -40,6 +52,15 @@ type Worktree struct {
 	LastCommitHash    string
 	LastCommitMessage string
 	LastCommitTime    string
+	CommitTime        time.Time // Committer date of HEAD, loaded in background
+
+	// Note and labels from grove's state dir, attached by the app
+	Note   string
+	Labels []string
+	Pin    int // Position among pinned worktrees (1-based, 0 = not pinned)
+
+	// Sparse checkout (nil = full checkout)
+	Sparse *SparseCheckout
 
 	// Internal
 	head string // The HEAD commit
@@ -112,7 +133,17 @@ func List() ([]Worktree, error) {
 // Only fetches dirty status for maximum speed. Other info is lazy-loaded.
 func enrichWorktree(wt *Worktree, _ *Repo) {
 	// Get dirty status - essential for list view (1 git command)
-	wt.IsDirty, wt.DirtyFiles, _ = GetDirtyStatus(wt.Path)
+	files, submodules, err := GetStatus(wt.Path)
+	if err == nil {
+		wt.DirtyFiles = files
+		wt.DirtySubmodules = len(submodules)
+		wt.IsDirty = files > 0 || len(submodules) > 0
+	}
+
+	// Sparse status - actions depend on it (1 git command, 2 when sparse)
+	if !wt.IsPrunable {
+		wt.Sparse, _ = GetSparseStatus(wt.Path)
+	}
 
 	// NOTE: Upstream, last commit, merged status, and unique commits are
 	// fetched on-demand to speed up initial load.
@@ -121,6 +152,12 @@ func enrichWorktree(wt *Worktree, _ *Repo) {
 // EnrichWorktreesUpstream fetches ahead/behind status for all worktrees.
 // Run this in background after initial load for progressive enhancement.
 func EnrichWorktreesUpstream(worktrees []Worktree) {
+	// One for-each-ref call covers gone upstreams for every branch
+	gone, _ := GetGoneBranches()
+	for i := range worktrees {
+		worktrees[i].UpstreamGone = gone[worktrees[i].Branch]
+	}
+
 	sem := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
 	for i := range worktrees {
@@ -138,6 +175,76 @@ func EnrichWorktreesUpstream(worktrees []Worktree) {
 	wg.Wait()
 }
 
+// EnrichWorktreesCommitTime sets CommitTime for every worktree with one git
+// call. Worktrees loaded from cache have no HEAD and are skipped.
+func EnrichWorktreesCommitTime(worktrees []Worktree) error {
+	byHead := make(map[string][]int)
+	args := []string{"log", "--no-walk=unsorted", "--format=%H %ct"}
+	for i, wt := range worktrees {
+		if wt.head == "" || wt.IsPrunable {
+			continue
+		}
+		if _, ok := byHead[wt.head]; !ok {
+			args = append(args, wt.head)
+		}
+		byHead[wt.head] = append(byHead[wt.head], i)
+	}
+	if len(byHead) == 0 {
+		return nil
+	}
+
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+	output, err := runGitInDir(repo.MainWorktreeRoot, args...)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		hash, ts, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		secs, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		for _, i := range byHead[hash] {
+			worktrees[i].CommitTime = time.Unix(secs, 0)
+		}
+	}
+	return nil
+}
+
+// EnrichWorktreesColumns fetches the last commit and merge status for list
+// columns that show them. Like EnrichWorktreesUpstream, run it in background.
+func EnrichWorktreesColumns(worktrees []Worktree, defaultBranch string, lastCommit, merged bool) {
+	if !lastCommit && !merged {
+		return
+	}
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i := range worktrees {
+		if worktrees[i].IsPrunable || worktrees[i].IsBare {
+			continue
+		}
+		wg.Add(1)
+		go func(wt *Worktree) {
+			sem <- struct{}{}        // acquire
+			defer func() { <-sem }() // release
+			defer wg.Done()
+			if lastCommit {
+				EnrichWorktreeDetail(wt)
+			}
+			if merged {
+				EnrichWorktreeSafety(wt, defaultBranch)
+			}
+		}(&worktrees[i])
+	}
+	wg.Wait()
+}
+
 // EnrichWorktreeDetail fetches additional info for detail panel display.
 // Called lazily when user opens detail view.
 func EnrichWorktreeDetail(wt *Worktree) {
@@ -188,11 +295,18 @@ func parseWorktreeList(output string) []Worktree {
 			current.Branch = strings.TrimPrefix(branch, "refs/heads/")
 		} else if line == "bare" && current != nil {
 			// Bare repo worktree - no branch
+			current.IsBare = true
+		} else if (line == "locked" || strings.HasPrefix(line, "locked ")) && current != nil {
+			current.IsLocked = true
+			current.LockReason = strings.TrimSpace(strings.TrimPrefix(line, "locked"))
+		} else if strings.HasPrefix(line, "prunable") && current != nil {
+			current.IsPrunable = true
+			current.PrunableReason = strings.TrimSpace(strings.TrimPrefix(line, "prunable"))
 		} else if line == "detached" && current != nil {
 			// Detached HEAD - mark as detached and use short hash for display
 			current.IsDetached = true
 			if current.head != "" && len(current.head) >= 7 {
-				current.Branch = current.head[:7] + " (detached)"
+				current.Branch = ShortHash(current.head) + " (detached)"
 			}
 		}
 	}
@@ -204,16 +318,86 @@ func parseWorktreeList(output string) []Worktree {
 	return worktrees
 }
 
-// Create creates a new worktree.
-func Create(path, branch string, isNewBranch bool, baseBranch string) error {
-	repo, err := GetRepo()
+// Create creates a new worktree. A non-nil sparse limits the checkout to
+// the profile's directories.
+func Create(path, branch string, isNewBranch bool, baseBranch string, sparse *SparseCheckout) error {
+	repo, err := prepareCreate(path, branch)
+	if err != nil {
+		return err
+	}
+
+	// Build command arguments
+	args := []string{"worktree", "add"}
+
+	if isNewBranch {
+		// Starting from a remote branch must not make it the upstream
+		args = append(args, "--no-track", "-b", branch, path)
+		if baseBranch != "" {
+			args = append(args, baseBranch)
+		}
+	} else {
+		args = append(args, path, branch)
+	}
+
+	return addWorktree(repo, path, sparse, args...)
+}
+
+// CreateTracking creates a worktree on a new local branch that tracks
+// remoteRef (e.g. "origin/feature-x").
+func CreateTracking(path, branch, remoteRef string, sparse *SparseCheckout) error {
+	repo, err := prepareCreate(path, branch)
+	if err != nil {
+		return err
+	}
+
+	return addWorktree(repo, path, sparse, "worktree", "add", "--track", "-b", branch, path, remoteRef)
+}
+
+// CreateDetached creates a worktree with a detached HEAD at rev, which may
+// be a tag, a commit SHA, or any revision expression such as HEAD~3.
+func CreateDetached(path, rev string, sparse *SparseCheckout) error {
+	repo, err := prepareCreate(path, "")
 	if err != nil {
 		return err
 	}
 
+	return addWorktree(repo, path, sparse, "worktree", "add", "--detach", path, rev)
+}
+
+// addWorktree runs a "git worktree add" command line. For sparse worktrees
+// the checkout is deferred with --no-checkout until the sparse patterns are
+// in place, so files outside the profile are never written.
+func addWorktree(repo *Repo, path string, sparse *SparseCheckout, args ...string) error {
+	if sparse != nil {
+		args = append([]string{args[0], args[1], "--no-checkout"}, args[2:]...)
+	}
+
+	if _, err := runGitInDir(repo.MainWorktreeRoot, args...); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+	if sparse == nil {
+		return nil
+	}
+
+	if err := SetSparse(path, *sparse); err != nil {
+		return err
+	}
+	if _, err := runGitInDir(path, "checkout"); err != nil {
+		return fmt.Errorf("failed to check out sparse worktree: %w", err)
+	}
+	return nil
+}
+
+// prepareCreate runs the checks shared by all worktree creation paths.
+func prepareCreate(path, branch string) (*Repo, error) {
+	repo, err := GetRepo()
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if path already exists
 	if _, err := os.Stat(path); err == nil {
-		return fmt.Errorf("path already exists: %s (try a different branch name or delete the existing directory)", path)
+		return nil, fmt.Errorf("path already exists: %s (try a different branch name or delete the existing directory)", path)
 	}
 
 	// Ensure the worktree directory is excluded from git tracking
@@ -223,26 +407,23 @@ func Create(path, branch string, isNewBranch bool, baseBranch string) error {
 	_, _ = runGitInDir(repo.MainWorktreeRoot, "worktree", "prune")
 
 	if err := checkCreateConflicts(path, branch); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Build command arguments
-	args := []string{"worktree", "add"}
+	return repo, nil
+}
 
-	if isNewBranch {
-		args = append(args, "-b", branch, path)
-		if baseBranch != "" {
-			args = append(args, baseBranch)
-		}
-	} else {
-		args = append(args, path, branch)
+// ConvertToBranch creates a new branch at a detached worktree's HEAD and
+// switches the worktree to it.
+func ConvertToBranch(worktreePath, branch string) error {
+	if BranchExists(branch) {
+		return fmt.Errorf("branch %q already exists", branch)
 	}
-
-	_, err = runGitInDir(repo.MainWorktreeRoot, args...)
+	_, err := runGitInDir(worktreePath, "switch", "-c", branch)
 	if err != nil {
-		return fmt.Errorf("failed to create worktree: %w", err)
+		return fmt.Errorf("failed to create branch: %w", err)
 	}
-
+	_, _ = ListAndCache()
 	return nil
 }
 
@@ -253,7 +434,19 @@ func checkCreateConflicts(path, branch string) error {
 		return fmt.Errorf("failed to list worktrees for preflight: %w", err)
 	}
 
+	if strings.ContainsAny(path, "{}") {
+		return fmt.Errorf("worktree path %s has unexpanded template variables (check general.worktree_path)", path)
+	}
+
 	targetPath := ResolvePath(path)
+	if repo, err := GetRepo(); err == nil {
+		for _, gitDir := range []string{repo.GitDir, filepath.Join(repo.MainWorktreeRoot, ".git")} {
+			if isWithinPath(ResolvePath(gitDir), targetPath) {
+				return fmt.Errorf("target path %s is inside the git directory %s", path, gitDir)
+			}
+		}
+	}
+
 	for _, wt := range worktrees {
 		wtPath := ResolvePath(wt.Path)
 
@@ -266,6 +459,10 @@ func checkCreateConflicts(path, branch string) error {
 		}
 
 		if wt.IsMain {
+			// Worktrees may live inside the main worktree, but never around it
+			if isWithinPath(targetPath, wtPath) {
+				return fmt.Errorf("target path %s contains the main worktree %s", path, wt.Path)
+			}
 			continue
 		}
 
@@ -288,6 +485,15 @@ func Remove(path string, force bool) error {
 		return err
 	}
 
+	// git refuses to remove worktrees with initialized submodules unless
+	// forced, even when everything in them is committed
+	if !force && hasSubmoduleCheckouts(path) {
+		if dirty, _, err := GetDirtyStatus(path); err != nil || dirty {
+			return fmt.Errorf("failed to remove worktree: %s has uncommitted changes", path)
+		}
+		force = true
+	}
+
 	args := []string{"worktree", "remove"}
 	if force {
 		args = append(args, "--force")
@@ -310,12 +516,90 @@ func Remove(path string, force bool) error {
 	return nil
 }
 
+// Move relocates a worktree with git worktree move, then removes any parent
+// directories left empty at the old location.
+func Move(oldPath, newPath string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+
+	if ResolvePath(oldPath) == ResolvePath(newPath) {
+		return nil
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("path already exists: %s", newPath)
+	}
+	if err := checkCreateConflicts(newPath, ""); err != nil {
+		return err
+	}
+
+	// git worktree move doesn't create missing parents (e.g. for feature/x)
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	ensureWorktreeDirExcluded(newPath, repo)
+
+	_, err = runGitInDir(repo.MainWorktreeRoot, "worktree", "move", oldPath, newPath)
+	if err != nil {
+		cleanupEmptyParentDirs(newPath)
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+
+	cleanupEmptyParentDirs(oldPath)
+	_, _ = ListAndCache()
+
+	return nil
+}
+
+// Lock locks a worktree so git won't prune, move, or remove it.
+// The reason is optional and shown by git worktree list.
+func Lock(path, reason string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, path)
+
+	if _, err := runGitInDir(repo.MainWorktreeRoot, args...); err != nil {
+		return fmt.Errorf("failed to lock worktree: %w", err)
+	}
+	_, _ = ListAndCache()
+	return nil
+}
+
+// Unlock unlocks a locked worktree.
+func Unlock(path string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGitInDir(repo.MainWorktreeRoot, "worktree", "unlock", path); err != nil {
+		return fmt.Errorf("failed to unlock worktree: %w", err)
+	}
+	_, _ = ListAndCache()
+	return nil
+}
+
 // ensureWorktreeDirExcluded adds the worktree directory to .git/info/exclude
 // if it's not already there. This prevents worktrees from showing as untracked files.
 func ensureWorktreeDirExcluded(worktreePath string, repo *Repo) {
+	// Worktrees outside the repo (e.g. ~/worktrees/...) need no exclude entry
+	root := ResolvePath(repo.MainWorktreeRoot)
+	target := ResolvePath(worktreePath)
+	if !isWithinPath(root, target) {
+		return
+	}
+
 	// Get the worktree directory (parent of the actual worktree path)
 	// e.g., if path is "/repo/.worktrees/feature", we want ".worktrees"
-	relPath, err := filepath.Rel(repo.MainWorktreeRoot, worktreePath)
+	relPath, err := filepath.Rel(root, target)
 	if err != nil {
 		return
 	}
@@ -332,7 +616,24 @@ func ensureWorktreeDirExcluded(worktreePath string, repo *Repo) {
 		return
 	}
 
+	addExcludeEntry(repo, worktreeDir+"/")
+}
+
+// EnsureExcluded adds a pattern to .git/info/exclude unless an equivalent
+// entry (with or without trailing slash) is already there. The exclude file
+// lives in the common git dir, so it applies to every worktree.
+func EnsureExcluded(pattern string) error {
+	repo, err := GetRepo()
+	if err != nil {
+		return err
+	}
+	addExcludeEntry(repo, pattern)
+	return nil
+}
+
+func addExcludeEntry(repo *Repo, pattern string) {
 	excludePath := filepath.Join(repo.GitDir, "info", "exclude")
+	bare := strings.TrimSuffix(pattern, "/")
 
 	// Read existing content
 	content, err := os.ReadFile(excludePath)
@@ -344,7 +645,7 @@ func ensureWorktreeDirExcluded(worktreePath string, repo *Repo) {
 	lines := strings.Split(string(content), "\n")
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
-		if trimmed == worktreeDir || trimmed == worktreeDir+"/" {
+		if trimmed == bare || trimmed == bare+"/" {
 			return // Already excluded
 		}
 	}
@@ -363,7 +664,7 @@ func ensureWorktreeDirExcluded(worktreePath string, repo *Repo) {
 	defer func() { _ = f.Close() }()
 
 	// Add newline before entry if file doesn't end with one
-	entry := worktreeDir + "/\n"
+	entry := pattern + "\n"
 	if len(content) > 0 && content[len(content)-1] != '\n' {
 		entry = "\n" + entry
 	}
@@ -440,167 +741,83 @@ func (w *Worktree) BranchShort() string {
 	return parts[len(parts)-1]
 }
 
-// CopyFiles copies files matching patterns from source to dest worktree.
-func CopyFiles(sourceDir, destDir string, patterns, ignores []string) error {
-	// Resolve source directory for path traversal validation
-	resolvedSource := ResolvePath(sourceDir)
-
-	for _, pattern := range patterns {
-		// Find files matching pattern
-		matches, err := filepath.Glob(filepath.Join(sourceDir, pattern))
-		if err != nil {
-			continue
-		}
-
-		for _, srcPath := range matches {
-			// Validate path is within source directory (prevent path traversal)
-			resolvedSrc := ResolvePath(srcPath)
-			if !isWithinPath(resolvedSource, resolvedSrc) {
-				continue
-			}
-
-			// Check if ignored
-			relPath, _ := filepath.Rel(sourceDir, srcPath)
-			if isIgnored(relPath, ignores) {
-				continue
-			}
-
-			// Determine destination path
-			destPath := filepath.Join(destDir, relPath)
-
-			// Copy file or directory
-			info, err := os.Stat(srcPath)
-			if err != nil {
-				continue
-			}
-
-			if info.IsDir() {
-				err = copyDir(srcPath, destPath, ignores)
-			} else {
-				err = copyFile(srcPath, destPath)
-			}
-			if err != nil {
-				return fmt.Errorf("failed to copy %s: %w", relPath, err)
-			}
-		}
-	}
-	return nil
-}
-
-// isIgnored checks if a path matches any ignore pattern.
-func isIgnored(path string, ignores []string) bool {
-	for _, pattern := range ignores {
-		// Handle ** patterns (e.g., node_modules/**)
-		if strings.HasSuffix(pattern, "/**") {
-			prefix := strings.TrimSuffix(pattern, "/**")
-			if path == prefix || strings.HasPrefix(path, prefix+string(filepath.Separator)) {
-				return true
-			}
-		}
-
-		// Standard filepath.Match against full path
-		matched, err := filepath.Match(pattern, path)
-		if err == nil && matched {
-			return true
-		}
-		// Also check against base name
-		matched, err = filepath.Match(pattern, filepath.Base(path))
-		if err == nil && matched {
-			return true
-		}
+// Prune removes stale worktree entries (worktrees that no longer exist on disk).
+// Entries for the worktrees in keep survive. git worktree prune has no way to
+// skip entries, so when some are kept the others are removed one by one
+// instead. Returns the number of pruned entries.
+func Prune(keep ...string) (int, error) {
+	repo, err := GetRepo()
+	if err != nil {
+		return 0, err
 	}
-	return false
-}
 
-// copyFile copies a single file.
-func copyFile(src, dst string) error {
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
-		return err
+	if len(keep) > 0 {
+		return pruneExcept(repo, keep)
 	}
 
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = srcFile.Close() }()
+	// Get current worktrees to count before
+	beforeOutput, _ := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
+	beforeCount := countWorktrees(beforeOutput)
 
-	srcInfo, err := srcFile.Stat()
+	// Run prune
+	_, err = runGitInDir(repo.MainWorktreeRoot, "worktree", "prune")
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("failed to prune worktrees: %w", err)
 	}
 
-	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
-	if err != nil {
-		return err
-	}
-	defer func() { _ = dstFile.Close() }()
+	// Get count after
+	afterOutput, _ := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
+	afterCount := countWorktrees(afterOutput)
 
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	return beforeCount - afterCount, nil
 }
 
-// copyDir copies a directory recursively.
-func copyDir(src, dst string, ignores []string) error {
-	srcInfo, err := os.Stat(src)
+// pruneExcept runs git worktree remove for each prunable worktree not in
+// keep. Locked entries are skipped, as git worktree prune would.
+func pruneExcept(repo *Repo, keep []string) (int, error) {
+	output, err := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
-		return err
-	}
-
-	entries, err := os.ReadDir(src)
-	if err != nil {
-		return err
+	kept := make(map[string]bool)
+	for _, path := range keep {
+		kept[filepath.Clean(path)] = true
 	}
 
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		dstPath := filepath.Join(dst, entry.Name())
-
-		if isIgnored(entry.Name(), ignores) {
+	pruned := 0
+	for _, wt := range parseWorktreeList(output) {
+		if !wt.IsPrunable || wt.IsLocked || kept[filepath.Clean(wt.Path)] {
 			continue
 		}
-
-		if entry.IsDir() {
-			if err := copyDir(srcPath, dstPath, ignores); err != nil {
-				return err
-			}
-		} else {
-			if err := copyFile(srcPath, dstPath); err != nil {
-				return err
-			}
+		if _, err := runGitInDir(repo.MainWorktreeRoot, "worktree", "remove", wt.Path); err != nil {
+			return pruned, fmt.Errorf("failed to prune %s: %w", wt.Path, err)
 		}
+		pruned++
 	}
-
-	return nil
+	return pruned, nil
 }
 
-// Prune removes stale worktree entries (worktrees that no longer exist on disk).
-// Returns the number of pruned entries.
-func Prune() (int, error) {
+// LivePaths returns the paths of the worktrees git still tracks, leaving
+// out entries git worktree prune would remove.
+func LivePaths() ([]string, error) {
 	repo, err := GetRepo()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-
-	// Get current worktrees to count before
-	beforeOutput, _ := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
-	beforeCount := countWorktrees(beforeOutput)
-
-	// Run prune
-	_, err = runGitInDir(repo.MainWorktreeRoot, "worktree", "prune")
+	output, err := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
 	if err != nil {
-		return 0, fmt.Errorf("failed to prune worktrees: %w", err)
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	// Get count after
-	afterOutput, _ := runGitInDir(repo.MainWorktreeRoot, "worktree", "list", "--porcelain")
-	afterCount := countWorktrees(afterOutput)
-
-	return beforeCount - afterCount, nil
+	var paths []string
+	for _, wt := range parseWorktreeList(output) {
+		if wt.IsPrunable && !wt.IsLocked {
+			continue
+		}
+		paths = append(paths, wt.Path)
+	}
+	return paths, nil
 }
 
 // countWorktrees counts the number of worktrees from porcelain output.
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const notesFile = "notes.json"
+
+// Note is the free-text note and labels attached to a worktree.
+type Note struct {
+	Text   string   `json:"text,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// IsEmpty reports whether the note has neither text nor labels.
+func (n Note) IsEmpty() bool {
+	return n.Text == "" && len(n.Labels) == 0
+}
+
+// String formats the note the way ParseNote reads it: labels as #tags
+// followed by the text.
+func (n Note) String() string {
+	parts := make([]string, 0, len(n.Labels)+1)
+	for _, label := range n.Labels {
+		parts = append(parts, "#"+label)
+	}
+	if n.Text != "" {
+		parts = append(parts, n.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseNote splits user input into labels (the #tags it starts with) and the
+// remaining text, so a # later on, as in "see PR #42", stays in the text.
+// Duplicate labels are dropped.
+func ParseNote(input string) Note {
+	var note Note
+	rest := strings.TrimSpace(input)
+	for {
+		word, after, _ := strings.Cut(rest, " ")
+		label, ok := strings.CutPrefix(word, "#")
+		if !ok || label == "" {
+			break
+		}
+		if !slices.Contains(note.Labels, label) {
+			note.Labels = append(note.Labels, label)
+		}
+		rest = strings.TrimSpace(after)
+	}
+	note.Text = rest
+	return note
+}
+
+type worktreeNotes struct {
+	Notes map[string]Note `json:"notes"` // Worktree path -> note
+}
+
+// LoadNotes returns the notes of all worktrees, keyed by path.
+func LoadNotes() map[string]Note {
+	var notes worktreeNotes
+	if err := load(notesFile, &notes); err != nil || notes.Notes == nil {
+		return map[string]Note{}
+	}
+	return notes.Notes
+}
+
+// SetNote attaches note to the worktree. An empty note removes it.
+func SetNote(worktreePath string, note Note) error {
+	var notes worktreeNotes
+	return update(notesFile, &notes, func() error {
+		if notes.Notes == nil {
+			notes.Notes = make(map[string]Note)
+		}
+		key := filepath.Clean(worktreePath)
+		if note.IsEmpty() {
+			delete(notes.Notes, key)
+		} else {
+			notes.Notes[key] = note
+		}
+		return nil
+	})
+}
+
+// moveNote carries a worktree's note over to its new path.
+func moveNote(oldPath, newPath string) error {
+	var notes worktreeNotes
+	return update(notesFile, &notes, func() error {
+		oldKey := filepath.Clean(oldPath)
+		if note, ok := notes.Notes[oldKey]; ok {
+			delete(notes.Notes, oldKey)
+			notes.Notes[filepath.Clean(newPath)] = note
+		}
+		return nil
+	})
+}
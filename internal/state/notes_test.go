@@ -0,0 +1,64 @@
+package state
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNote(t *testing.T) {
+	tests := []struct {
+		input  string
+		text   string
+		labels []string
+	}{
+		{"", "", nil},
+		{"waiting on review", "waiting on review", nil},
+		{"#wip  #auth fix the flow", "fix the flow", []string{"wip", "auth"}},
+		{"fix see PR #42", "fix see PR #42", nil},
+		{"#wip #wip # alone", "# alone", []string{"wip"}},
+	}
+	for _, tt := range tests {
+		note := ParseNote(tt.input)
+		if note.Text != tt.text || !slices.Equal(note.Labels, tt.labels) {
+			t.Errorf("ParseNote(%q) = %+v, want text %q labels %v", tt.input, note, tt.text, tt.labels)
+		}
+	}
+
+	note := Note{Text: "fix the flow", Labels: []string{"wip", "auth"}}
+	if got := ParseNote(note.String()); got.Text != note.Text || !slices.Equal(got.Labels, note.Labels) {
+		t.Errorf("ParseNote(String()) = %+v, want %+v", got, note)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if len(LoadNotes()) != 0 {
+		t.Fatal("expected no notes before saving")
+	}
+
+	note := Note{Text: "ship it", Labels: []string{"review"}}
+	if err := SetNote("/wt/a/", note); err != nil {
+		t.Fatalf("SetNote error: %v", err)
+	}
+	if got := LoadNotes()["/wt/a"]; got.Text != note.Text || !slices.Equal(got.Labels, note.Labels) {
+		t.Errorf("LoadNotes = %+v, want %+v", got, note)
+	}
+
+	// Notes follow moved worktrees
+	if err := MoveWorktree("/wt/a", "/wt/b"); err != nil {
+		t.Fatalf("MoveWorktree error: %v", err)
+	}
+	notes := LoadNotes()
+	if _, ok := notes["/wt/a"]; ok || notes["/wt/b"].Text != note.Text {
+		t.Errorf("note not moved, got %v", notes)
+	}
+
+	// An empty note removes it
+	if err := SetNote("/wt/b", Note{}); err != nil {
+		t.Fatalf("SetNote error: %v", err)
+	}
+	if len(LoadNotes()) != 0 {
+		t.Errorf("expected no notes after clearing, got %v", LoadNotes())
+	}
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const portsFile = "ports.json"
+
+// PortBlock is a contiguous range of ports reserved for one worktree.
+type PortBlock struct {
+	Repo  string `json:"repo"`
+	Start int    `json:"start"`
+	Size  int    `json:"size"`
+}
+
+// Port returns the i-th port of the block; Port(0) is the first.
+func (b PortBlock) Port(i int) int {
+	return b.Start + i
+}
+
+// End returns the last port of the block.
+func (b PortBlock) End() int {
+	return b.Start + b.Size - 1
+}
+
+func (b PortBlock) overlaps(start, size int) bool {
+	return b.Start < start+size && start < b.Start+b.Size
+}
+
+type portAllocations struct {
+	// Blocks maps cleaned worktree paths to their reserved ports.
+	Blocks map[string]PortBlock `json:"blocks"`
+}
+
+// AllocatePorts reserves a block of size ports for the worktree, returning
+// the existing block if one was already allocated with the same layout.
+// Blocks are laid out from base in steps of size and the lowest free slot
+// wins. live lists the worktrees git still tracks for repoRoot; the repo's
+// blocks for any other path are reclaimed first. A path missing on disk is
+// not enough, since an unmounted drive would lose its ports that way. A nil
+// live skips reclaiming.
+func AllocatePorts(repoRoot, worktreePath string, live []string, base, size, maxBlocks int) (PortBlock, error) {
+	if size < 1 || maxBlocks < 1 {
+		return PortBlock{}, fmt.Errorf("invalid port block layout (size %d, max blocks %d)", size, maxBlocks)
+	}
+	key := filepath.Clean(worktreePath)
+
+	var allocs portAllocations
+	var block PortBlock
+	err := update(portsFile, &allocs, func() error {
+		if allocs.Blocks == nil {
+			allocs.Blocks = make(map[string]PortBlock)
+		}
+		if live != nil {
+			tracked := make(map[string]bool, len(live))
+			for _, path := range live {
+				tracked[filepath.Clean(path)] = true
+			}
+			for path, b := range allocs.Blocks {
+				if b.Repo == repoRoot && !tracked[path] && path != key {
+					delete(allocs.Blocks, path)
+				}
+			}
+		}
+
+		if existing, ok := allocs.Blocks[key]; ok &&
+			existing.Size == size && existing.Start >= base && (existing.Start-base)%size == 0 {
+			block = existing
+			return nil
+		}
+		delete(allocs.Blocks, key)
+
+		for i := 0; i < maxBlocks; i++ {
+			start := base + i*size
+			if start+size-1 > 65535 {
+				break
+			}
+			free := true
+			for _, b := range allocs.Blocks {
+				if b.overlaps(start, size) {
+					free = false
+					break
+				}
+			}
+			if free {
+				block = PortBlock{Repo: repoRoot, Start: start, Size: size}
+				allocs.Blocks[key] = block
+				return nil
+			}
+		}
+		return fmt.Errorf("no free port block: all %d blocks from %d are in use", maxBlocks, base)
+	})
+	return block, err
+}
+
+// LookupPorts returns the block allocated to the worktree, if any.
+func LookupPorts(worktreePath string) (PortBlock, bool) {
+	var allocs portAllocations
+	if err := load(portsFile, &allocs); err != nil {
+		return PortBlock{}, false
+	}
+	block, ok := allocs.Blocks[filepath.Clean(worktreePath)]
+	return block, ok
+}
+
+// ReleasePorts frees the worktree's block. Releasing a worktree without an
+// allocation is not an error.
+func ReleasePorts(worktreePath string) error {
+	var allocs portAllocations
+	return update(portsFile, &allocs, func() error {
+		delete(allocs.Blocks, filepath.Clean(worktreePath))
+		return nil
+	})
+}
+
+// MovePorts carries a worktree's block over to its new path after a move.
+func MovePorts(oldPath, newPath string) error {
+	var allocs portAllocations
+	return update(portsFile, &allocs, func() error {
+		oldKey := filepath.Clean(oldPath)
+		if block, ok := allocs.Blocks[oldKey]; ok {
+			delete(allocs.Blocks, oldKey)
+			allocs.Blocks[filepath.Clean(newPath)] = block
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllocatePorts(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	wtA := filepath.Join(dir, "a")
+	wtB := filepath.Join(dir, "b")
+	for _, p := range []string{wtA, wtB} {
+		if err := os.Mkdir(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := AllocatePorts(dir, wtA, nil, 3000, 10, 5)
+	if err != nil {
+		t.Fatalf("AllocatePorts(a) error: %v", err)
+	}
+	if a.Start != 3000 || a.End() != 3009 || a.Port(1) != 3001 {
+		t.Errorf("block a = %+v, want 3000-3009", a)
+	}
+
+	b, err := AllocatePorts(dir, wtB, nil, 3000, 10, 5)
+	if err != nil {
+		t.Fatalf("AllocatePorts(b) error: %v", err)
+	}
+	if b.Start != 3010 {
+		t.Errorf("block b starts at %d, want 3010", b.Start)
+	}
+
+	// Allocation is idempotent
+	again, err := AllocatePorts(dir, wtA, nil, 3000, 10, 5)
+	if err != nil || again != a {
+		t.Errorf("re-allocating a = %+v, %v; want %+v", again, err, a)
+	}
+
+	if got, ok := LookupPorts(wtB); !ok || got != b {
+		t.Errorf("LookupPorts(b) = %+v, %v; want %+v", got, ok, b)
+	}
+
+	// Released blocks are reused
+	if err := ReleasePorts(wtA); err != nil {
+		t.Fatalf("ReleasePorts error: %v", err)
+	}
+	if _, ok := LookupPorts(wtA); ok {
+		t.Error("block a still allocated after release")
+	}
+	c, err := AllocatePorts(dir, wtA, nil, 3000, 10, 5)
+	if err != nil || c.Start != 3000 {
+		t.Errorf("re-allocating after release = %+v, %v; want start 3000", c, err)
+	}
+}
+
+func TestAllocatePortsExhaustedAndStale(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	wtA := filepath.Join(dir, "a")
+	wtB := filepath.Join(dir, "b")
+	live := []string{dir, wtA, wtB}
+
+	if _, err := AllocatePorts(dir, wtA, live, 4000, 5, 1); err != nil {
+		t.Fatalf("AllocatePorts(a) error: %v", err)
+	}
+	// a doesn't exist on disk, but git still tracks it, so it keeps its block
+	if _, err := AllocatePorts(dir, wtB, live, 4000, 5, 1); err == nil {
+		t.Error("expected error when all blocks are in use")
+	}
+
+	// Another repo's blocks are left alone
+	other := t.TempDir()
+	if _, err := AllocatePorts(other, filepath.Join(other, "c"), []string{other}, 4000, 5, 1); err == nil {
+		t.Error("reclaimed a block belonging to another repo")
+	}
+
+	// Once git no longer tracks a, its block is reclaimable
+	b, err := AllocatePorts(dir, wtB, []string{dir, wtB}, 4000, 5, 1)
+	if err != nil || b.Start != 4000 {
+		t.Errorf("AllocatePorts(b) after stale cleanup = %+v, %v; want start 4000", b, err)
+	}
+}
+
+func TestMovePorts(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old")
+	newPath := filepath.Join(dir, "new")
+	if err := os.Mkdir(oldPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := AllocatePorts(dir, oldPath, nil, 5000, 2, 10)
+	if err != nil {
+		t.Fatalf("AllocatePorts error: %v", err)
+	}
+	if err := MovePorts(oldPath, newPath); err != nil {
+		t.Fatalf("MovePorts error: %v", err)
+	}
+	if _, ok := LookupPorts(oldPath); ok {
+		t.Error("old path still has an allocation")
+	}
+	if got, ok := LookupPorts(newPath); !ok || got != block {
+		t.Errorf("LookupPorts(new) = %+v, %v; want %+v", got, ok, block)
+	}
+}
@@ -0,0 +1,57 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const recentFile = "recent.json"
+
+type recentOpens struct {
+	Opened map[string]time.Time `json:"opened"` // Worktree path -> last open
+}
+
+// RecordOpen notes that the worktree was opened now. Entries for worktrees
+// that no longer exist are dropped on the way.
+func RecordOpen(worktreePaths ...string) error {
+	var recent recentOpens
+	return update(recentFile, &recent, func() error {
+		if recent.Opened == nil {
+			recent.Opened = make(map[string]time.Time)
+		}
+		for path := range recent.Opened {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				delete(recent.Opened, path)
+			}
+		}
+		// Later paths count as more recent, even within one clock tick
+		now := time.Now()
+		for i, path := range worktreePaths {
+			recent.Opened[filepath.Clean(path)] = now.Add(time.Duration(i))
+		}
+		return nil
+	})
+}
+
+// RecentOpens returns when each worktree was last opened through grove.
+func RecentOpens() map[string]time.Time {
+	var recent recentOpens
+	if err := load(recentFile, &recent); err != nil || recent.Opened == nil {
+		return map[string]time.Time{}
+	}
+	return recent.Opened
+}
+
+// moveRecent carries a worktree's open history over to its new path.
+func moveRecent(oldPath, newPath string) error {
+	var recent recentOpens
+	return update(recentFile, &recent, func() error {
+		oldKey := filepath.Clean(oldPath)
+		if opened, ok := recent.Opened[oldKey]; ok {
+			delete(recent.Opened, oldKey)
+			recent.Opened[filepath.Clean(newPath)] = opened
+		}
+		return nil
+	})
+}
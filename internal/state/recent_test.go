@@ -0,0 +1,76 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordOpen(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	wtA := filepath.Join(dir, "a")
+	wtB := filepath.Join(dir, "b")
+	for _, p := range []string{wtA, wtB} {
+		if err := os.Mkdir(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(RecentOpens()) != 0 {
+		t.Fatal("expected no recent opens before recording")
+	}
+
+	// The current worktree is recorded first, the opened one last
+	if err := RecordOpen(wtA, wtB); err != nil {
+		t.Fatalf("RecordOpen error: %v", err)
+	}
+	recent := RecentOpens()
+	if !recent[wtB].After(recent[wtA]) {
+		t.Errorf("expected b after a, got %v", recent)
+	}
+
+	if err := RecordOpen(wtA); err != nil {
+		t.Fatalf("RecordOpen error: %v", err)
+	}
+	recent = RecentOpens()
+	if !recent[wtA].After(recent[wtB]) {
+		t.Errorf("expected a after b once reopened, got %v", recent)
+	}
+
+	// Deleted worktrees are forgotten
+	if err := os.Remove(wtB); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordOpen(wtA); err != nil {
+		t.Fatalf("RecordOpen error: %v", err)
+	}
+	if _, ok := RecentOpens()[wtB]; ok {
+		t.Error("deleted worktree still recorded")
+	}
+}
+
+func TestMoveWorktreeCarriesRecent(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old")
+	newPath := filepath.Join(dir, "new")
+	if err := os.Mkdir(oldPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RecordOpen(oldPath); err != nil {
+		t.Fatalf("RecordOpen error: %v", err)
+	}
+	opened := RecentOpens()[oldPath]
+	if err := MoveWorktree(oldPath, newPath); err != nil {
+		t.Fatalf("MoveWorktree error: %v", err)
+	}
+	recent := RecentOpens()
+	if _, ok := recent[oldPath]; ok {
+		t.Error("old path still recorded after move")
+	}
+	if !recent[newPath].Equal(opened) {
+		t.Errorf("recent[new] = %v, want %v", recent[newPath], opened)
+	}
+}
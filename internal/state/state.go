@@ -0,0 +1,109 @@
+// Package state persists grove's machine-local bookkeeping that must survive
+// across runs and be shared between repositories, such as port allocations.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// Dir returns grove's state directory: $XDG_STATE_HOME/grove, falling back
+// to ~/.local/state/grove.
+func Dir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "grove")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "grove")
+	}
+	return filepath.Join(home, ".local", "state", "grove")
+}
+
+// MoveWorktree carries everything grove keeps about a worktree (ports, note,
+// pin and open history) over to its new path.
+func MoveWorktree(oldPath, newPath string) error {
+	return errors.Join(
+		MovePorts(oldPath, newPath),
+		moveNote(oldPath, newPath),
+		movePin(oldPath, newPath),
+		moveRecent(oldPath, newPath),
+	)
+}
+
+// ForgetWorktree drops what grove keeps about a removed worktree.
+func ForgetWorktree(worktreePath string) error {
+	return errors.Join(
+		ReleasePorts(worktreePath),
+		SetNote(worktreePath, Note{}),
+		movePin(worktreePath, ""),
+	)
+}
+
+// update loads the JSON file name from the state dir into v, calls fn and
+// writes v back, holding an exclusive lock for the whole cycle so concurrent
+// grove processes cannot hand out the same resource twice.
+func update(name string, v any, fn func() error) error {
+	path := filepath.Join(Dir(), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	fileLock := flock.New(path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return err
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	if err := readJSON(path, v); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// load reads the JSON file name from the state dir into v under a shared
+// lock. A missing file leaves v untouched.
+func load(name string, v any) error {
+	path := filepath.Join(Dir(), name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	fileLock := flock.New(path + ".lock")
+	if err := fileLock.RLock(); err != nil {
+		return err
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	return readJSON(path, v)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
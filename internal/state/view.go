@@ -0,0 +1,77 @@
+package state
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+const viewFile = "view.json"
+
+// ListView is the worktree list layout remembered for a repository.
+type ListView struct {
+	Tree      bool     `json:"tree,omitempty"`      // Group worktrees by branch prefix
+	Collapsed []string `json:"collapsed,omitempty"` // Collapsed group prefixes
+	Pinned    []string `json:"pinned,omitempty"`    // Pinned worktree paths, in pin order
+}
+
+// isDefault reports whether the view is the layout grove starts with.
+func (v ListView) isDefault() bool {
+	return !v.Tree && len(v.Collapsed) == 0 && len(v.Pinned) == 0
+}
+
+type listViews struct {
+	Repos map[string]ListView `json:"repos"`
+}
+
+// LoadListView returns the list layout saved for the repository, or the
+// zero value when none was saved.
+func LoadListView(repoRoot string) ListView {
+	var views listViews
+	if err := load(viewFile, &views); err != nil {
+		return ListView{}
+	}
+	return views.Repos[filepath.Clean(repoRoot)]
+}
+
+// SaveListView remembers the list layout for the repository.
+func SaveListView(repoRoot string, view ListView) error {
+	var views listViews
+	return update(viewFile, &views, func() error {
+		if views.Repos == nil {
+			views.Repos = make(map[string]ListView)
+		}
+		key := filepath.Clean(repoRoot)
+		if view.isDefault() {
+			delete(views.Repos, key)
+		} else {
+			views.Repos[key] = view
+		}
+		return nil
+	})
+}
+
+// movePin carries a worktree's pin over to its new path, or unpins it when
+// newPath is empty.
+func movePin(oldPath, newPath string) error {
+	var views listViews
+	return update(viewFile, &views, func() error {
+		oldKey := filepath.Clean(oldPath)
+		for root, view := range views.Repos {
+			i := slices.Index(view.Pinned, oldKey)
+			if i < 0 {
+				continue
+			}
+			if newPath == "" {
+				view.Pinned = slices.Delete(view.Pinned, i, i+1)
+			} else {
+				view.Pinned[i] = filepath.Clean(newPath)
+			}
+			if view.isDefault() {
+				delete(views.Repos, root)
+			} else {
+				views.Repos[root] = view
+			}
+		}
+		return nil
+	})
+}
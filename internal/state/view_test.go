@@ -0,0 +1,60 @@
+package state
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListView(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if got := LoadListView("/repo/a"); got.Tree || len(got.Collapsed) != 0 {
+		t.Errorf("LoadListView with no state = %+v, want zero value", got)
+	}
+
+	view := ListView{Tree: true, Collapsed: []string{"feat/auth", "fix"}}
+	if err := SaveListView("/repo/a/", view); err != nil {
+		t.Fatalf("SaveListView error: %v", err)
+	}
+	got := LoadListView("/repo/a")
+	if !got.Tree || !slices.Equal(got.Collapsed, view.Collapsed) {
+		t.Errorf("LoadListView = %+v, want %+v", got, view)
+	}
+	if other := LoadListView("/repo/b"); other.Tree {
+		t.Error("view leaked to another repository")
+	}
+
+	// Saving the default layout forgets the repository
+	if err := SaveListView("/repo/a", ListView{}); err != nil {
+		t.Fatalf("SaveListView error: %v", err)
+	}
+	if got := LoadListView("/repo/a"); got.Tree {
+		t.Errorf("LoadListView after reset = %+v, want zero value", got)
+	}
+}
+
+func TestPinsFollowWorktrees(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	view := ListView{Pinned: []string{"/wt/a", "/wt/b"}}
+	if err := SaveListView("/repo", view); err != nil {
+		t.Fatalf("SaveListView error: %v", err)
+	}
+
+	if err := MoveWorktree("/wt/a", "/wt/c"); err != nil {
+		t.Fatalf("MoveWorktree error: %v", err)
+	}
+	if got := LoadListView("/repo").Pinned; !slices.Equal(got, []string{"/wt/c", "/wt/b"}) {
+		t.Errorf("pins after move = %v, want [/wt/c /wt/b]", got)
+	}
+
+	if err := ForgetWorktree("/wt/c"); err != nil {
+		t.Fatalf("ForgetWorktree error: %v", err)
+	}
+	if err := ForgetWorktree("/wt/b"); err != nil {
+		t.Fatalf("ForgetWorktree error: %v", err)
+	}
+	if got := LoadListView("/repo").Pinned; len(got) != 0 {
+		t.Errorf("pins after removal = %v, want none", got)
+	}
+}
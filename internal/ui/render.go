@@ -2,9 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
 	"github.com/henri123lemoine/grove/internal/config"
 	"github.com/henri123lemoine/grove/internal/git"
 )
@@ -24,8 +29,35 @@ const (
 	StateStash
 	StateSelectLayout
 	StatePruneConfirm
+	StatePushing
+	StatePushConfirmForce
+	StateBranches
+	StateBranchDeleteConfirm
+	StateBranchRename
+	StateBranchSetUpstream
+	StatePruneGoneConfirm
+	StateMove
+	StateLock
+	StateCreateRemoteClash
+	StateCreateTemplate
+	StateSelectSparse
+	StateWidenSparse
+	StateNote
+	StatePalette
+	StateStashApplyTo
+	StateStashBranch
 )
 
+// KeyHints are the configured keys named in the footers of screens other
+// than the list.
+type KeyHints struct {
+	Yes, No, IncludePinned                                                string
+	Confirm, Cancel                                                       string
+	Reuse, NewName                                                        string
+	StashPop, StashApply, StashApplyTo, StashBranch, StashDrop            string
+	BranchCreate, BranchDelete, BranchPrune, BranchRename, BranchUpstream string
+}
+
 // HelpBinding represents a keybinding for help display.
 type HelpBinding struct {
 	Keys string
@@ -42,7 +74,8 @@ type HelpSection struct {
 type RenderParams struct {
 	State               int
 	Worktrees           []git.Worktree
-	Cursor              int
+	Rows                []ListRow // Tree mode rows; nil lists Worktrees flat
+	Cursor              int       // Index into Rows (or Worktrees when flat)
 	ViewOffset          int
 	VisibleCount        int
 	Width               int
@@ -53,6 +86,8 @@ type RenderParams struct {
 	Config              *config.Config
 	FilterInput         string
 	FilterValue         string
+	FilterQualifiers    []string // Active qualifiers, e.g. "is:dirty"
+	FilterInvalid       []string // Qualifier terms that could not be parsed
 	CreateInput         string
 	DeleteWorktree      *git.Worktree
 	SafetyInfo          *git.SafetyInfo
@@ -62,23 +97,65 @@ type RenderParams struct {
 	BaseViewOffset      int
 	VisibleBranchCount  int
 	CreateBranch        string
+	CreateRemote        string // Remote branch the new local branch will track
+	CreateValidation    string // Why the create input isn't a valid branch name
+	CreateSuggestion    string // Slugified name offered on tab
+	CreateHint          string // What enter does with an existing branch, tag or remote name
+	HasBranchTemplates  bool
+	TemplatePreview     string // Branch template with the fields filled so far
+	TemplateField       string // Template field being prompted for
+	TemplateInput       string
 	ShowDetail          bool
 	RenameWorktree      *git.Worktree
 	RenameInput         string
+	MoveWorktree        *git.Worktree
+	MoveInput           string
+	LockWorktree        *git.Worktree
+	LockInput           string
+	NoteWorktree        *git.Worktree
+	NoteInput           string
+	PaletteInput        string
+	PaletteEntries      []HelpBinding
+	PaletteCursor       int
 	StashWorktree       *git.Worktree
 	StashEntries        []git.StashEntry
 	StashCursor         int
+	StashOrigins        []string // Worktree path of each stash's branch, if any
+	StashDiff           string
+	StashDiffErr        error
+	StashTargets        []git.Worktree
+	StashTargetCursor   int
+	StashBranchInput    string
 	LayoutWorktree      *git.Worktree
 	LayoutCursor        int
 	SpinnerFrame        string
 	HelpSections        []HelpSection
+	Keys                KeyHints
+	PendingKeys         string // Keys typed so far of a key sequence
 	PendingWindowsCount int
 	PendingWindowsName  string // "window" for tmux, "tab" for zellij
 	ConfigWarnings      []string
-	LastPruneCount      int           // For displaying prune feedback
-	DeletedBranch       string        // Branch to potentially delete after worktree removal
-	SortMode            string        // Current sort order
-	CachedColumnWidths  *ColumnWidths // Pre-calculated column widths (optional)
+	LastPruneCount      int               // For displaying prune feedback
+	CopyProgress        *git.CopyProgress // Post-create copy in progress (nil = none)
+	DeletedBranch       string            // Branch to potentially delete after worktree removal
+	SortMode            string            // Current sort order
+	CachedColumnWidths  *ColumnWidths     // Pre-calculated column widths (optional)
+	Marked              map[string]bool   // Worktree paths marked for bulk actions
+	PushTargets         []git.Worktree    // Worktrees awaiting force-push confirmation
+	BranchDetails       []git.BranchInfo
+	BranchCursor        int
+	BranchViewOffset    int
+	BranchesLoading     bool
+	BranchInput         string
+	BranchDeleteTargets []git.BranchInfo
+	BranchForceConfirm  bool // Second confirmation for branches that need -D
+	GoneBranches        []git.GoneBranch
+	GoneLoading         bool
+	IncludePinned       bool // Prune flows also act on pinned worktrees
+	SparseCursor        int
+	PendingCreate       string // Worktree waiting on the sparse profile picker
+	WidenWorktree       *git.Worktree
+	WidenInput          string
 }
 
 // MinWidth is the absolute minimum terminal width we try to support.
@@ -93,33 +170,129 @@ const (
 	StashMsgMaxLen  = 50 // Max length for stash messages
 )
 
+// Column is one configured list column.
+type Column struct {
+	Name     string
+	MaxWidth int // Width cap in terminal cells (0 = no cap)
+}
+
+// defaultColumns matches the list layout before columns were configurable.
+var defaultColumns = []string{"pin", "branch", "dirty", "upstream", "lock", "labels", "note"}
+
+// defaultColumnCaps keeps free-text columns from crowding out the rest when
+// the config gives no width hint.
+var defaultColumnCaps = map[string]int{
+	"branch":      50,
+	"note":        40,
+	"last_commit": CommitMsgMaxLen,
+	"path":        60,
+}
+
+// flexibleColumns can be shrunk below their content width to fit the
+// terminal, in the order they give up space.
+var flexibleColumns = []string{"path", "last_commit", "note", "branch"}
+
+// minColumnWidth is the narrowest a flexible column is shrunk to.
+const minColumnWidth = 8
+
 // ColumnWidths holds pre-calculated widths for table-like alignment.
 type ColumnWidths struct {
-	Branch int // Max branch name length
+	Columns []Column
+	Widths  []int // Display width of each column; 0 hides an empty column
 }
 
-// CalculateColumnWidths computes column widths across all worktrees.
-func CalculateColumnWidths(worktrees []git.Worktree) ColumnWidths {
-	var widths ColumnWidths
-	for _, wt := range worktrees {
-		branchLen := len(wt.Branch)
-		if branchLen > widths.Branch {
-			widths.Branch = branchLen
+// ParseColumns resolves the configured list columns, skipping invalid
+// entries (Validate reports them).
+func ParseColumns(cfg *config.Config) []Column {
+	specs := defaultColumns
+	if cfg != nil && len(cfg.UI.Columns) > 0 {
+		specs = cfg.UI.Columns
+	}
+	columns := make([]Column, 0, len(specs))
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		name, maxWidth, err := config.ParseColumn(spec)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if maxWidth == 0 {
+			maxWidth = defaultColumnCaps[name]
 		}
+		columns = append(columns, Column{Name: name, MaxWidth: maxWidth})
+	}
+	return columns
+}
+
+// CalculateColumnWidths computes column widths across all worktrees, using
+// the tree labels of rows when given. Widths are measured in terminal cells,
+// so wide and combining runes align.
+func CalculateColumnWidths(worktrees []git.Worktree, rows []ListRow, cfg *config.Config) ColumnWidths {
+	columns := ParseColumns(cfg)
+	widths := ColumnWidths{Columns: columns, Widths: make([]int, len(columns))}
+	if rows == nil {
+		rows = flatRows(len(worktrees))
 	}
-	// Cap branch width to avoid extreme cases
-	if widths.Branch > 50 {
-		widths.Branch = 50
+	for _, row := range rows {
+		if row.IsGroup() {
+			continue
+		}
+		for i, col := range columns {
+			w := lipgloss.Width(listCell(worktrees[row.Index], row.Label, col.Name, col.MaxWidth, false, cfg))
+			if w > widths.Widths[i] {
+				widths.Widths[i] = w
+			}
+		}
 	}
 	return widths
 }
 
-// truncateMsg truncates a message to maxLen, adding "..." if needed.
+// Fit shrinks flexible columns so a row fits in width cells.
+func (c ColumnWidths) Fit(width int) ColumnWidths {
+	// Cursor and mark take two cells; columns are separated by two spaces
+	total := 2
+	visible := 0
+	for _, w := range c.Widths {
+		if w > 0 {
+			total += w
+			visible++
+		}
+	}
+	if visible > 1 {
+		total += 2 * (visible - 1)
+	}
+	if total <= width {
+		return c
+	}
+
+	fitted := ColumnWidths{Columns: c.Columns, Widths: slices.Clone(c.Widths)}
+	for _, name := range flexibleColumns {
+		for i, col := range fitted.Columns {
+			if col.Name != name || fitted.Widths[i] <= minColumnWidth {
+				continue
+			}
+			shrink := min(total-width, fitted.Widths[i]-minColumnWidth)
+			fitted.Widths[i] -= shrink
+			total -= shrink
+		}
+		if total <= width {
+			break
+		}
+	}
+	return fitted
+}
+
+// truncateMsg truncates a message to maxLen cells, adding "..." if needed.
 func truncateMsg(msg string, maxLen int) string {
-	if len(msg) > maxLen {
-		return msg[:maxLen-3] + "..."
+	return runewidth.Truncate(msg, maxLen, "...")
+}
+
+// truncateCell truncates column text to maxWidth cells (0 = no limit).
+func truncateCell(text string, maxWidth int) string {
+	if maxWidth <= 0 {
+		return text
 	}
-	return msg
+	return runewidth.Truncate(text, maxWidth, "…")
 }
 
 // Render renders the full UI.
@@ -158,6 +331,38 @@ func Render(p RenderParams) string {
 		return renderSelectLayout(p)
 	case StatePruneConfirm:
 		return renderPruneConfirm(p)
+	case StatePushing:
+		return renderPushing(p)
+	case StatePushConfirmForce:
+		return renderPushConfirmForce(p)
+	case StateBranches:
+		return renderBranches(p)
+	case StateBranchDeleteConfirm:
+		return renderBranchDeleteConfirm(p)
+	case StateBranchRename, StateBranchSetUpstream:
+		return renderBranchInput(p)
+	case StatePruneGoneConfirm:
+		return renderPruneGoneConfirm(p)
+	case StateMove:
+		return renderMove(p)
+	case StateLock:
+		return renderLock(p)
+	case StateCreateRemoteClash:
+		return renderRemoteClash(p)
+	case StateCreateTemplate:
+		return renderCreateTemplate(p)
+	case StateSelectSparse:
+		return renderSelectSparse(p)
+	case StateWidenSparse:
+		return renderWidenSparse(p)
+	case StateNote:
+		return renderNote(p)
+	case StatePalette:
+		return renderPalette(p)
+	case StateStashApplyTo:
+		return renderStashApplyTo(p)
+	case StateStashBranch:
+		return renderStashBranch(p)
 	default:
 		return renderList(p)
 	}
@@ -201,6 +406,11 @@ func renderList(p RenderParams) string {
 		b.WriteString(HelpStyle.Render("(press any key to dismiss)") + "\n\n")
 	}
 
+	// Copy progress (shown while files are copied into a new worktree)
+	if p.CopyProgress != nil {
+		b.WriteString(CopyProgressLine(*p.CopyProgress) + "\n\n")
+	}
+
 	// Prune feedback (shown after prune operation)
 	if p.LastPruneCount > 0 {
 		msg := fmt.Sprintf("Pruned %d stale worktree entries", p.LastPruneCount)
@@ -227,16 +437,21 @@ func renderList(p RenderParams) string {
 	if p.CachedColumnWidths != nil {
 		colWidths = *p.CachedColumnWidths
 	} else {
-		colWidths = CalculateColumnWidths(p.Worktrees)
+		colWidths = CalculateColumnWidths(p.Worktrees, p.Rows, p.Config)
+	}
+
+	rows := p.Rows
+	if rows == nil {
+		rows = flatRows(len(p.Worktrees))
 	}
 
 	// Calculate visible range
 	startIdx := p.ViewOffset
 	endIdx := p.ViewOffset + p.VisibleCount
-	if endIdx > len(p.Worktrees) {
-		endIdx = len(p.Worktrees)
+	if endIdx > len(rows) {
+		endIdx = len(rows)
 	}
-	if startIdx >= len(p.Worktrees) {
+	if startIdx >= len(rows) {
 		startIdx = 0
 	}
 
@@ -247,12 +462,17 @@ func renderList(p RenderParams) string {
 
 	// Worktree list - only render visible items
 	for i := startIdx; i < endIdx; i++ {
-		wt := p.Worktrees[i]
+		row := rows[i]
 		isSelected := i == p.Cursor
-		b.WriteString(renderWorktreeEntry(wt, isSelected, contentWidth, p.Config, colWidths))
-		// Show detail panel for selected item if enabled
-		if isSelected && p.ShowDetail {
-			b.WriteString(renderDetailPanel(wt, contentWidth))
+		if row.IsGroup() {
+			b.WriteString(renderGroupRow(row, isSelected))
+		} else {
+			wt := p.Worktrees[row.Index]
+			b.WriteString(renderWorktreeEntry(wt, row.Label, isSelected, p.Marked[wt.Path], contentWidth, p.Config, colWidths))
+			// Show detail panel for selected item if enabled
+			if isSelected && p.ShowDetail {
+				b.WriteString(renderDetailPanel(wt, contentWidth))
+			}
 		}
 		if i < endIdx-1 {
 			b.WriteString("\n")
@@ -260,81 +480,218 @@ func renderList(p RenderParams) string {
 	}
 
 	// Show scroll indicator if items below
-	if endIdx < len(p.Worktrees) {
-		b.WriteString("\n" + PathStyle.Render(fmt.Sprintf("  ↓ %d more below", len(p.Worktrees)-endIdx)))
+	if endIdx < len(rows) {
+		b.WriteString("\n" + PathStyle.Render(fmt.Sprintf("  ↓ %d more below", len(rows)-endIdx)))
 	}
 
 	// Footer
 	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
 	helpText := compactHelp(
-		"enter open • n new • d delete • r rename • f fetch • / filter • o sort • tab detail • ? help • q quit",
-		"enter•n•d•r•f•/•o•tab•?•q",
+		"enter open • n new • d delete • r rename • f fetch • p push • b branches • / filter • o sort • tab detail • ? help • q quit",
+		"enter•n•d•r•f•p•b•/•o•tab•?•q",
 		p.Width,
 	)
+	if p.PendingKeys != "" {
+		helpText = p.PendingKeys + " … (esc cancel)"
+	}
 	b.WriteString(HelpStyle.Render(helpText))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
 
-// renderWorktreeEntry renders a single worktree as a single line.
-func renderWorktreeEntry(wt git.Worktree, selected bool, width int, cfg *config.Config, colWidths ColumnWidths) string {
-	var parts []string
-
-	// Cursor indicator
-	cursor := "  "
+// renderWorktreeEntry renders a single worktree as a single line. A non-empty
+// label replaces the branch name, for tree mode.
+func renderWorktreeEntry(wt git.Worktree, label string, selected, marked bool, width int, cfg *config.Config, colWidths ColumnWidths) string {
+	// Cursor indicator, followed by the mark indicator
+	cursor := " "
 	if selected {
-		cursor = SelectedStyle.Render("› ")
+		cursor = SelectedStyle.Render("›")
 	} else if wt.IsCurrent {
-		cursor = CurrentStyle.Render("• ")
+		cursor = CurrentStyle.Render("•")
 	}
-
-	// Branch name (padded for alignment)
-	branch := wt.Branch
-	if branch == "" {
-		branch = "(detached)"
-	}
-	// Pad branch to align status columns
-	paddedBranch := branch
-	if len(branch) < colWidths.Branch {
-		paddedBranch = branch + strings.Repeat(" ", colWidths.Branch-len(branch))
-	}
-	if selected {
-		parts = append(parts, cursor+SelectedStyle.Render(paddedBranch))
+	if marked {
+		cursor += SelectedStyle.Render("*")
 	} else {
-		parts = append(parts, cursor+BranchStyle.Render(paddedBranch))
+		cursor += " "
 	}
 
-	// Status indicators (only essential info fetched on initial load)
-	var statusParts []string
-
-	// Dirty indicator
-	if wt.IsDirty {
-		statusParts = append(statusParts, DirtyStyle.Render(fmt.Sprintf("✗%d", wt.DirtyFiles)))
-	} else {
-		statusParts = append(statusParts, CleanStyle.Render("✓"))
+	colWidths = colWidths.Fit(width)
+	var cells []string
+	for i, col := range colWidths.Columns {
+		colWidth := colWidths.Widths[i]
+		if colWidth == 0 {
+			continue
+		}
+		cell := listCell(wt, label, col.Name, colWidth, selected, cfg)
+		// Pad for alignment; the last column needs no trailing spaces
+		if pad := colWidth - lipgloss.Width(cell); pad > 0 && i < len(colWidths.Columns)-1 {
+			cell += strings.Repeat(" ", pad)
+		}
+		cells = append(cells, cell)
 	}
 
-	// Ahead/Behind with arrows (respects config)
-	showUpstream := true
-	if cfg != nil {
-		showUpstream = cfg.UI.ShowUpstream
-	}
-	if showUpstream {
+	return strings.TrimRight(cursor+strings.Join(cells, "  "), " ")
+}
+
+// listCell renders one column of a worktree row. Free-text columns are
+// truncated to maxWidth cells before styling.
+func listCell(wt git.Worktree, label, column string, maxWidth int, selected bool, cfg *config.Config) string {
+	switch column {
+	case "branch":
+		branch := label
+		if branch == "" {
+			branch = wt.Branch
+		}
+		if branch == "" {
+			branch = "(detached)"
+		}
+		branch = truncateCell(branch, maxWidth)
+		if selected {
+			return SelectedStyle.Render(branch)
+		}
+		return BranchStyle.Render(branch)
+
+	case "dirty":
+		// Dirty indicator, with changed submodules shown separately
+		var parts []string
+		if wt.DirtyFiles > 0 {
+			parts = append(parts, DirtyStyle.Render(fmt.Sprintf("✗%d", wt.DirtyFiles)))
+		}
+		if wt.DirtySubmodules > 0 {
+			parts = append(parts, DirtyStyle.Render(fmt.Sprintf("%s%d", SymbolSubmod, wt.DirtySubmodules)))
+		}
+		if wt.IsPrunable {
+			parts = append(parts, DangerStyle.Render("prunable"))
+		} else if !wt.IsDirty {
+			parts = append(parts, CleanStyle.Render("✓"))
+		}
+		return strings.Join(parts, " ")
+
+	case "upstream":
+		// Ahead/Behind with arrows (respects config)
+		if cfg != nil && !cfg.UI.ShowUpstream {
+			return ""
+		}
+		var parts []string
 		if wt.Behind > 0 {
-			statusParts = append(statusParts, BehindStyle.Render(fmt.Sprintf("↓%d", wt.Behind)))
+			parts = append(parts, BehindStyle.Render(fmt.Sprintf("↓%d", wt.Behind)))
 		}
 		if wt.Ahead > 0 {
-			statusParts = append(statusParts, AheadStyle.Render(fmt.Sprintf("↑%d", wt.Ahead)))
+			parts = append(parts, AheadStyle.Render(fmt.Sprintf("↑%d", wt.Ahead)))
+		}
+		if wt.UpstreamGone {
+			parts = append(parts, DangerStyle.Render("× gone"))
+		}
+		return strings.Join(parts, " ")
+
+	case "lock":
+		if !wt.IsLocked {
+			return ""
+		}
+		lock := SymbolLocked
+		if wt.LockReason != "" {
+			lock += " " + truncateMsg(wt.LockReason, 30)
 		}
+		return LockedStyle.Render(truncateCell(lock, maxWidth))
+
+	case "pin":
+		// Pins 1-9 show the digit that opens them
+		switch {
+		case wt.Pin == 0:
+			return ""
+		case wt.Pin <= 9:
+			return PinnedStyle.Render(fmt.Sprintf("%s%d", SymbolPinned, wt.Pin))
+		default:
+			return PinnedStyle.Render(SymbolPinned)
+		}
+
+	case "labels":
+		return RenderLabels(wt.Labels)
+
+	case "note":
+		return PathStyle.Render(truncateCell(wt.Note, maxWidth))
+
+	case "age":
+		return PathStyle.Render(compactAge(wt.LastCommitTime))
+
+	case "last_commit":
+		return CommitStyle.Render(truncateCell(wt.LastCommitMessage, maxWidth))
+
+	case "merged":
+		if wt.IsMain || !wt.IsMerged {
+			return ""
+		}
+		return MergedStyle.Render("merged")
+
+	case "path":
+		return PathStyle.Render(truncateCell(displayPath(wt.Path), maxWidth))
 	}
+	return ""
+}
 
-	// Merged status and unique commits are shown in detail panel (Tab) only
+// compactAge shortens a git relative date for the age column,
+// e.g. "3 weeks ago" becomes "3w" and "1 year, 2 months ago" becomes "1y".
+func compactAge(relTime string) string {
+	fields := strings.Fields(relTime)
+	if len(fields) < 2 {
+		return relTime
+	}
+	unit := strings.TrimSuffix(strings.TrimSuffix(fields[1], ","), "s")
+	switch unit {
+	case "second":
+		return fields[0] + "s"
+	case "minute":
+		return fields[0] + "m"
+	case "hour":
+		return fields[0] + "h"
+	case "day":
+		return fields[0] + "d"
+	case "week":
+		return fields[0] + "w"
+	case "month":
+		return fields[0] + "mo"
+	case "year":
+		return fields[0] + "y"
+	}
+	return relTime
+}
 
-	parts = append(parts, strings.Join(statusParts, " "))
+// displayPath abbreviates the home directory to "~".
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return filepath.Join("~", rest)
+	}
+	return path
+}
 
-	// Commit info is shown in detail panel (Tab) only, not in list view
+// CopyProgressLine renders the status line for a running post-create copy.
+func CopyProgressLine(cp git.CopyProgress) string {
+	msg := "Copying files into new worktree..."
+	if cp.Files > 0 {
+		msg = fmt.Sprintf("Copying files: %d (%s)  %s", cp.Files, formatBytes(cp.Bytes), cp.Path)
+	}
+	return PathStyle.Render(msg)
+}
 
-	return strings.Join(parts, "  ")
+// formatBytes formats a byte count with a binary unit, e.g. "56.2 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 // renderDetailPanel renders the expanded detail panel for a worktree.
@@ -356,10 +713,8 @@ func renderDetailPanel(wt git.Worktree, width int) string {
 	// Helper to render a row with proper padding and truncation
 	renderRow := func(label, value string, style func(string) string) string {
 		maxValueLen := innerWidth - len(label) - 2 // -2 for spaces
-		if len(value) > maxValueLen {
-			value = value[:maxValueLen-3] + "..."
-		}
-		padding := innerWidth - len(label) - len(value)
+		value = truncateMsg(value, maxValueLen)
+		padding := innerWidth - len(label) - runewidth.StringWidth(value)
 		if padding < 0 {
 			padding = 0
 		}
@@ -378,16 +733,70 @@ func renderDetailPanel(wt git.Worktree, width int) string {
 	// Branch
 	b.WriteString(renderRow("Branch:   ", wt.Branch, identity))
 
+	// Note and labels
+	if wt.Note != "" {
+		b.WriteString(renderRow("Note:     ", wt.Note, identity))
+	}
+	if len(wt.Labels) > 0 {
+		tags := make([]string, len(wt.Labels))
+		for i, label := range wt.Labels {
+			tags[i] = "#" + label
+		}
+		// Color each tag that survived truncation
+		colorTags := func(s string) string {
+			words := strings.Split(s, " ")
+			for i, w := range words {
+				if label, ok := strings.CutPrefix(w, "#"); ok && label != "" {
+					words[i] = LabelStyle(label).Render(w)
+				}
+			}
+			return strings.Join(words, " ")
+		}
+		b.WriteString(renderRow("Labels:   ", strings.Join(tags, " "), colorTags))
+	}
+
 	// Status
+	var status []string
+	if wt.DirtyFiles > 0 {
+		status = append(status, fmt.Sprintf("%d uncommitted files", wt.DirtyFiles))
+	}
+	if wt.DirtySubmodules > 0 {
+		status = append(status, fmt.Sprintf("%d changed submodules", wt.DirtySubmodules))
+	}
 	statusStr := "clean"
-	if wt.IsDirty {
-		statusStr = fmt.Sprintf("%d uncommitted files", wt.DirtyFiles)
+	if len(status) > 0 {
+		statusStr = strings.Join(status, ", ")
 	}
 	b.WriteString(renderRow("Status:   ", statusStr, identity))
 
+	if wt.IsPrunable {
+		b.WriteString(renderRow("Broken:   ", wt.PrunableReason+" (run grove adopt)", identity))
+	}
+
+	// Lock
+	if wt.IsLocked {
+		lockStr := "locked"
+		if wt.LockReason != "" {
+			lockStr += ": " + wt.LockReason
+		}
+		b.WriteString(renderRow("Lock:     ", lockStr, func(s string) string { return LockedStyle.Render(s) }))
+	}
+
+	// Sparse checkout
+	if wt.Sparse != nil {
+		profile := wt.Sparse.Profile
+		if profile == "" {
+			profile = "custom"
+		}
+		sparseStr := profile + ": " + strings.Join(wt.Sparse.Paths, ", ")
+		b.WriteString(renderRow("Sparse:   ", sparseStr, identity))
+	}
+
 	// Upstream
 	upstreamStr := "no upstream"
-	if wt.HasUpstream {
+	if wt.UpstreamGone {
+		upstreamStr = "gone (deleted on remote)"
+	} else if wt.HasUpstream {
 		if wt.Ahead > 0 || wt.Behind > 0 {
 			upstreamStr = fmt.Sprintf("↑%d ahead, ↓%d behind", wt.Ahead, wt.Behind)
 		} else {
@@ -425,11 +834,52 @@ func renderCreate(p RenderParams) string {
 	b.WriteString(HeaderStyle.Render("NEW WORKTREE") + "\n")
 	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
 
-	b.WriteString("Branch name:\n")
+	if p.CreateRemote != "" {
+		b.WriteString("Tracking: " + RemoteTagStyle.Render(p.CreateRemote) + "\n\n")
+		b.WriteString("New local branch name:\n")
+	} else {
+		b.WriteString("Branch name " + PathStyle.Render("(or tag / commit / HEAD~n for a detached worktree)") + ":\n")
+	}
 	b.WriteString(p.CreateInput + "\n")
 
+	if p.CreateValidation != "" {
+		b.WriteString(ErrorStyle.Render("✗ "+p.CreateValidation) + "\n")
+	}
+	if p.CreateHint != "" {
+		b.WriteString(HelpStyle.Render("→ "+p.CreateHint) + "\n")
+	}
+	if p.CreateSuggestion != "" {
+		b.WriteString(HelpStyle.Render("tab → ") + BranchStyle.Render(p.CreateSuggestion) + "\n")
+	}
+
+	help := "enter confirm • tab complete • esc cancel"
+	if p.HasBranchTemplates {
+		help = "enter confirm • tab complete • ctrl+t template • esc cancel"
+	}
 	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
-	b.WriteString(HelpStyle.Render("enter confirm • esc cancel"))
+	b.WriteString(HelpStyle.Render(compactHelp(help, "enter • tab • esc", p.Width)))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderCreateTemplate renders the branch template field prompts.
+func renderCreateTemplate(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("NEW BRANCH FROM TEMPLATE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString("Branch: " + BranchStyle.Render(p.TemplatePreview) + "\n\n")
+	if p.TemplateField == "slug" {
+		b.WriteString(p.TemplateField + " " + PathStyle.Render("(paste a title, it will be slugified)") + ":\n")
+	} else {
+		b.WriteString(p.TemplateField + ":\n")
+	}
+	b.WriteString(p.TemplateInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render(compactHelp("enter next field • ctrl+t next template • esc back", "enter • ctrl+t • esc", p.Width)))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
@@ -544,13 +994,16 @@ func renderDelete(p RenderParams) string {
 		if info.MergeStatusKnown && info.IsMerged {
 			b.WriteString("• Branch merged to default\n")
 		}
-		b.WriteString("\n" + HelpStyle.Render("y confirm • n cancel"))
+		b.WriteString("\n" + HelpStyle.Render(p.Keys.Yes+" confirm • "+p.Keys.No+" cancel"))
 
 	case git.SafetyLevelWarning:
 		b.WriteString(DirtyStyle.Render("⚠ Warning") + "\n\n")
 		if info.HasUncommittedChanges {
 			b.WriteString(fmt.Sprintf("• %d uncommitted changes\n", info.UncommittedFileCount))
 		}
+		for _, sub := range info.SubmoduleChanges {
+			b.WriteString(fmt.Sprintf("• submodule %s\n", sub))
+		}
 		if info.HasUnpushedCommits {
 			b.WriteString(fmt.Sprintf("• %d unpushed commits\n", info.UnpushedCommitCount))
 		}
@@ -562,11 +1015,22 @@ func renderDelete(p RenderParams) string {
 				b.WriteString(fmt.Sprintf("• %s\n", msg))
 			}
 		}
-		b.WriteString("\n" + HelpStyle.Render("y confirm • n cancel"))
+		b.WriteString("\n" + HelpStyle.Render(p.Keys.Yes+" confirm • "+p.Keys.No+" cancel"))
 
 	case git.SafetyLevelDanger:
 		b.WriteString(DangerStyle.Render("⚠ DANGER: Data will be lost!") + "\n\n")
-		b.WriteString(fmt.Sprintf("%d commits exist only on this branch:\n\n", info.UniqueCommitCount))
+		if info.HasUncommittedChanges {
+			b.WriteString(fmt.Sprintf("• %d uncommitted changes\n", info.UncommittedFileCount))
+		}
+		for _, sub := range info.SubmoduleChanges {
+			b.WriteString(fmt.Sprintf("• submodule %s\n", sub))
+		}
+		if info.HasUniqueCommits {
+			if info.HasLocalChanges() {
+				b.WriteString("\n")
+			}
+			b.WriteString(fmt.Sprintf("%d commits exist only on this branch:\n\n", info.UniqueCommitCount))
+		}
 		for i, commit := range info.UniqueCommits {
 			if i >= 5 {
 				b.WriteString(fmt.Sprintf("  ... and %d more\n", len(info.UniqueCommits)-5))
@@ -587,7 +1051,7 @@ func renderDelete(p RenderParams) string {
 			b.WriteString(p.DeleteInput + "\n")
 			b.WriteString("\n" + HelpStyle.Render("esc cancel"))
 		} else {
-			b.WriteString("\n" + HelpStyle.Render("y confirm • n cancel"))
+			b.WriteString("\n" + HelpStyle.Render(p.Keys.Yes+" confirm • "+p.Keys.No+" cancel"))
 		}
 	}
 
@@ -615,7 +1079,7 @@ func renderDeleteConfirmCloseWindow(p RenderParams) string {
 
 	b.WriteString(fmt.Sprintf("Found %d %s with this worktree path.\n\n", p.PendingWindowsCount, windowNamePlural))
 	b.WriteString("Would you like to close " + SelectedStyle.Render(windowNamePlural) + "?\n\n")
-	b.WriteString(HelpStyle.Render("y close • n keep • esc cancel"))
+	b.WriteString(HelpStyle.Render(p.Keys.Yes + " close • " + p.Keys.No + " keep • esc cancel"))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
@@ -631,11 +1095,27 @@ func renderDeleteConfirmBranch(p RenderParams) string {
 	b.WriteString("Worktree has been deleted.\n\n")
 	b.WriteString("Would you also like to delete the branch?\n\n")
 	b.WriteString("Branch: " + SelectedStyle.Render(p.DeletedBranch) + "\n\n")
-	b.WriteString(HelpStyle.Render("y delete branch • n keep branch • esc cancel"))
+	b.WriteString(HelpStyle.Render(p.Keys.Yes + " delete branch • " + p.Keys.No + " keep branch • esc cancel"))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
 
+// renderFilterHint shows the active filter qualifiers, or the qualifier
+// syntax while none are used.
+func renderFilterHint(p RenderParams) string {
+	if len(p.FilterQualifiers) == 0 && len(p.FilterInvalid) == 0 {
+		return HelpStyle.Render("is:dirty is:merged is:ahead age:>14d label:x has:upstream • -is:x negates")
+	}
+	parts := make([]string, 0, len(p.FilterQualifiers)+len(p.FilterInvalid))
+	for _, term := range p.FilterQualifiers {
+		parts = append(parts, SelectedStyle.Render(term))
+	}
+	for _, term := range p.FilterInvalid {
+		parts = append(parts, DangerStyle.Render(term+" (invalid)"))
+	}
+	return HelpStyle.Render("where ") + strings.Join(parts, HelpStyle.Render(" and "))
+}
+
 // renderFilter renders the filter mode.
 func renderFilter(p RenderParams) string {
 	var b strings.Builder
@@ -643,6 +1123,7 @@ func renderFilter(p RenderParams) string {
 
 	b.WriteString(HeaderStyle.Render("FILTER") + "  ")
 	b.WriteString(p.FilterInput + "\n")
+	b.WriteString(renderFilterHint(p) + "\n")
 	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
 
 	if len(p.Worktrees) == 0 {
@@ -653,16 +1134,21 @@ func renderFilter(p RenderParams) string {
 		if p.CachedColumnWidths != nil {
 			colWidths = *p.CachedColumnWidths
 		} else {
-			colWidths = CalculateColumnWidths(p.Worktrees)
+			colWidths = CalculateColumnWidths(p.Worktrees, p.Rows, p.Config)
+		}
+
+		rows := p.Rows
+		if rows == nil {
+			rows = flatRows(len(p.Worktrees))
 		}
 
 		// Calculate visible range
 		startIdx := p.ViewOffset
 		endIdx := p.ViewOffset + p.VisibleCount
-		if endIdx > len(p.Worktrees) {
-			endIdx = len(p.Worktrees)
+		if endIdx > len(rows) {
+			endIdx = len(rows)
 		}
-		if startIdx >= len(p.Worktrees) {
+		if startIdx >= len(rows) {
 			startIdx = 0
 		}
 
@@ -672,16 +1158,21 @@ func renderFilter(p RenderParams) string {
 		}
 
 		for i := startIdx; i < endIdx; i++ {
-			wt := p.Worktrees[i]
-			b.WriteString(renderWorktreeEntry(wt, i == p.Cursor, contentWidth, p.Config, colWidths))
+			row := rows[i]
+			if row.IsGroup() {
+				b.WriteString(renderGroupRow(row, i == p.Cursor))
+			} else {
+				wt := p.Worktrees[row.Index]
+				b.WriteString(renderWorktreeEntry(wt, row.Label, i == p.Cursor, p.Marked[wt.Path], contentWidth, p.Config, colWidths))
+			}
 			if i < endIdx-1 {
 				b.WriteString("\n")
 			}
 		}
 
 		// Show scroll indicator if items below
-		if endIdx < len(p.Worktrees) {
-			b.WriteString("\n" + PathStyle.Render(fmt.Sprintf("  ↓ %d more below", len(p.Worktrees)-endIdx)))
+		if endIdx < len(rows) {
+			b.WriteString("\n" + PathStyle.Render(fmt.Sprintf("  ↓ %d more below", len(rows)-endIdx)))
 		}
 	}
 
@@ -728,18 +1219,57 @@ func renderHelp(p RenderParams) string {
 		}
 	}
 
+	b.WriteString("\n" + renderThemePreview())
+
 	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
 	b.WriteString(HelpStyle.Render("Press any key to close"))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
 
+// renderThemePreview shows the active palette and how list elements look
+// with it, so theme edits can be checked from the help screen.
+func renderThemePreview() string {
+	var b strings.Builder
+	b.WriteString(BranchStyle.Render("Theme: "+activeThemeName) + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", 40)) + "\n")
+
+	swatches := []struct {
+		name  string
+		color lipgloss.Color
+	}{
+		{"primary", activePalette.Primary}, {"secondary", activePalette.Secondary},
+		{"success", activePalette.Success}, {"warning", activePalette.Warning},
+		{"danger", activePalette.Danger}, {"muted", activePalette.Muted},
+		{"highlight", activePalette.Highlight}, {"text", activePalette.Text},
+		{"purple", activePalette.Purple},
+	}
+	for i, s := range swatches {
+		if i%5 == 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(lipgloss.NewStyle().Foreground(s.color).Render("██") + " " + s.name + "  ")
+		if i%5 == 4 || i == len(swatches)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("  " + SelectedStyle.Render(SymbolCursor+" selected") + "  " +
+		DirtyStyle.Render("✗3") + " " + AheadStyle.Render(SymbolAhead+"2") + " " +
+		BehindStyle.Render(SymbolBehind+"1") + "  " + DangerStyle.Render("danger") + "\n")
+	return b.String()
+}
+
 // renderRename renders the rename branch flow.
 func renderRename(p RenderParams) string {
 	var b strings.Builder
 	contentWidth := p.Width - 4
 
-	b.WriteString(HeaderStyle.Render("RENAME BRANCH") + "\n")
+	title, prompt := "RENAME BRANCH", "New name:"
+	if p.RenameWorktree != nil && p.RenameWorktree.IsDetached {
+		title, prompt = "CONVERT TO BRANCH", "Branch name:"
+	}
+	b.WriteString(HeaderStyle.Render(title) + "\n")
 	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
 
 	if p.RenameWorktree == nil {
@@ -747,7 +1277,7 @@ func renderRename(p RenderParams) string {
 	}
 
 	b.WriteString("Current: " + PathStyle.Render(p.RenameWorktree.Branch) + "\n\n")
-	b.WriteString("New name:\n")
+	b.WriteString(prompt + "\n")
 	b.WriteString(p.RenameInput + "\n")
 
 	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
@@ -756,6 +1286,198 @@ func renderRename(p RenderParams) string {
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
 
+// renderMove renders the move worktree flow.
+func renderMove(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("MOVE WORKTREE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.MoveWorktree == nil {
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	b.WriteString("Branch:  " + BranchStyle.Render(p.MoveWorktree.Branch) + "\n")
+	b.WriteString("Current: " + PathStyle.Render(p.MoveWorktree.Path) + "\n\n")
+	b.WriteString("New path (relative to repo root or absolute):\n")
+	b.WriteString(p.MoveInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("enter confirm • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderRemoteClash renders the prompt shown when a remote branch's local
+// name is already taken.
+func renderRemoteClash(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("LOCAL BRANCH EXISTS") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString("Remote:  " + RemoteTagStyle.Render(p.CreateRemote) + "\n")
+	b.WriteString("Local:   " + BranchStyle.Render(p.CreateBranch) + " already exists\n\n")
+	b.WriteString("r  reuse the existing local branch, tracking " + p.CreateRemote + "\n")
+	b.WriteString("n  pick a new name for a branch tracking " + p.CreateRemote + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render(p.Keys.Reuse + " reuse • " + p.Keys.NewName + " new name • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderLock renders the lock reason prompt.
+func renderLock(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("LOCK WORKTREE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.LockWorktree == nil {
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	b.WriteString("Branch: " + BranchStyle.Render(p.LockWorktree.Branch) + "\n")
+	b.WriteString("Path:   " + PathStyle.Render(p.LockWorktree.Path) + "\n\n")
+	b.WriteString("Locked worktrees are skipped by prune and can't be deleted or moved.\n\n")
+	b.WriteString("Reason:\n")
+	b.WriteString(p.LockInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("enter lock • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderNote renders the note editor.
+func renderNote(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("WORKTREE NOTE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.NoteWorktree == nil {
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	b.WriteString("Branch: " + BranchStyle.Render(p.NoteWorktree.Branch) + "\n")
+	b.WriteString("Path:   " + PathStyle.Render(p.NoteWorktree.Path) + "\n\n")
+	b.WriteString("Leading #tags become labels, e.g. " + RenderLabels([]string{"review"}) + ".\n")
+	b.WriteString("Clear the text to remove the note.\n\n")
+	b.WriteString("Note:\n")
+	b.WriteString(p.NoteInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("enter save • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderPalette renders the command palette.
+func renderPalette(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("COMMAND PALETTE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString(p.PaletteInput + "\n\n")
+
+	if len(p.PaletteEntries) == 0 {
+		b.WriteString(PathStyle.Render("No matching actions.") + "\n")
+	}
+
+	// Keep the cursor in view when there are more entries than rows
+	visible := max(p.Height-12, 1)
+	start := 0
+	if p.PaletteCursor >= visible {
+		start = p.PaletteCursor - visible + 1
+	}
+	end := min(start+visible, len(p.PaletteEntries))
+
+	for i := start; i < end; i++ {
+		entry := p.PaletteEntries[i]
+		if i == p.PaletteCursor {
+			b.WriteString(SelectedStyle.Render("› "+entry.Desc) + " " + PathStyle.Render(entry.Keys) + "\n")
+		} else {
+			b.WriteString("  " + entry.Desc + " " + PathStyle.Render(entry.Keys) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("↑/↓ select • enter run • esc close"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderSelectSparse renders the sparse profile picker shown before a
+// worktree is created.
+func renderSelectSparse(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("SELECT SPARSE PROFILE") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString("Creating: " + SelectedStyle.Render(p.PendingCreate) + "\n\n")
+
+	var profiles []config.SparseProfile
+	if p.Config != nil {
+		profiles = p.Config.Worktree.SparseProfiles
+	}
+	option := func(i int, name, desc string) {
+		if i == p.SparseCursor {
+			b.WriteString(SelectedStyle.Render("› ") + SelectedStyle.Render(name) + " " + PathStyle.Render(desc) + "\n")
+		} else {
+			b.WriteString("  " + BranchStyle.Render(name) + " " + PathStyle.Render(desc) + "\n")
+		}
+	}
+	for i, profile := range profiles {
+		desc := profile.Description
+		if desc == "" {
+			desc = strings.Join(profile.Paths, ", ")
+		}
+		option(i, profile.Name, desc)
+	}
+	option(len(profiles), "Full", "Check out the whole tree")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("↑/↓ select • enter create • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderWidenSparse renders the input for widening a sparse checkout.
+func renderWidenSparse(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("WIDEN SPARSE CHECKOUT") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.WidenWorktree == nil {
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	b.WriteString("Branch: " + BranchStyle.Render(p.WidenWorktree.Branch) + "\n")
+	if sparse := p.WidenWorktree.Sparse; sparse != nil {
+		b.WriteString("Paths:  " + PathStyle.Render(strings.Join(sparse.Paths, ", ")) + "\n")
+	}
+	b.WriteString("\nAdd a profile name or directories (space or comma separated).\n")
+	b.WriteString("Enter * to check out the whole tree.\n\n")
+	b.WriteString(p.WidenInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("tab complete • enter widen • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
 // renderStash renders the stash management view.
 func renderStash(p RenderParams) string {
 	var b strings.Builder
@@ -780,17 +1502,122 @@ func renderStash(p RenderParams) string {
 				cursor = SelectedStyle.Render("› ")
 			}
 			stashRef := fmt.Sprintf("stash@{%d}", entry.Index)
-			msg := truncateMsg(entry.Message, StashMsgMaxLen)
+			msg := truncateMsg(entry.Subject, StashMsgMaxLen)
+			origin := "detached"
+			if entry.Branch != "" {
+				origin = "on " + entry.Branch
+			}
+			if i < len(p.StashOrigins) && p.StashOrigins[i] != "" {
+				origin += " · " + p.StashOrigins[i]
+			}
 			if i == p.StashCursor {
-				b.WriteString(cursor + SelectedStyle.Render(stashRef) + " " + msg + "\n")
+				b.WriteString(cursor + SelectedStyle.Render(stashRef) + " " + msg + " " + PathStyle.Render(origin) + "\n")
 			} else {
-				b.WriteString(cursor + StashStyle.Render(stashRef) + " " + PathStyle.Render(msg) + "\n")
+				b.WriteString(cursor + StashStyle.Render(stashRef) + " " + PathStyle.Render(msg+" "+origin) + "\n")
 			}
 		}
+
+		// Preview of the selected stash in the remaining height
+		b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+		maxLines := max(p.Height-len(p.StashEntries)-14, 3)
+		switch {
+		case p.StashDiffErr != nil:
+			b.WriteString(ErrorStyle.Render("Could not load diff: "+strings.TrimSpace(p.StashDiffErr.Error())) + "\n")
+		case p.StashDiff == "":
+			b.WriteString(PathStyle.Render("Loading diff...") + "\n")
+		default:
+			b.WriteString(renderDiff(p.StashDiff, maxLines, contentWidth))
+		}
 	}
 
 	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
-	b.WriteString(HelpStyle.Render("p pop • a apply • d drop • esc cancel"))
+	help := fmt.Sprintf("%s pop • %s apply • %s apply to… • %s branch • %s drop • esc cancel",
+		p.Keys.StashPop, p.Keys.StashApply, p.Keys.StashApplyTo, p.Keys.StashBranch, p.Keys.StashDrop)
+	short := strings.Join([]string{p.Keys.StashPop, p.Keys.StashApply, p.Keys.StashApplyTo, p.Keys.StashBranch, p.Keys.StashDrop, "esc"}, "•")
+	b.WriteString(HelpStyle.Render(compactHelp(help, short, p.Width)))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderDiff renders up to maxLines of a diffstat and patch, colored by
+// line kind and cut to width.
+func renderDiff(diff string, maxLines, width int) string {
+	var b strings.Builder
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		if i == maxLines {
+			b.WriteString(PathStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-maxLines)) + "\n")
+			break
+		}
+		line = truncateMsg(strings.ReplaceAll(line, "\t", "    "), width)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "):
+			line = BranchStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			line = CommitStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = CleanStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = DangerStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// renderStashApplyTo renders the worktree picker for applying a stash
+// elsewhere.
+func renderStashApplyTo(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("APPLY STASH TO") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.StashCursor < len(p.StashEntries) {
+		entry := p.StashEntries[p.StashCursor]
+		b.WriteString("Stash: " + StashStyle.Render(fmt.Sprintf("stash@{%d}", entry.Index)) + " " + truncateMsg(entry.Subject, StashMsgMaxLen) + "\n\n")
+	}
+
+	for i, wt := range p.StashTargets {
+		if i == p.StashTargetCursor {
+			b.WriteString(SelectedStyle.Render("› "+wt.Branch) + " " + PathStyle.Render(wt.ShortPath()))
+		} else {
+			b.WriteString("  " + BranchStyle.Render(wt.Branch) + " " + PathStyle.Render(wt.ShortPath()))
+		}
+		if wt.IsDirty {
+			b.WriteString(" " + DirtyStyle.Render(SymbolDirty))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nThe stash is kept; drop it once it applies cleanly.\n")
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("↑/↓ select • " + p.Keys.Confirm + " apply • " + p.Keys.Cancel + " back"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderStashBranch renders the branch name prompt for turning a stash into
+// a branch and worktree.
+func renderStashBranch(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("BRANCH FROM STASH") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.StashCursor < len(p.StashEntries) {
+		entry := p.StashEntries[p.StashCursor]
+		b.WriteString("Stash: " + StashStyle.Render(fmt.Sprintf("stash@{%d}", entry.Index)) + " " + truncateMsg(entry.Subject, StashMsgMaxLen) + "\n\n")
+	}
+	b.WriteString("Creates a worktree on a new branch at the commit the stash was made on,\n")
+	b.WriteString("applies the stash there and drops it.\n\n")
+	b.WriteString("Branch name:\n")
+	b.WriteString(p.StashBranchInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("enter create • esc back"))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
@@ -849,6 +1676,299 @@ func renderSelectLayout(p RenderParams) string {
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
 
+// renderPushing renders the pushing state.
+func renderPushing(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("PUSHING") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+	b.WriteString(p.SpinnerFrame + " Pushing to remote...\n")
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderPushConfirmForce renders the force-with-lease confirmation for diverged branches.
+func renderPushConfirmForce(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("FORCE PUSH?") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString(DirtyStyle.Render("⚠ These branches have diverged from their upstream:") + "\n\n")
+	for _, wt := range p.PushTargets {
+		if wt.Ahead > 0 && wt.Behind > 0 {
+			b.WriteString(fmt.Sprintf("  %s  %s %s\n",
+				SelectedStyle.Render(wt.Branch),
+				AheadStyle.Render(fmt.Sprintf("↑%d", wt.Ahead)),
+				BehindStyle.Render(fmt.Sprintf("↓%d", wt.Behind))))
+		}
+	}
+	b.WriteString("\nThey will be pushed with --force-with-lease, overwriting\n")
+	b.WriteString("the remote branch unless it changed since the last fetch.\n\n")
+	b.WriteString(HelpStyle.Render(p.Keys.Yes + " force push • " + p.Keys.No + " skip these • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderBranches renders the branches view (local branches without worktrees).
+func renderBranches(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	repoName := ""
+	if p.Repo != nil {
+		repoName = filepath.Base(p.Repo.MainWorktreeRoot)
+	}
+	b.WriteString(HeaderStyle.Render("BRANCHES") + "  " + PathStyle.Render(repoName) + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+
+	if p.Err != nil {
+		b.WriteString(ErrorStyle.Render("Error: "+p.Err.Error()) + "\n\n")
+	}
+
+	if p.BranchesLoading {
+		b.WriteString("\n" + p.SpinnerFrame + " Loading branches...\n")
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	if len(p.BranchDetails) == 0 {
+		b.WriteString("\n" + PathStyle.Render("Every local branch has a worktree.") + "\n")
+	} else {
+		nameWidth := 0
+		for _, br := range p.BranchDetails {
+			if w := lipgloss.Width(br.Name); w > nameWidth {
+				nameWidth = w
+			}
+		}
+		if nameWidth > 50 {
+			nameWidth = 50
+		}
+
+		startIdx := p.BranchViewOffset
+		endIdx := p.BranchViewOffset + p.VisibleCount
+		if endIdx > len(p.BranchDetails) {
+			endIdx = len(p.BranchDetails)
+		}
+		if startIdx >= len(p.BranchDetails) {
+			startIdx = 0
+		}
+
+		if startIdx > 0 {
+			b.WriteString(PathStyle.Render(fmt.Sprintf("  ↑ %d more above", startIdx)) + "\n")
+		}
+
+		for i := startIdx; i < endIdx; i++ {
+			b.WriteString(renderBranchEntry(p.BranchDetails[i], i == p.BranchCursor, nameWidth))
+			if i < endIdx-1 {
+				b.WriteString("\n")
+			}
+		}
+
+		if endIdx < len(p.BranchDetails) {
+			b.WriteString("\n" + PathStyle.Render(fmt.Sprintf("  ↓ %d more below", len(p.BranchDetails)-endIdx)))
+		}
+	}
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	helpText := compactHelp(
+		fmt.Sprintf("%s worktree • %s delete • %s rename • %s upstream • %s prune merged • esc back",
+			p.Keys.BranchCreate, p.Keys.BranchDelete, p.Keys.BranchRename, p.Keys.BranchUpstream, p.Keys.BranchPrune),
+		strings.Join([]string{p.Keys.BranchCreate, p.Keys.BranchDelete, p.Keys.BranchRename, p.Keys.BranchUpstream, p.Keys.BranchPrune, "esc"}, "•"),
+		p.Width,
+	)
+	b.WriteString(HelpStyle.Render(helpText))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderBranchEntry renders a single branch in the branches view.
+func renderBranchEntry(br git.BranchInfo, selected bool, nameWidth int) string {
+	cursor := "  "
+	name := br.Name
+	if w := lipgloss.Width(name); w < nameWidth {
+		name += strings.Repeat(" ", nameWidth-w)
+	}
+	if selected {
+		cursor = SelectedStyle.Render("› ")
+		name = SelectedStyle.Render(name)
+	} else {
+		name = BranchStyle.Render(name)
+	}
+
+	parts := []string{cursor + name, PathStyle.Render(br.LastCommitTime)}
+
+	switch {
+	case br.IsMerged:
+		parts = append(parts, MergedStyle.Render("✓ merged"))
+	case br.IsSquashMerged:
+		parts = append(parts, MergedStyle.Render("⊂ squash-merged"))
+	}
+
+	if br.UpstreamGone {
+		parts = append(parts, DangerStyle.Render("× upstream gone"))
+	} else if br.Upstream != "" {
+		parts = append(parts, RemoteTagStyle.Render(br.Upstream))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
+// renderBranchDeleteConfirm renders the delete confirmation for the branches view.
+func renderBranchDeleteConfirm(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	if p.BranchForceConfirm {
+		return renderBranchForceDeleteConfirm(p)
+	}
+
+	header := "DELETE BRANCH?"
+	if len(p.BranchDeleteTargets) > 1 {
+		header = fmt.Sprintf("DELETE %d BRANCHES?", len(p.BranchDeleteTargets))
+	}
+	b.WriteString(HeaderStyle.Render(header) + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	unmerged := false
+	for i, br := range p.BranchDeleteTargets {
+		if i >= 10 {
+			b.WriteString(fmt.Sprintf("  ... and %d more\n", len(p.BranchDeleteTargets)-10))
+			break
+		}
+		status := MergedStyle.Render("merged")
+		if br.IsSquashMerged && !br.IsMerged {
+			status = MergedStyle.Render("squash-merged")
+		} else if !br.IsStale() {
+			status = DangerStyle.Render("not merged")
+			unmerged = true
+		}
+		b.WriteString("  " + SelectedStyle.Render(br.Name) + "  " + status + "\n")
+	}
+
+	if unmerged {
+		b.WriteString("\n" + DangerStyle.Render("⚠ Unmerged commits will be lost unless they exist elsewhere.") + "\n")
+	}
+
+	b.WriteString("\n" + HelpStyle.Render(p.Keys.Yes+" delete • "+p.Keys.No+" cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderBranchForceDeleteConfirm renders the second confirmation, listing
+// the branches that git only deletes with -D.
+func renderBranchForceDeleteConfirm(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("FORCE DELETE?") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	b.WriteString("Git doesn't see these branches as merged, so they need a force delete (-D):\n\n")
+	unmerged := false
+	for _, br := range p.BranchDeleteTargets {
+		if br.IsMerged {
+			continue
+		}
+		status := MergedStyle.Render("squash-merged")
+		if !br.IsSquashMerged {
+			status = DangerStyle.Render("not merged")
+			unmerged = true
+		}
+		b.WriteString("  " + SelectedStyle.Render(br.Name) + "  " + status + "\n")
+	}
+
+	if unmerged {
+		b.WriteString("\n" + DangerStyle.Render("⚠ Unmerged commits will be lost unless they exist elsewhere.") + "\n")
+	}
+
+	b.WriteString("\n" + HelpStyle.Render(p.Keys.Yes+" force delete • "+p.Keys.No+" cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderBranchInput renders the rename and set-upstream prompts of the branches view.
+func renderBranchInput(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	header, label := "RENAME BRANCH", "New name:"
+	if p.State == StateBranchSetUpstream {
+		header, label = "SET UPSTREAM", "Upstream (remote/branch):"
+	}
+	b.WriteString(HeaderStyle.Render(header) + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.BranchCursor >= 0 && p.BranchCursor < len(p.BranchDetails) {
+		b.WriteString("Branch: " + PathStyle.Render(p.BranchDetails[p.BranchCursor].Name) + "\n\n")
+	}
+	b.WriteString(label + "\n")
+	b.WriteString(p.BranchInput + "\n")
+
+	b.WriteString("\n" + DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n")
+	b.WriteString(HelpStyle.Render("enter confirm • esc cancel"))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
+// renderPruneGoneConfirm renders the confirmation for pruning branches whose upstream is gone.
+func renderPruneGoneConfirm(p RenderParams) string {
+	var b strings.Builder
+	contentWidth := p.Width - 4
+
+	b.WriteString(HeaderStyle.Render("PRUNE GONE BRANCHES") + "\n")
+	b.WriteString(DividerStyle.Render(strings.Repeat("─", contentWidth)) + "\n\n")
+
+	if p.GoneLoading {
+		b.WriteString(p.SpinnerFrame + " Checking branches with a gone upstream...\n")
+		return wrapInBox(b.String(), p.Width, p.Height)
+	}
+
+	var prunable, skipped []git.GoneBranch
+	for _, g := range p.GoneBranches {
+		if g.SkipReason == "" {
+			prunable = append(prunable, g)
+		} else {
+			skipped = append(skipped, g)
+		}
+	}
+
+	if len(prunable) > 0 {
+		b.WriteString("These branches will be deleted, along with their worktrees:\n\n")
+		for _, g := range prunable {
+			line := "  " + SelectedStyle.Render(g.Branch)
+			if g.Worktree != nil {
+				line += "  " + PathStyle.Render(g.Worktree.ShortPath())
+				if g.Worktree.Pin > 0 {
+					line += " " + PinnedStyle.Render(SymbolPinned)
+				}
+			}
+			b.WriteString(line + "\n")
+		}
+	} else {
+		b.WriteString("Nothing can be pruned safely.\n")
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("\n" + DirtyStyle.Render("⚠ Skipped:") + "\n")
+		for _, g := range skipped {
+			b.WriteString("  " + BranchStyle.Render(g.Branch) + "  " + DangerStyle.Render(g.SkipReason) + "\n")
+		}
+	}
+
+	help := "esc close"
+	if len(prunable) > 0 {
+		help = p.Keys.Yes + " prune • " + p.Keys.No + " cancel"
+	}
+	if p.IncludePinned {
+		help += " • " + p.Keys.IncludePinned + " exclude pinned"
+	}
+	b.WriteString("\n" + HelpStyle.Render(help))
+
+	return wrapInBox(b.String(), p.Width, p.Height)
+}
+
 // renderPruneConfirm renders the prune confirmation dialog.
 func renderPruneConfirm(p RenderParams) string {
 	var b strings.Builder
@@ -859,8 +1979,42 @@ func renderPruneConfirm(p RenderParams) string {
 
 	b.WriteString("This will remove stale worktree entries (worktrees that\n")
 	b.WriteString("no longer exist on disk).\n\n")
+	var locked []string
+	for _, wt := range p.Worktrees {
+		if wt.IsLocked {
+			locked = append(locked, wt.Branch)
+		}
+	}
+	if len(locked) > 0 {
+		b.WriteString(LockedStyle.Render(fmt.Sprintf("%d locked worktree(s) will be kept:", len(locked))) + "\n")
+		for _, branch := range locked {
+			b.WriteString("  " + SymbolLocked + " " + BranchStyle.Render(branch) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	var pinned []string
+	for _, wt := range p.Worktrees {
+		if wt.Pin > 0 && wt.IsPrunable && !wt.IsLocked {
+			pinned = append(pinned, wt.Branch)
+		}
+	}
+	help := p.Keys.Yes + " confirm • " + p.Keys.No + " cancel"
+	if len(pinned) > 0 {
+		if p.IncludePinned {
+			b.WriteString(DangerStyle.Render(fmt.Sprintf("%d pinned worktree(s) will be pruned:", len(pinned))) + "\n")
+			help += " • " + p.Keys.IncludePinned + " keep pinned"
+		} else {
+			b.WriteString(PinnedStyle.Render(fmt.Sprintf("%d pinned worktree(s) will be kept:", len(pinned))) + "\n")
+			help += " • " + p.Keys.IncludePinned + " include pinned"
+		}
+		for _, branch := range pinned {
+			b.WriteString("  " + SymbolPinned + " " + BranchStyle.Render(branch) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
 	b.WriteString("Are you sure you want to prune?\n\n")
-	b.WriteString(HelpStyle.Render("y confirm • n cancel"))
+	b.WriteString(HelpStyle.Render(help))
 
 	return wrapInBox(b.String(), p.Width, p.Height)
 }
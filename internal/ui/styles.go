@@ -2,10 +2,13 @@
 package ui
 
 import (
+	"hash/fnv"
 	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/henri123lemoine/grove/internal/config"
 )
 
 // Theme represents a color theme
@@ -59,8 +62,12 @@ var lightPalette = ColorPalette{
 	Background: lipgloss.Color("255"), // White
 }
 
-// Current active palette
-var activePalette = darkPalette
+// Current active palette and the ui.theme it came from
+var (
+	activePalette   = darkPalette
+	activeThemeName = string(ThemeAuto)
+	labelColors     map[string]string // ui.label_colors
+)
 
 // Color variables - these reference the active palette
 var (
@@ -91,6 +98,8 @@ var (
 	BehindStyle      lipgloss.Style
 	UniqueStyle      lipgloss.Style
 	StashStyle       lipgloss.Style
+	LockedStyle      lipgloss.Style
+	PinnedStyle      lipgloss.Style
 	PathStyle        lipgloss.Style
 	CommitStyle      lipgloss.Style
 	HelpStyle        lipgloss.Style
@@ -116,24 +125,31 @@ const (
 	SymbolCurrent = "•"
 	SymbolDivider = "─"
 	SymbolStash   = "📦"
+	SymbolLocked  = "🔒"
+	SymbolSubmod  = "◆"
+	SymbolPinned  = "★"
 )
 
-// init initializes styles with default dark theme
+// init initializes styles with the auto-detected theme
 func init() {
-	InitTheme("auto")
+	InitTheme(nil)
 }
 
-// InitTheme initializes styles based on the theme setting
-func InitTheme(theme string) {
-	switch Theme(theme) {
-	case ThemeDark:
-		activePalette = darkPalette
-	case ThemeLight:
-		activePalette = lightPalette
-	case ThemeAuto:
-		fallthrough
-	default:
-		activePalette = detectTheme()
+// InitTheme initializes styles from ui.theme, [themes.*] and ui.colors.
+// It can be called again at any time to re-theme; a nil config selects the
+// auto-detected dark or light palette.
+func InitTheme(cfg *config.Config) {
+	var overrides config.ColorOverrides
+	activeThemeName = string(ThemeAuto)
+	activePalette = detectTheme()
+	labelColors = nil
+	if cfg != nil {
+		if cfg.UI.Theme != "" {
+			activeThemeName = cfg.UI.Theme
+		}
+		activePalette = resolvePalette(activeThemeName, cfg.Themes)
+		overrides = cfg.UI.Colors
+		labelColors = cfg.UI.LabelColors
 	}
 
 	// Set color variables from active palette
@@ -195,6 +211,12 @@ func InitTheme(theme string) {
 	StashStyle = lipgloss.NewStyle().
 		Foreground(ColorPurple)
 
+	LockedStyle = lipgloss.NewStyle().
+		Foreground(ColorWarning)
+
+	PinnedStyle = lipgloss.NewStyle().
+		Foreground(ColorHighlight)
+
 	PathStyle = lipgloss.NewStyle().
 		Foreground(ColorMuted)
 
@@ -230,6 +252,96 @@ func InitTheme(theme string) {
 
 	GitTagStyle = lipgloss.NewStyle().
 		Foreground(ColorPurple)
+
+	applyOverrides(overrides)
+}
+
+// LabelStyle returns the style for a worktree label: its ui.label_colors
+// entry, or a palette color picked from the name so a label keeps its color.
+func LabelStyle(label string) lipgloss.Style {
+	if c, ok := labelColors[label]; ok {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Bold(true)
+	}
+	colors := []lipgloss.Color{
+		activePalette.Primary, activePalette.Success, activePalette.Warning,
+		activePalette.Danger, activePalette.Highlight, activePalette.Purple,
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(label))
+	return lipgloss.NewStyle().Foreground(colors[h.Sum32()%uint32(len(colors))]).Bold(true)
+}
+
+// RenderLabels renders labels as colored #tags.
+func RenderLabels(labels []string) string {
+	tags := make([]string, len(labels))
+	for i, label := range labels {
+		tags[i] = LabelStyle(label).Render("#" + label)
+	}
+	return strings.Join(tags, " ")
+}
+
+// resolvePalette returns the palette for a theme name. User themes take
+// precedence over built-in ones and start from their base palette.
+func resolvePalette(name string, themes map[string]config.ThemeConfig) ColorPalette {
+	if theme, ok := themes[name]; ok {
+		return overlayPalette(resolvePalette(theme.Base, nil), theme)
+	}
+	if theme, ok := config.BuiltinThemes[name]; ok {
+		return overlayPalette(darkPalette, theme)
+	}
+
+	switch Theme(name) {
+	case ThemeDark:
+		return darkPalette
+	case ThemeLight:
+		return lightPalette
+	default:
+		return detectTheme()
+	}
+}
+
+// overlayPalette replaces the colors the theme sets.
+func overlayPalette(p ColorPalette, theme config.ThemeConfig) ColorPalette {
+	set := func(dst *lipgloss.Color, value string) {
+		if value != "" {
+			*dst = lipgloss.Color(value)
+		}
+	}
+	set(&p.Primary, theme.Primary)
+	set(&p.Secondary, theme.Secondary)
+	set(&p.Success, theme.Success)
+	set(&p.Warning, theme.Warning)
+	set(&p.Danger, theme.Danger)
+	set(&p.Muted, theme.Muted)
+	set(&p.Highlight, theme.Highlight)
+	set(&p.Text, theme.Text)
+	set(&p.Purple, theme.Purple)
+	set(&p.Background, theme.Background)
+	return p
+}
+
+// applyOverrides recolors individual elements after the palette is set.
+func applyOverrides(o config.ColorOverrides) {
+	if o.Dirty != "" {
+		DirtyStyle = DirtyStyle.Foreground(lipgloss.Color(o.Dirty))
+	}
+	if o.Ahead != "" {
+		AheadStyle = AheadStyle.Foreground(lipgloss.Color(o.Ahead))
+	}
+	if o.Behind != "" {
+		BehindStyle = BehindStyle.Foreground(lipgloss.Color(o.Behind))
+	}
+	if o.Danger != "" {
+		DangerStyle = DangerStyle.Foreground(lipgloss.Color(o.Danger))
+		UniqueStyle = UniqueStyle.Foreground(lipgloss.Color(o.Danger))
+		ErrorStyle = ErrorStyle.Foreground(lipgloss.Color(o.Danger))
+	}
+	if o.Selected != "" {
+		SelectedStyle = SelectedStyle.Foreground(lipgloss.Color(o.Selected))
+	}
+	if o.SelectedBg != "" {
+		SelectedStyle = SelectedStyle.Background(lipgloss.Color(o.SelectedBg))
+	}
 }
 
 // detectTheme tries to detect whether the terminal has a light or dark background
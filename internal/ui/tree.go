@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henri123lemoine/grove/internal/git"
+)
+
+// ListRow is one line of the worktree list: a worktree or, in tree mode,
+// the header of a branch-prefix group.
+type ListRow struct {
+	Index  int        // Index into the worktree slice; -1 for a group header
+	Group  *TreeGroup // Set for group headers
+	Depth  int        // Nesting level in tree mode
+	Label  string     // Branch text shown in tree mode, after the group prefix
+	Parent string     // Prefix of the enclosing group ("" at the top level)
+}
+
+// IsGroup reports whether the row is a group header.
+func (r ListRow) IsGroup() bool {
+	return r.Group != nil
+}
+
+// TreeGroup summarizes the worktrees under a branch prefix such as "feat/auth".
+type TreeGroup struct {
+	Prefix    string // Full prefix, used as the collapse key
+	Label     string // Prefix shown on the header, relative to the parent group
+	Count     int    // Worktrees in the group, including nested groups
+	Dirty     int    // Worktrees with uncommitted changes
+	Ahead     int    // Commits ahead of upstream, summed
+	Behind    int    // Commits behind upstream, summed
+	Collapsed bool
+}
+
+// treeNode is a branch-prefix group while the tree is being built. Entries
+// keep the order in which worktrees first appear, so the tree follows the
+// current sort.
+type treeNode struct {
+	name     string
+	entries  []*treeNode
+	children map[string]*treeNode
+	leaf     int // Worktree index for leaf entries, -1 for groups
+	label    string
+}
+
+func (n *treeNode) group(name string) *treeNode {
+	if child, ok := n.children[name]; ok {
+		return child
+	}
+	child := &treeNode{name: name, leaf: -1, children: make(map[string]*treeNode)}
+	n.children[name] = child
+	n.entries = append(n.entries, child)
+	return child
+}
+
+// leaves returns the worktree indexes under n.
+func (n *treeNode) leaves() []int {
+	if n.leaf >= 0 {
+		return []int{n.leaf}
+	}
+	var out []int
+	for _, e := range n.entries {
+		out = append(out, e.leaves()...)
+	}
+	return out
+}
+
+// TreeRows groups worktrees by "/"-separated branch prefix. A group holding
+// only another group is merged into one header ("feat/auth"). Rows inside
+// collapsed groups are left out.
+func TreeRows(worktrees []git.Worktree, collapsed map[string]bool) []ListRow {
+	root := &treeNode{leaf: -1, children: make(map[string]*treeNode)}
+	for i, wt := range worktrees {
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		segments := []string{branch}
+		if !wt.IsDetached {
+			segments = strings.Split(branch, "/")
+		}
+		node := root
+		for _, seg := range segments[:len(segments)-1] {
+			node = node.group(seg)
+		}
+		node.entries = append(node.entries, &treeNode{leaf: i, label: segments[len(segments)-1]})
+	}
+
+	var rows []ListRow
+	var walk func(n *treeNode, prefix string, depth int)
+	walk = func(n *treeNode, prefix string, depth int) {
+		for _, e := range n.entries {
+			if e.leaf >= 0 {
+				rows = append(rows, ListRow{
+					Index:  e.leaf,
+					Depth:  depth,
+					Label:  strings.Repeat("  ", depth) + e.label,
+					Parent: prefix,
+				})
+				continue
+			}
+
+			label := e.name
+			for len(e.entries) == 1 && e.entries[0].leaf < 0 {
+				e = e.entries[0]
+				label += "/" + e.name
+			}
+			groupPrefix := label
+			if prefix != "" {
+				groupPrefix = prefix + "/" + label
+			}
+
+			g := &TreeGroup{Prefix: groupPrefix, Label: label, Collapsed: collapsed[groupPrefix]}
+			for _, i := range e.leaves() {
+				g.Count++
+				if worktrees[i].IsDirty {
+					g.Dirty++
+				}
+				g.Ahead += worktrees[i].Ahead
+				g.Behind += worktrees[i].Behind
+			}
+			rows = append(rows, ListRow{Index: -1, Group: g, Depth: depth, Parent: prefix})
+			if !g.Collapsed {
+				walk(e, groupPrefix, depth+1)
+			}
+		}
+	}
+	walk(root, "", 0)
+	return rows
+}
+
+// renderGroupRow renders a tree group header with its rollups.
+func renderGroupRow(row ListRow, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = SelectedStyle.Render("›") + " "
+	}
+
+	arrow := "▾"
+	if row.Group.Collapsed {
+		arrow = "▸"
+	}
+	label := arrow + " " + row.Group.Label + "/"
+	if selected {
+		label = SelectedStyle.Render(label)
+	} else {
+		label = BranchStyle.Render(label)
+	}
+
+	parts := []string{
+		strings.Repeat("  ", row.Depth) + label,
+		PathStyle.Render(fmt.Sprintf("(%d)", row.Group.Count)),
+	}
+	if row.Group.Dirty > 0 {
+		parts = append(parts, DirtyStyle.Render(fmt.Sprintf("✗%d", row.Group.Dirty)))
+	}
+	if row.Group.Behind > 0 {
+		parts = append(parts, BehindStyle.Render(fmt.Sprintf("↓%d", row.Group.Behind)))
+	}
+	if row.Group.Ahead > 0 {
+		parts = append(parts, AheadStyle.Render(fmt.Sprintf("↑%d", row.Group.Ahead)))
+	}
+	return cursor + strings.Join(parts, " ")
+}
+
+// flatRows returns one row per worktree, the layout outside tree mode.
+func flatRows(n int) []ListRow {
+	rows := make([]ListRow, n)
+	for i := range rows {
+		rows[i] = ListRow{Index: i}
+	}
+	return rows
+}